@@ -8,13 +8,37 @@ import (
 	"neoway_test/internal/infrastructure/api/handlers"
 	databaseConfig "neoway_test/internal/infrastructure/database/config"
 	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	"neoway_test/internal/infrastructure/worker"
 	usecaseCreate "neoway_test/internal/usecase/customer/create"
 	usecaseDelete "neoway_test/internal/usecase/customer/delete"
+	usecaseExport "neoway_test/internal/usecase/customer/export"
 	usecaseFind "neoway_test/internal/usecase/customer/find"
+	usecaseHistory "neoway_test/internal/usecase/customer/history"
 	usecaseList "neoway_test/internal/usecase/customer/list"
+	usecasePurge "neoway_test/internal/usecase/customer/purge"
+	usecaseRestore "neoway_test/internal/usecase/customer/restore"
+	usecaseRollback "neoway_test/internal/usecase/customer/rollback"
+	usecaseUpdate "neoway_test/internal/usecase/customer/update"
+	usecaseImportJobCreate "neoway_test/internal/usecase/importjob/create"
+	usecaseImportJobFind "neoway_test/internal/usecase/importjob/find"
+	usecaseImportJobList "neoway_test/internal/usecase/importjob/list"
+	usecaseImportJobRun "neoway_test/internal/usecase/importjob/run"
+	usecasePurchaseCreate "neoway_test/internal/usecase/purchase/create"
+	usecasePurchaseList "neoway_test/internal/usecase/purchase/list"
+	usecaseStoreCustomers "neoway_test/internal/usecase/store/customers"
+	usecaseStoreFind "neoway_test/internal/usecase/store/find"
+	usecaseStoreList "neoway_test/internal/usecase/store/list"
+	usecaseUploadSessionAppend "neoway_test/internal/usecase/uploadsession/append"
+	usecaseUploadSessionCreate "neoway_test/internal/usecase/uploadsession/create"
+	usecaseUploadSessionExpire "neoway_test/internal/usecase/uploadsession/expire"
+	usecaseUploadSessionFinalize "neoway_test/internal/usecase/uploadsession/finalize"
+	usecaseUploadSessionFind "neoway_test/internal/usecase/uploadsession/find"
+	usecaseWatchFolderIngest "neoway_test/internal/usecase/watchfolder/ingest"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -73,8 +97,8 @@ func run() error {
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins: []string{"*"},
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Actor"},
 		ExposedHeaders: []string{"Link"},
 		MaxAge:         300,
 	}))
@@ -98,7 +122,39 @@ func run() error {
 		log.Fatal(err)
 	}
 
-	createCustomersBulkService := service.NewParseTxtFileService()
+	importJobRepo, err := databaseRepository.NewPostgresImportJobRepository(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	uploadSessionRepo, err := databaseRepository.NewPostgresUploadSessionRepository(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	storeRepo, err := databaseRepository.NewPostgresStoreRepository(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	purchaseRepo, err := databaseRepository.NewPostgresPurchaseRepository(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	uploadDir := os.Getenv("IMPORT_UPLOAD_DIR")
+	if uploadDir == "" {
+		uploadDir = filepath.Join(os.TempDir(), "neoway-imports")
+	}
+
+	layouts := service.NewLayoutRegistry()
+	if layoutsFile := os.Getenv("CUSTOMER_LAYOUTS_FILE"); layoutsFile != "" {
+		if err := layouts.LoadFile(layoutsFile); err != nil {
+			return fmt.Errorf("error loading file layouts: %w", err)
+		}
+	}
+
+	createCustomersBulkService := service.NewFileFormatRegistry(service.NewParseTxtFileService(layouts))
 	createCustomersService := service.NewParseService()
 
 	createCustomerUsecase := usecaseCreate.NewCreateCustomerUseCase(customerRepo, createCustomersService)
@@ -106,16 +162,123 @@ func run() error {
 	getCustomerByCpfUsecase := usecaseFind.NewGetCustomerByCpfUseCase(customerRepo)
 	getCustomerByIdUsecase := usecaseFind.NewGetCustomerByIdUseCase(customerRepo)
 	getCustomersListUsecase := usecaseList.NewGetCustomersListUseCase(customerRepo)
+	updateCustomerUsecase := usecaseUpdate.NewUpdateCustomerUseCase(customerRepo, createCustomersService)
 	deleteCustomersUsecase := usecaseDelete.NewDeleteCustomerUseCase(customerRepo)
+	restoreCustomerUsecase := usecaseRestore.NewRestoreCustomerUseCase(customerRepo)
+	customerHistoryUsecase := usecaseHistory.NewGetCustomerHistoryUseCase(customerRepo)
+	rollbackImportBatchUsecase := usecaseRollback.NewRollbackImportBatchUseCase(customerRepo)
+	exportCustomersUsecase := usecaseExport.NewExportCustomersUseCase(customerRepo, service.NewExportService(layouts))
+
+	// Lojas
+	getStoresListUsecase := usecaseStoreList.NewGetStoresListUseCase(storeRepo)
+	getStoreByCnpjUsecase := usecaseStoreFind.NewGetStoreByCnpjUseCase(storeRepo)
+	getStoreCustomersUsecase := usecaseStoreCustomers.NewGetStoreCustomersUseCase(storeRepo, customerRepo)
+
+	// Compras
+	createPurchasesUsecase := usecasePurchaseCreate.NewCreatePurchasesUseCase(purchaseRepo, customerRepo)
+	getPurchasesListUsecase := usecasePurchaseList.NewGetPurchasesListUseCase(purchaseRepo, customerRepo)
+
+	// Importação assíncrona
+	runImportJobUsecase := usecaseImportJobRun.NewRunImportJobUseCase(importJobRepo, createCustomersBulkUsecase)
+	importJobWorker := worker.NewImportJobWorker(runImportJobUsecase, 5*time.Second)
+	createImportJobUsecase := usecaseImportJobCreate.NewCreateImportJobUseCase(importJobRepo, importJobWorker, createCustomersBulkService, uploadDir)
+	getImportJobByIdUsecase := usecaseImportJobFind.NewGetImportJobByIdUseCase(importJobRepo)
+	getImportJobsListUsecase := usecaseImportJobList.NewGetImportJobsListUseCase(importJobRepo)
+
+	// Uploads em partes
+	createUploadSessionUsecase := usecaseUploadSessionCreate.NewCreateUploadSessionUseCase(uploadSessionRepo, createCustomersBulkService, uploadDir)
+	appendUploadChunkUsecase := usecaseUploadSessionAppend.NewAppendUploadChunkUseCase(uploadSessionRepo)
+	getUploadSessionByIdUsecase := usecaseUploadSessionFind.NewGetUploadSessionByIdUseCase(uploadSessionRepo)
+	finalizeUploadSessionUsecase := usecaseUploadSessionFinalize.NewFinalizeUploadSessionUseCase(uploadSessionRepo, createImportJobUsecase)
+
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	importJobWorker.Start(workerCtx)
+
+	// Expiração de uploads em partes abandonados
+	uploadSessionTTL := 24 * time.Hour
+	if ttl := os.Getenv("UPLOAD_SESSION_TTL"); ttl != "" {
+		if uploadSessionTTL, err = time.ParseDuration(ttl); err != nil || uploadSessionTTL <= 0 {
+			return fmt.Errorf("invalid UPLOAD_SESSION_TTL: %q", ttl)
+		}
+	}
+	expireUploadSessionsUsecase := usecaseUploadSessionExpire.NewExpireUploadSessionsUseCase(uploadSessionRepo, uploadSessionTTL)
+	worker.NewUploadSessionExpiryWorker(expireUploadSessionsUsecase, min(uploadSessionTTL, time.Hour)).Start(workerCtx)
+
+	// Expurgo de clientes excluídos (opcional)
+	if retention := os.Getenv("CUSTOMER_RETENTION_DAYS"); retention != "" {
+		days, err := strconv.Atoi(retention)
+		if err != nil || days < 0 {
+			return fmt.Errorf("invalid CUSTOMER_RETENTION_DAYS: %q", retention)
+		}
+
+		purgeInterval := 24 * time.Hour
+		if interval := os.Getenv("CUSTOMER_PURGE_INTERVAL"); interval != "" {
+			if purgeInterval, err = time.ParseDuration(interval); err != nil {
+				return fmt.Errorf("invalid CUSTOMER_PURGE_INTERVAL: %w", err)
+			}
+		}
+
+		purgeDeletedCustomersUsecase := usecasePurge.NewPurgeDeletedCustomersUseCase(customerRepo, time.Duration(days)*24*time.Hour)
+		worker.NewCustomerPurgeWorker(purgeDeletedCustomersUsecase, purgeInterval).Start(workerCtx)
+	}
+
+	// Pasta monitorada (opcional)
+	if watchDir := os.Getenv("IMPORT_WATCH_DIR"); watchDir != "" {
+		watchInterval := 30 * time.Second
+		if interval := os.Getenv("IMPORT_WATCH_INTERVAL"); interval != "" {
+			if watchInterval, err = time.ParseDuration(interval); err != nil {
+				return fmt.Errorf("invalid IMPORT_WATCH_INTERVAL: %w", err)
+			}
+		}
+
+		watchedFileRepo, err := databaseRepository.NewPostgresWatchedFileRepository(db)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// A file untouched for a whole interval is taken as fully uploaded.
+		ingestWatchFolderUsecase := usecaseWatchFolderIngest.NewIngestWatchFolderUseCase(watchedFileRepo, createCustomersBulkUsecase, watchDir, watchInterval)
+		if err := worker.NewWatchFolderWorker(ingestWatchFolderUsecase, watchInterval).Start(workerCtx); err != nil {
+			return fmt.Errorf("error starting watch folder: %w", err)
+		}
+	}
 
 	// Handlers HTTP
 	customerHandler := handlers.NewCustomerHandler(
 		getCustomersListUsecase,
 		createCustomerUsecase,
+		createImportJobUsecase,
 		createCustomersBulkUsecase,
 		getCustomerByCpfUsecase,
 		getCustomerByIdUsecase,
 		deleteCustomersUsecase,
+		rollbackImportBatchUsecase,
+		exportCustomersUsecase,
+		updateCustomerUsecase,
+		restoreCustomerUsecase,
+		customerHistoryUsecase,
+	)
+	importJobHandler := handlers.NewImportJobHandler(
+		getImportJobByIdUsecase,
+		getImportJobsListUsecase,
+	)
+	uploadSessionHandler := handlers.NewUploadSessionHandler(
+		createUploadSessionUsecase,
+		appendUploadChunkUsecase,
+		getUploadSessionByIdUsecase,
+		finalizeUploadSessionUsecase,
+	)
+
+	storeHandler := handlers.NewStoreHandler(
+		getStoresListUsecase,
+		getStoreByCnpjUsecase,
+		getStoreCustomersUsecase,
+	)
+
+	purchaseHandler := handlers.NewPurchaseHandler(
+		createPurchasesUsecase,
+		getPurchasesListUsecase,
 	)
 
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
@@ -130,9 +293,38 @@ func run() error {
 		r.Post("/", handlers.HandlerError(customerHandler.CustomerPost))
 		r.Post("/bulkCreation", handlers.HandlerError(customerHandler.CustomerPostBulk))
 		r.Get("/", handlers.HandlerError(customerHandler.CustomerGet))
+		r.Get("/export", handlers.HandlerError(customerHandler.CustomerExport))
 		r.Get("/getById/{id}", handlers.HandlerError(customerHandler.CustomerGetById))
 		r.Get("/getByCpf/{cpf}", handlers.HandlerError(customerHandler.CustomerGetByCpf))
+		r.Put("/{id}", handlers.HandlerError(customerHandler.CustomerPut))
+		r.Patch("/{id}", handlers.HandlerError(customerHandler.CustomerPatch))
 		r.Delete("/{id}", handlers.HandlerError(customerHandler.CustomerDelete))
+		r.Post("/{id}/restore", handlers.HandlerError(customerHandler.CustomerRestore))
+		r.Get("/{id}/history", handlers.HandlerError(customerHandler.CustomerHistory))
+		r.Post("/importBatch/{id}/rollback", handlers.HandlerError(customerHandler.CustomerRollbackBatch))
+	})
+
+	r.Route("/api/v1/store", func(r chi.Router) {
+		r.Get("/", handlers.HandlerError(storeHandler.StoreGet))
+		r.Get("/{cnpj}", handlers.HandlerError(storeHandler.StoreGetByCnpj))
+		r.Get("/{cnpj}/customers", handlers.HandlerError(storeHandler.StoreGetCustomers))
+	})
+
+	r.Route("/api/v1/purchase", func(r chi.Router) {
+		r.Post("/", handlers.HandlerError(purchaseHandler.PurchasePost))
+		r.Get("/", handlers.HandlerError(purchaseHandler.PurchaseGet))
+	})
+
+	r.Route("/api/v1/importJob", func(r chi.Router) {
+		r.Get("/", handlers.HandlerError(importJobHandler.ImportJobGet))
+		r.Get("/{id}", handlers.HandlerError(importJobHandler.ImportJobGetById))
+	})
+
+	r.Route("/api/v1/upload", func(r chi.Router) {
+		r.Post("/", handlers.HandlerError(uploadSessionHandler.UploadSessionPost))
+		r.Get("/{id}", handlers.HandlerError(uploadSessionHandler.UploadSessionGetById))
+		r.Put("/{id}", handlers.HandlerError(uploadSessionHandler.UploadSessionPut))
+		r.Post("/{id}/finalize", handlers.HandlerError(uploadSessionHandler.UploadSessionFinalize))
 	})
 
 	server := &http.Server{Addr: ":8080", Handler: r}
@@ -148,6 +340,7 @@ func run() error {
 	<-sig
 
 	log.Println("Shutting down gracefully...")
+	stopWorker()
 	if err := server.Shutdown(context.Background()); err != nil {
 		return fmt.Errorf("error during server shutdown: %w", err)
 	}
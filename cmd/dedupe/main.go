@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	databaseConfig "neoway_test/internal/infrastructure/database/config"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `Usage: dedupe [flags]
+
+Lists the customers that share a CPF, which keep the API from creating the
+unique index on it at startup. With -apply, keeps the newest customer of each
+CPF and removes the others for good, moving their purchases to the one kept.
+The database is taken from POSTGRES_FULL_URL, as in the API.
+
+Flags:
+`
+
+func main() {
+	log.SetFlags(0)
+
+	apply := flag.Bool("apply", false, "remove the older customers of each CPF instead of only listing them")
+	actor := flag.String("actor", defaultActor(), "who runs the dedupe, recorded in the audit log of every customer changed")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(*apply, *actor); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+func run(apply bool, actor string) error {
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, relying on system environment variables")
+	}
+
+	db, err := databaseConfig.Open()
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	var collisions []databaseConfig.CpfCollision
+	if apply {
+		collisions, err = databaseConfig.DedupeCpfCollisions(db, actor)
+	} else {
+		collisions, err = databaseConfig.FindCpfCollisions(db)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, collision := range collisions {
+		fmt.Printf("%s\tkept %s\tremoved %s\n", collision.Cpf, collision.IDs[0], strings.Join(collision.IDs[1:], ","))
+	}
+
+	switch {
+	case len(collisions) == 0:
+		fmt.Fprintln(os.Stderr, "no CPF is shared by more than one customer")
+	case apply:
+		fmt.Fprintf(os.Stderr, "%d CPFs deduplicated\n", len(collisions))
+	default:
+		fmt.Fprintf(os.Stderr, "%d CPFs shared by more than one customer; run again with -apply to remove the older ones\n", len(collisions))
+	}
+	return nil
+}
+
+// defaultActor names the system user running the dedupe, for the audit log.
+func defaultActor() string {
+	if current, err := user.Current(); err == nil {
+		return "dedupe:" + current.Username
+	}
+	return "dedupe"
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/repository"
+	"neoway_test/internal/domain/customer/service"
+	databaseConfig "neoway_test/internal/infrastructure/database/config"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	usecaseCreate "neoway_test/internal/usecase/customer/create"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `Usage: importer [flags] [file ...]
+
+Imports customer files straight into Postgres, without the HTTP server. With
+no file, or with "-", the file is read from stdin. The database is taken from
+POSTGRES_FULL_URL, as in the API.
+
+Flags:
+`
+
+type options struct {
+	format      string
+	layout      string
+	layoutsFile string
+	encoding    string
+	strict      bool
+	duplicates  string
+	stdinName   string
+	batchSize   int
+	dryRun      bool
+	errorReport string
+	actor       string
+}
+
+func main() {
+	log.SetFlags(0)
+
+	var opts options
+	flag.StringVar(&opts.format, "format", "", "file format (txt, csv, tsv or ndjson); detected from the file extension when empty")
+	flag.StringVar(&opts.layout, "layout", "", "fixed-width layout name (default neoway)")
+	flag.StringVar(&opts.encoding, "encoding", "", "character encoding (utf-8, windows-1252, iso-8859-1 or iso-8859-15); detected when empty")
+	flag.BoolVar(&opts.strict, "strict", false, "reject lines with malformed dates or amounts instead of storing them as NULL or 0")
+	flag.StringVar(&opts.duplicates, "duplicates", "", "policy for a CPF repeated in a file: keep_first, keep_last, reject or merge")
+	flag.StringVar(&opts.layoutsFile, "layouts-file", os.Getenv("CUSTOMER_LAYOUTS_FILE"), "YAML or JSON file with extra fixed-width layouts")
+	flag.StringVar(&opts.stdinName, "stdin-name", "stdin", "file name used for stdin, also used to detect its format")
+	flag.IntVar(&opts.batchSize, "batch-size", usecaseCreate.DefaultBulkBatchSize, "customers written to the database at once")
+	flag.BoolVar(&opts.dryRun, "dry-run", false, "parse and validate without writing to the database")
+	flag.StringVar(&opts.errorReport, "error-report", "", "write the import report, with every rejected line, as JSON to this path")
+	flag.StringVar(&opts.actor, "actor", defaultActor(), "who is importing, recorded in the audit log of every customer written")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(opts, flag.Args()); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+func run(opts options, files []string) error {
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, relying on system environment variables")
+	}
+
+	layouts := service.NewLayoutRegistry()
+	if opts.layoutsFile != "" {
+		if err := layouts.LoadFile(opts.layoutsFile); err != nil {
+			return fmt.Errorf("error loading file layouts: %w", err)
+		}
+	}
+
+	// A dry run never reaches the repository, so it does not need a database.
+	var customerRepo repository.CustomerRepository
+	if !opts.dryRun {
+		db := databaseConfig.NewDb()
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		defer sqlDB.Close()
+
+		customerRepo, err = databaseRepository.NewPostgresCustomerRepository(db)
+		if err != nil {
+			return err
+		}
+	}
+
+	createCustomersBulkUsecase := usecaseCreate.NewCreateCustomersBulkUseCase(customerRepo, service.NewFileFormatRegistry(service.NewParseTxtFileService(layouts))).
+		WithBatchSize(opts.batchSize).
+		// The error report lists every rejected line, however many.
+		WithReportLimit(0)
+
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	// A file that fails does not stop the others, which are imported in
+	// batches of their own; the failure is kept in its report entry.
+	var failures []error
+	reports := make(map[string]fileReport, len(files))
+	for _, path := range files {
+		name := path
+		if path == "-" {
+			name = opts.stdinName
+		}
+
+		report, err := importFile(createCustomersBulkUsecase, opts, path, name)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", name, err))
+			reports[name] = fileReport{Error: err.Error()}
+			continue
+		}
+		reports[name] = fileReport{OutputCreateCustomerBulkDto: &report}
+	}
+
+	if opts.errorReport != "" {
+		if err := writeReport(opts.errorReport, reports); err != nil {
+			failures = append(failures, err)
+		}
+	}
+	return errors.Join(failures...)
+}
+
+// fileReport is the -error-report entry of one file: the import report, or
+// the error that stopped the file.
+type fileReport struct {
+	*dto.OutputCreateCustomerBulkDto
+	Error string `json:"error,omitempty"`
+}
+
+func importFile(uc *usecaseCreate.CreateCustomerBulkUseCase, opts options, path string, name string) (dto.OutputCreateCustomerBulkDto, error) {
+	// Standard input cannot be read twice, so it is imported without a hash.
+	var file io.Reader = os.Stdin
+	var fileHash string
+	if path != "-" {
+		opened, err := os.Open(path)
+		if err != nil {
+			return dto.OutputCreateCustomerBulkDto{}, err
+		}
+		defer opened.Close()
+		file = opened
+
+		fileHash, err = service.HashUpload(opened)
+		if err != nil {
+			return dto.OutputCreateCustomerBulkDto{}, err
+		}
+	}
+
+	started := time.Now()
+	report, err := uc.Execute(dto.InputCreateCustomerBulkDto{
+		File:            file,
+		FileName:        name,
+		FileHash:        fileHash,
+		Format:          opts.format,
+		Layout:          opts.layout,
+		Encoding:        opts.encoding,
+		Strict:          opts.strict,
+		DuplicatePolicy: opts.duplicates,
+		DryRun:          opts.dryRun,
+		Actor:           opts.actor,
+		OnProgress: func(processed int, rejected int) {
+			fmt.Fprintf(os.Stderr, "\r%s: %d lines, %d rejected", name, processed, rejected)
+		},
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr)
+		return dto.OutputCreateCustomerBulkDto{}, err
+	}
+
+	fmt.Fprintf(os.Stderr, "\r%s: %s in %s: %d accepted (%d inserted, %d updated), %d rejected, batch %s\n",
+		name, report.Message, time.Since(started).Round(time.Millisecond), report.Accepted, report.Inserted, report.Updated, report.Rejected, report.BatchID)
+	return report, nil
+}
+
+// defaultActor names the system user running the importer, for the audit log.
+func defaultActor() string {
+	if current, err := user.Current(); err == nil {
+		return "importer:" + current.Username
+	}
+	return "importer"
+}
+
+func writeReport(path string, reports map[string]fileReport) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(reports)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -44,6 +44,20 @@ const docTemplate = `{
                         "description": "Page number",
                         "name": "page",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "digits",
+                        "description": "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)",
+                        "name": "cpf_format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Also list deleted customers",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -71,7 +85,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new customer with the provided details",
+                "description": "Create a new customer with the provided details. When a customer with the same CPF exists, its ticket, store and date fields are updated instead",
                 "consumes": [
                     "application/json"
                 ],
@@ -91,9 +105,34 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.InputCreateCustomerDto"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "default": "anonymous",
+                        "description": "Who makes the change, as claimed by the caller and not verified, recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "Updated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -111,7 +150,7 @@ const docTemplate = `{
         },
         "/api/v1/customer/bulkCreation": {
             "post": {
-                "description": "Create multiple customers from a provided file",
+                "description": "Queue an import job for the provided file. Follow its progress at /api/v1/importJob/{id}. With dry_run=true the file is only parsed and validated, synchronously, and nothing is written",
                 "consumes": [
                     "multipart/form-data"
                 ],
@@ -125,13 +164,146 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "file",
-                        "description": "CSV file with customer data",
+                        "description": "Customer file: fixed-width TXT, CSV, TSV or NDJSON, optionally gzip or zip compressed",
                         "name": "file",
                         "in": "formData",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File format (txt, csv, tsv or ndjson); detected from the content type or extension when omitted",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "neoway",
+                        "description": "Fixed-width layout name",
+                        "name": "layout",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Character encoding (utf-8, windows-1252, iso-8859-1 or iso-8859-15); taken from the file's charset or detected when omitted",
+                        "name": "encoding",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Reject lines with malformed dates or amounts instead of storing them as NULL or 0 with a warning",
+                        "name": "strict",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Policy for a CPF repeated in the file: keep_first, keep_last, reject or merge",
+                        "name": "duplicate_policy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Validate the file without importing it",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "anonymous",
+                        "description": "Who makes the change, as claimed by the caller and not verified, recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run report",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputCreateCustomerBulkDto"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputImportJobDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/customer/export": {
+            "get": {
+                "description": "Stream every customer matching the filters as CSV, TSV, NDJSON or fixed-width TXT. The file uses the columns the bulk import reads, so it can be sent back to /api/v1/customer/bulkCreation unchanged. Rows are read through a database cursor and written as they arrive",
+                "produces": [
+                    "text/csv",
+                    "text/tab-separated-values",
+                    "application/x-ndjson",
+                    "text/plain"
+                ],
+                "tags": [
+                    "Customers"
+                ],
+                "summary": "Export customers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Export format (txt, csv, tsv or ndjson)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "neoway",
+                        "description": "Fixed-width layout name for txt exports",
+                        "name": "layout",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only customers last written by this import batch",
+                        "name": "import_batch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only customers whose CPF is (true) or is not (false) valid",
+                        "name": "cpf_valido",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only customers created on or after this date (2006-01-02 or RFC 3339)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only customers created before this date (2006-01-02 or RFC 3339)",
+                        "name": "created_to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "Customer file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -149,7 +321,7 @@ const docTemplate = `{
         },
         "/api/v1/customer/getByCpf/{cpf}": {
             "get": {
-                "description": "Get details of a customer by CPF",
+                "description": "Get details of a customer by CPF, sent with or without punctuation",
                 "consumes": [
                     "application/json"
                 ],
@@ -167,6 +339,20 @@ const docTemplate = `{
                         "name": "cpf",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "digits",
+                        "description": "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)",
+                        "name": "cpf_format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Also find a deleted customer",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -176,6 +362,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.OutputGetCustomerDto"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Customer not found",
                         "schema": {
@@ -211,6 +403,20 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "digits",
+                        "description": "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)",
+                        "name": "cpf_format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Also find a deleted customer",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -220,6 +426,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.OutputGetCustomerDto"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Customer not found",
                         "schema": {
@@ -235,9 +447,134 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/customer/importBatch/{id}/rollback": {
+            "post": {
+                "description": "Delete the customers an import batch created and restore the ones it updated to their previous values, in a single transaction. The batch ID of an import job is the job ID. Customers changed again by a later import or edit are skipped",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customers"
+                ],
+                "summary": "Roll back an import batch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "anonymous",
+                        "description": "Who makes the change, as claimed by the caller and not verified, recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputRollbackImportBatchDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Import batch not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/customer/{id}": {
+            "put": {
+                "description": "Replace every field of a customer by ID, keeping its ID and creation time. Fields left out are stored as empty. The values are sanitized and validated like a new customer. The customer no longer counts as written by its import, so rolling that import back leaves it alone. For a customer with purchases, the ticket, last purchase and store fields keep the values derived from them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customers"
+                ],
+                "summary": "Replace a customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "digits",
+                        "description": "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)",
+                        "name": "cpf_format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Customer data",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.InputCreateCustomerDto"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "anonymous",
+                        "description": "Who makes the change, as claimed by the caller and not verified, recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputGetCustomerDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "CPF belongs to another customer",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
             "delete": {
-                "description": "Delete a customer by ID",
+                "description": "Delete a customer by ID. The customer is only marked as deleted and hidden from queries; it can be restored until it is purged",
                 "consumes": [
                     "application/json"
                 ],
@@ -255,6 +592,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "anonymous",
+                        "description": "Who makes the change, as claimed by the caller and not verified, recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -277,31 +621,957 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "dto.InputCreateCustomerDto": {
-            "type": "object",
-            "properties": {
-                "cpf": {
-                    "type": "string"
-                },
-                "dataUltimaCompra": {
-                    "type": "string"
-                },
-                "incompleto": {
-                    "type": "string"
-                },
-                "lojaMaisFrequente": {
-                    "type": "string"
-                },
-                "lojaUltimaCompra": {
-                    "type": "string"
-                },
+            },
+            "patch": {
+                "description": "Change the given fields of a customer by ID and keep the others. The result is sanitized and validated like a new customer. The customer no longer counts as written by its import, so rolling that import back leaves it alone. For a customer with purchases, the ticket, last purchase and store fields keep the values derived from them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customers"
+                ],
+                "summary": "Update a customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "digits",
+                        "description": "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)",
+                        "name": "cpf_format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.InputUpdateCustomerDto"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "anonymous",
+                        "description": "Who makes the change, as claimed by the caller and not verified, recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputGetCustomerDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "CPF belongs to another customer",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/customer/{id}/history": {
+            "get": {
+                "description": "Get the audit log of a customer by ID, oldest first, 100 entries per page. Each entry has the operation, who made it, the request ID and the fields it changed with their values before and after. The log is kept after the customer is purged",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customers"
+                ],
+                "summary": "List the changes to a customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "digits",
+                        "description": "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)",
+                        "name": "cpf_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.OutputCustomerAuditDto"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "No changes found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/customer/{id}/restore": {
+            "post": {
+                "description": "Bring back a deleted customer that was not purged yet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customers"
+                ],
+                "summary": "Restore a deleted customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "digits",
+                        "description": "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)",
+                        "name": "cpf_format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "anonymous",
+                        "description": "Who makes the change, as claimed by the caller and not verified, recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputGetCustomerDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Customer is not deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/importJob": {
+            "get": {
+                "description": "Get a paginated list of bulk import jobs, most recent first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ImportJobs"
+                ],
+                "summary": "List import jobs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.OutputImportJobDto"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No import jobs found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/importJob/{id}": {
+            "get": {
+                "description": "Get the state, progress and report of a bulk import job",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ImportJobs"
+                ],
+                "summary": "Get import job status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputImportJobDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Import job not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/purchase": {
+            "get": {
+                "description": "Get a paginated list of purchases, most recent first, optionally of one customer, at one store or within a date range",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchases"
+                ],
+                "summary": "List purchases",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer CPF",
+                        "name": "cpf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Store CNPJ",
+                        "name": "cnpj",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, as YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, as YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.OutputGetPurchaseDto"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer or purchases not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Record purchases of existing customers, identified by CPF. The ticket, last purchase and store fields of those customers are derived again from all their purchases, and the change is recorded in their audit log. Purchases that cannot be recorded are reported by their position in the request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchases"
+                ],
+                "summary": "Record purchases",
+                "parameters": [
+                    {
+                        "description": "Purchases",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.InputCreatePurchaseDto"
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "anonymous",
+                        "description": "Who makes the change, as claimed by the caller and not verified, recorded in the audit log",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputCreatePurchasesDto"
+                        }
+                    },
+                    "400": {
+                        "description": "No purchase was recorded",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputCreatePurchasesDto"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/store": {
+            "get": {
+                "description": "Get a paginated list of the stores customers bought from, ordered by CNPJ",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "summary": "List stores",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.OutputGetStoreDto"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No stores found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/store/{cnpj}": {
+            "get": {
+                "description": "Get a store by CNPJ, sent as digits or with its punctuation URL-encoded",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "summary": "Get store details by CNPJ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Store CNPJ",
+                        "name": "cnpj",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputGetStoreDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Store not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/store/{cnpj}/customers": {
+            "get": {
+                "description": "Get a paginated list of the customers whose most frequent or last purchase store is the given one, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "summary": "List the customers of a store",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Store CNPJ",
+                        "name": "cnpj",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only customers whose most frequent (most_frequent) or last purchase (last_purchase) store it is",
+                        "name": "relation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "digits",
+                        "description": "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)",
+                        "name": "cpf_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.OutputGetCustomersListDto"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Store or customers not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/upload": {
+            "post": {
+                "description": "Open an upload session for a customer file sent in chunks. The format and layout are the same options accepted by /api/v1/customer/bulkCreation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Uploads"
+                ],
+                "summary": "Open a resumable upload",
+                "parameters": [
+                    {
+                        "description": "Upload data",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.InputCreateUploadSessionDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputUploadSessionDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/upload/{id}": {
+            "get": {
+                "description": "Get how many bytes of the upload were received, which is the offset of the next chunk",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Uploads"
+                ],
+                "summary": "Get upload progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputUploadSessionDto"
+                        }
+                    },
+                    "404": {
+                        "description": "Upload session not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Append the request body to the upload. offset must equal the bytes already received; on a mismatch the request fails with 409 and the current offset can be read from GET /api/v1/upload/{id}",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Uploads"
+                ],
+                "summary": "Send a chunk of a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Position of the chunk in the file",
+                        "name": "offset",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputUploadSessionDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Upload session not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Offset does not match the bytes received, or another chunk is being written",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/upload/{id}/finalize": {
+            "post": {
+                "description": "Close a complete upload and queue its import job. Follow its progress at /api/v1/importJob/{id}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Uploads"
+                ],
+                "summary": "Finish a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Upload session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "anonymous",
+                        "description": "Who queues the import, as claimed by the caller and not verified, recorded in the audit log of the customers it writes",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OutputImportJobDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Upload session not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Upload already finalized, or being finalized or written by another request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.CustomerFieldWarningDto": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "file": {
+                    "description": "File is the archive member the line came from; empty for plain uploads.",
+                    "type": "string"
+                },
+                "line_number": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DuplicateCollisionDto": {
+            "type": "object",
+            "properties": {
+                "cpf": {
+                    "type": "string"
+                },
+                "file": {
+                    "description": "File is the archive member the line came from; empty for plain uploads.",
+                    "type": "string"
+                },
+                "kept_line_number": {
+                    "type": "integer"
+                },
+                "line_number": {
+                    "type": "integer"
+                },
+                "resolution": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.InputCreateCustomerDto": {
+            "type": "object",
+            "properties": {
+                "cpf": {
+                    "type": "string"
+                },
+                "dataUltimaCompra": {
+                    "type": "string"
+                },
+                "incompleto": {
+                    "type": "boolean"
+                },
+                "lojaMaisFrequente": {
+                    "type": "string"
+                },
+                "lojaUltimaCompra": {
+                    "type": "string"
+                },
                 "private": {
+                    "type": "boolean"
+                },
+                "ticketMedio": {
+                    "type": "number"
+                },
+                "ticketUltimaCompra": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.InputCreatePurchaseDto": {
+            "type": "object",
+            "properties": {
+                "cpf": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "string"
+                },
+                "loja": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.InputCreateUploadSessionDto": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "duplicate_policy": {
+                    "type": "string"
+                },
+                "encoding": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "layout": {
+                    "type": "string"
+                },
+                "strict": {
+                    "type": "boolean"
+                },
+                "total_bytes": {
+                    "description": "TotalBytes is the size of the whole file; 0 when unknown.",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.InputUpdateCustomerDto": {
+            "type": "object",
+            "properties": {
+                "cpf": {
+                    "type": "string"
+                },
+                "dataUltimaCompra": {
+                    "type": "string"
+                },
+                "incompleto": {
+                    "type": "boolean"
+                },
+                "lojaMaisFrequente": {
                     "type": "string"
                 },
+                "lojaUltimaCompra": {
+                    "type": "string"
+                },
+                "private": {
+                    "type": "boolean"
+                },
                 "ticketMedio": {
                     "type": "number"
                 },
@@ -310,6 +1580,157 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.OutputBulkSummaryDto": {
+            "type": "object",
+            "properties": {
+                "invalid_cnpjs": {
+                    "type": "integer"
+                },
+                "invalid_cpfs": {
+                    "type": "integer"
+                },
+                "null_dates": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "zero_tickets": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.OutputCreateCustomerBulkDto": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer"
+                },
+                "batch_id": {
+                    "type": "string"
+                },
+                "collision_count": {
+                    "type": "integer"
+                },
+                "collisions": {
+                    "description": "Collisions lists the first repeated CPFs resolved by the duplicate\npolicy, up to the report limit; CollisionCount counts them all.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DuplicateCollisionDto"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "duplicate_policy": {
+                    "type": "string"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OutputImportedFileDto"
+                    }
+                },
+                "inserted": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "rejected_lines": {
+                    "description": "RejectedLines lists the first rejected lines, up to the report limit;\nRejected counts them all.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RejectedCustomerLineDto"
+                    }
+                },
+                "strict": {
+                    "type": "boolean"
+                },
+                "summary": {
+                    "$ref": "#/definitions/dto.OutputBulkSummaryDto"
+                },
+                "superseded": {
+                    "description": "Superseded counts the accepted lines not written because a later line of\nthe same batch had the same CPF.",
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                },
+                "warning_count": {
+                    "type": "integer"
+                },
+                "warnings": {
+                    "description": "Warnings lists the first malformed values stored as NULL or 0, up to the\nreport limit; WarningCount counts them all.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CustomerFieldWarningDto"
+                    }
+                }
+            }
+        },
+        "dto.OutputCreatePurchasesDto": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "rejected_purchases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RejectedPurchaseDto"
+                    }
+                }
+            }
+        },
+        "dto.OutputCustomerAuditDto": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/dto.OutputFieldChangeDto"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "import_batch_id": {
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OutputFieldChangeDto": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {}
+            }
+        },
         "dto.OutputGetCustomerDto": {
             "type": "object",
             "properties": {
@@ -331,12 +1752,18 @@ const docTemplate = `{
                 "data_ultima_compra": {
                     "type": "string"
                 },
+                "deleted_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "incompleto": {
+                "import_batch_id": {
                     "type": "string"
                 },
+                "incompleto": {
+                    "type": "boolean"
+                },
                 "loja_mais_frequente": {
                     "type": "string"
                 },
@@ -344,8 +1771,17 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "private": {
+                    "type": "boolean"
+                },
+                "source_file_hash": {
                     "type": "string"
                 },
+                "source_file_name": {
+                    "type": "string"
+                },
+                "source_line_number": {
+                    "type": "integer"
+                },
                 "ticket_medio": {
                     "type": "number"
                 },
@@ -375,12 +1811,18 @@ const docTemplate = `{
                 "data_ultima_compra": {
                     "type": "string"
                 },
+                "deleted_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "incompleto": {
+                "import_batch_id": {
                     "type": "string"
                 },
+                "incompleto": {
+                    "type": "boolean"
+                },
                 "loja_mais_frequente": {
                     "type": "string"
                 },
@@ -388,8 +1830,17 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "private": {
+                    "type": "boolean"
+                },
+                "source_file_hash": {
+                    "type": "string"
+                },
+                "source_file_name": {
                     "type": "string"
                 },
+                "source_line_number": {
+                    "type": "integer"
+                },
                 "ticket_medio": {
                     "type": "number"
                 },
@@ -397,6 +1848,219 @@ const docTemplate = `{
                     "type": "number"
                 }
             }
+        },
+        "dto.OutputGetPurchaseDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "loja_cnpj": {
+                    "type": "string"
+                },
+                "valor": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.OutputGetStoreDto": {
+            "type": "object",
+            "properties": {
+                "cnpj": {
+                    "type": "string"
+                },
+                "cnpj_valido": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "nome": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OutputImportJobDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "duplicate_policy": {
+                    "type": "string"
+                },
+                "encoding": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "file_hash": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "heartbeat_at": {
+                    "description": "HeartbeatAt is when the running job last reported progress.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "layout": {
+                    "type": "string"
+                },
+                "report": {
+                    "$ref": "#/definitions/dto.OutputCreateCustomerBulkDto"
+                },
+                "rows_processed": {
+                    "type": "integer"
+                },
+                "rows_rejected": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "strict": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.OutputImportedFileDto": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rejected": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.OutputRollbackImportBatchDto": {
+            "type": "object",
+            "properties": {
+                "batch_id": {
+                    "type": "string"
+                },
+                "removed": {
+                    "description": "Removed counts the customers the batch created, now deleted.",
+                    "type": "integer"
+                },
+                "reverted": {
+                    "description": "Reverted counts the customers the batch updated, now back to their\nprevious values.",
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "Skipped counts the customers the batch updated that a later import or\nedit changed again; they are left as they are.",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.OutputUploadSessionDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "duplicate_policy": {
+                    "type": "string"
+                },
+                "encoding": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "import_job_id": {
+                    "type": "string"
+                },
+                "layout": {
+                    "type": "string"
+                },
+                "received_bytes": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "strict": {
+                    "type": "boolean"
+                },
+                "total_bytes": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RejectedCustomerLineDto": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "file": {
+                    "description": "File is the archive member the line came from; empty for plain uploads.",
+                    "type": "string"
+                },
+                "line_number": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RejectedPurchaseDto": {
+            "type": "object",
+            "properties": {
+                "index": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
         }
     },
     "externalDocs": {
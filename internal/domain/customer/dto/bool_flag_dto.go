@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidBoolean = errors.New("invalid boolean")
+
+// BoolFlag is a customer yes/no field. Besides JSON booleans it accepts the
+// spellings found in customer files: 0/1, true/false and S/N.
+type BoolFlag bool
+
+// ParseBoolFlag reads 0/1, true/false or S/N, in any case.
+func ParseBoolFlag(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "s":
+		return true, nil
+	case "0", "false", "n":
+		return false, nil
+	}
+	return false, fmt.Errorf("%w: %q", ErrInvalidBoolean, value)
+}
+
+func (f *BoolFlag) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case nil:
+		// A null flag counts as not set.
+		*f = false
+		return nil
+	case bool:
+		*f = BoolFlag(v)
+		return nil
+	case float64:
+		if v == 0 || v == 1 {
+			*f = v == 1
+			return nil
+		}
+	case string:
+		parsed, err := ParseBoolFlag(v)
+		if err != nil {
+			return err
+		}
+		*f = BoolFlag(parsed)
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidBoolean, data)
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBoolFlag(t *testing.T) {
+	for _, value := range []string{"1", "true", "TRUE", "S", "s", " 1 "} {
+		parsed, err := ParseBoolFlag(value)
+		assert.Nil(t, err, value)
+		assert.True(t, parsed, value)
+	}
+	for _, value := range []string{"0", "false", "False", "N", "n"} {
+		parsed, err := ParseBoolFlag(value)
+		assert.Nil(t, err, value)
+		assert.False(t, parsed, value)
+	}
+	for _, value := range []string{"", "2", "sim", "yes", "NULL"} {
+		_, err := ParseBoolFlag(value)
+		assert.True(t, errors.Is(err, ErrInvalidBoolean), value)
+	}
+}
+
+func TestBoolFlag_UnmarshalJSON(t *testing.T) {
+	var input struct {
+		A, B, C, D BoolFlag
+	}
+	err := json.Unmarshal([]byte(`{"A": true, "B": 1, "C": "S", "D": "0"}`), &input)
+	assert.Nil(t, err)
+	assert.Equal(t, BoolFlag(true), input.A)
+	assert.Equal(t, BoolFlag(true), input.B)
+	assert.Equal(t, BoolFlag(true), input.C)
+	assert.Equal(t, BoolFlag(false), input.D)
+
+	for _, value := range []string{`"x"`, `2`, `{}`} {
+		var flag BoolFlag
+		err := json.Unmarshal([]byte(value), &flag)
+		assert.True(t, errors.Is(err, ErrInvalidBoolean), value)
+	}
+}
@@ -0,0 +1,124 @@
+package dto
+
+import "io"
+
+type InputCreateCustomerBulkDto struct {
+	// File is read once as a stream; gzip and zip uploads are decompressed on
+	// the fly and every member of a zip is imported and reported on its own.
+	File        io.Reader
+	FileName    string
+	ContentType string
+	// Format forces the file format; when empty it is detected from
+	// ContentType and FileName.
+	Format string
+	// Layout is the fixed-width layout name; empty means the default layout.
+	Layout string
+	// Encoding is the character encoding of the file; empty means it is
+	// taken from ContentType or detected.
+	Encoding string
+	// BatchID identifies the import on every customer it writes; a new one is
+	// generated when empty.
+	BatchID string
+	// FileHash is the SHA-256 of the upload, recorded on every customer.
+	FileHash string
+	// DuplicatePolicy resolves a CPF repeated in the same file: keep_first,
+	// keep_last, reject or merge. Empty leaves it to the upsert. Every policy
+	// but keep_first reads the file twice.
+	DuplicatePolicy string
+	// Strict rejects lines with malformed dates or amounts instead of storing
+	// them as NULL or 0.
+	Strict bool
+	// DryRun parses and validates every line without touching the repository.
+	DryRun bool
+	// Actor and RequestID identify the import in the audit log.
+	Actor     string
+	RequestID string
+	// OnProgress, when set, is called after every batch with the number of
+	// lines handled so far and how many of them were rejected.
+	OnProgress func(processed int, rejected int)
+}
+
+type ParsedCustomerLineDto struct {
+	LineNumber int
+	Raw        string
+	Customer   OutputCreateCustomerDto
+	Err        error
+	// Warnings lists the values a lenient parse could not read and replaced.
+	Warnings []CustomerFieldWarningDto
+}
+
+// CustomerFieldWarningDto describes a malformed value stored as NULL or 0.
+type CustomerFieldWarningDto struct {
+	// File is the archive member the line came from; empty for plain uploads.
+	File       string `json:"file,omitempty"`
+	LineNumber int    `json:"line_number"`
+	Field      string `json:"field"`
+	Value      string `json:"value"`
+	Message    string `json:"message"`
+}
+
+type RejectedCustomerLineDto struct {
+	// File is the archive member the line came from; empty for plain uploads.
+	File       string `json:"file,omitempty"`
+	LineNumber int    `json:"line_number"`
+	Content    string `json:"content"`
+	Reason     string `json:"reason"`
+}
+
+// DuplicateCollisionDto describes an occurrence of a repeated CPF that was
+// not imported as read. KeptLineNumber is the line that was imported instead,
+// or under merge the line whose purchase was kept; it is zero when every
+// occurrence was rejected.
+type DuplicateCollisionDto struct {
+	// File is the archive member the line came from; empty for plain uploads.
+	File           string `json:"file,omitempty"`
+	Cpf            string `json:"cpf"`
+	LineNumber     int    `json:"line_number"`
+	KeptLineNumber int    `json:"kept_line_number,omitempty"`
+	Resolution     string `json:"resolution"`
+}
+
+type OutputImportedFileDto struct {
+	Name     string `json:"name"`
+	Accepted int    `json:"accepted"`
+	Rejected int    `json:"rejected"`
+}
+
+// OutputBulkSummaryDto describes the accepted lines of an import. Store
+// CNPJs given as NULL are not counted as invalid.
+type OutputBulkSummaryDto struct {
+	Rows         int `json:"rows"`
+	InvalidCpfs  int `json:"invalid_cpfs"`
+	InvalidCnpjs int `json:"invalid_cnpjs"`
+	NullDates    int `json:"null_dates"`
+	ZeroTickets  int `json:"zero_tickets"`
+}
+
+type OutputCreateCustomerBulkDto struct {
+	Message  string `json:"message"`
+	BatchID  string `json:"batch_id"`
+	Accepted int    `json:"accepted"`
+	Inserted int    `json:"inserted"`
+	Updated  int    `json:"updated"`
+	// Superseded counts the accepted lines not written because a later line of
+	// the same batch had the same CPF.
+	Superseded      int                     `json:"superseded"`
+	Rejected        int                     `json:"rejected"`
+	Duplicates      int                     `json:"duplicates"`
+	DryRun          bool                    `json:"dry_run"`
+	Strict          bool                    `json:"strict"`
+	DuplicatePolicy string                  `json:"duplicate_policy,omitempty"`
+	Summary         OutputBulkSummaryDto    `json:"summary"`
+	Files           []OutputImportedFileDto `json:"files"`
+	// RejectedLines lists the first rejected lines, up to the report limit;
+	// Rejected counts them all.
+	RejectedLines []RejectedCustomerLineDto `json:"rejected_lines"`
+	// Warnings lists the first malformed values stored as NULL or 0, up to the
+	// report limit; WarningCount counts them all.
+	Warnings     []CustomerFieldWarningDto `json:"warnings"`
+	WarningCount int                       `json:"warning_count"`
+	// Collisions lists the first repeated CPFs resolved by the duplicate
+	// policy, up to the report limit; CollisionCount counts them all.
+	Collisions     []DuplicateCollisionDto `json:"collisions"`
+	CollisionCount int                     `json:"collision_count"`
+}
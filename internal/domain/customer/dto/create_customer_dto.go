@@ -3,22 +3,25 @@ package dto
 import "time"
 
 type InputCreateCustomerDto struct {
-	Cpf                string
-	Private            string
-	Incompleto         string
-	DataUltimaCompra   string
-	TicketMedio        float64
-	TicketUltimaCompra float64
-	LojaMaisFrequente  string
-	LojaUltimaCompra   string
+	Cpf                string   `json:"cpf"`
+	Private            BoolFlag `json:"private"`
+	Incompleto         BoolFlag `json:"incompleto"`
+	DataUltimaCompra   string   `json:"dataUltimaCompra"`
+	TicketMedio        float64  `json:"ticketMedio"`
+	TicketUltimaCompra float64  `json:"ticketUltimaCompra"`
+	LojaMaisFrequente  string   `json:"lojaMaisFrequente"`
+	LojaUltimaCompra   string   `json:"lojaUltimaCompra"`
+	// Actor and RequestID identify the change in the audit log.
+	Actor     string `json:"-"`
+	RequestID string `json:"-"`
 }
 
 type OutputCreateCustomerDto struct {
 	ID                          string
 	Cpf                         string
 	CpfValido                   bool
-	Private                     string
-	Incompleto                  string
+	Private                     bool
+	Incompleto                  bool
 	DataUltimaCompra            *time.Time
 	TicketMedio                 float64
 	TicketUltimaCompra          float64
@@ -27,4 +30,6 @@ type OutputCreateCustomerDto struct {
 	LojaUltimaCompra            string
 	CnpjLojaUltimaCompraValido  bool
 	CreatedAt                   time.Time
+	// Updated is set when a customer with the same CPF already existed.
+	Updated bool
 }
@@ -2,6 +2,9 @@ package dto
 
 type InputDeleteCustomerDto struct {
 	ID string
+	// Actor and RequestID identify the change in the audit log.
+	Actor     string
+	RequestID string
 }
 
 type OutputDeleteCustomerDto struct{}
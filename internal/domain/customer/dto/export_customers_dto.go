@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type InputExportCustomersDto struct {
+	// Format is txt, csv, tsv or ndjson; empty means csv.
+	Format string
+	// Layout is the fixed-width layout used by txt exports.
+	Layout        string
+	ImportBatchID string
+	CpfValido     *bool
+	// CreatedFrom and CreatedTo bound the creation date, CreatedTo excluded.
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
+type OutputExportCustomersDto struct {
+	Format      string
+	ContentType string
+	FileName    string
+}
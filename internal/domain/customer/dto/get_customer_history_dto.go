@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type InputGetCustomerHistoryDto struct {
+	ID        string
+	Page      int
+	CpfFormat string
+}
+
+type OutputFieldChangeDto struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type OutputCustomerAuditDto struct {
+	ID            string                          `json:"id"`
+	CustomerID    string                          `json:"customer_id"`
+	Operation     string                          `json:"operation"`
+	Actor         string                          `json:"actor"`
+	RequestID     string                          `json:"request_id,omitempty"`
+	ImportBatchID string                          `json:"import_batch_id,omitempty"`
+	Changes       map[string]OutputFieldChangeDto `json:"changes"`
+	CreatedAt     time.Time                       `json:"created_at"`
+}
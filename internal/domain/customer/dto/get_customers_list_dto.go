@@ -3,15 +3,18 @@ package dto
 import "time"
 
 type InputGetCustomersListDto struct {
-	Page int
+	Page      int
+	CpfFormat string
+	// IncludeDeleted also lists deleted customers.
+	IncludeDeleted bool
 }
 
 type OutputGetCustomersListDto struct {
 	ID                          string     `json:"id"`
 	Cpf                         string     `json:"cpf"`
 	CpfValido                   bool       `json:"cpf_valido"`
-	Private                     string     `json:"private"`
-	Incompleto                  string     `json:"incompleto"`
+	Private                     bool       `json:"private"`
+	Incompleto                  bool       `json:"incompleto"`
 	DataUltimaCompra            *time.Time `json:"data_ultima_compra"`
 	TicketMedio                 float64    `json:"ticket_medio"`
 	TicketUltimaCompra          float64    `json:"ticket_ultima_compra"`
@@ -19,5 +22,10 @@ type OutputGetCustomersListDto struct {
 	CnpjLojaMaisFrequenteValido bool       `json:"cnpj_loja_mais_frequente_valido"`
 	LojaUltimaCompra            string     `json:"loja_ultima_compra"`
 	CnpjLojaUltimaCompraValido  bool       `json:"cnpj_loja_ultima_compra_valido"`
+	ImportBatchID               string     `json:"import_batch_id,omitempty"`
+	SourceFileName              string     `json:"source_file_name,omitempty"`
+	SourceFileHash              string     `json:"source_file_hash,omitempty"`
+	SourceLineNumber            int        `json:"source_line_number,omitempty"`
 	CreatedAt                   time.Time  `json:"created_at"`
+	DeletedAt                   *time.Time `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,9 @@
+package dto
+
+type InputRestoreCustomerDto struct {
+	ID        string
+	CpfFormat string
+	// Actor and RequestID identify the change in the audit log.
+	Actor     string
+	RequestID string
+}
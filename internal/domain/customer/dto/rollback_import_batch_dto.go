@@ -0,0 +1,20 @@
+package dto
+
+type InputRollbackImportBatchDto struct {
+	BatchID string
+	// Actor and RequestID identify the change in the audit log.
+	Actor     string
+	RequestID string
+}
+
+type OutputRollbackImportBatchDto struct {
+	BatchID string `json:"batch_id"`
+	// Removed counts the customers the batch created, now deleted.
+	Removed int `json:"removed"`
+	// Reverted counts the customers the batch updated, now back to their
+	// previous values.
+	Reverted int `json:"reverted"`
+	// Skipped counts the customers the batch updated that a later import or
+	// edit changed again; they are left as they are.
+	Skipped int `json:"skipped"`
+}
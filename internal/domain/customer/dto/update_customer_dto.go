@@ -0,0 +1,37 @@
+package dto
+
+// InputUpdateCustomerDto changes the customer with the given ID. Fields left
+// nil keep their stored value, so a full replacement sets all of them.
+type InputUpdateCustomerDto struct {
+	ID                 string    `json:"-"`
+	Cpf                *string   `json:"cpf"`
+	Private            *BoolFlag `json:"private"`
+	Incompleto         *BoolFlag `json:"incompleto"`
+	DataUltimaCompra   *string   `json:"dataUltimaCompra"`
+	TicketMedio        *float64  `json:"ticketMedio"`
+	TicketUltimaCompra *float64  `json:"ticketUltimaCompra"`
+	LojaMaisFrequente  *string   `json:"lojaMaisFrequente"`
+	LojaUltimaCompra   *string   `json:"lojaUltimaCompra"`
+	CpfFormat          string    `json:"-"`
+	// Actor and RequestID identify the change in the audit log.
+	Actor     string `json:"-"`
+	RequestID string `json:"-"`
+}
+
+// NewInputReplaceCustomerDto builds an update that replaces every field of
+// the customer with the given ID by the ones in input.
+func NewInputReplaceCustomerDto(id string, input InputCreateCustomerDto) InputUpdateCustomerDto {
+	return InputUpdateCustomerDto{
+		ID:                 id,
+		Cpf:                &input.Cpf,
+		Private:            &input.Private,
+		Incompleto:         &input.Incompleto,
+		DataUltimaCompra:   &input.DataUltimaCompra,
+		TicketMedio:        &input.TicketMedio,
+		TicketUltimaCompra: &input.TicketUltimaCompra,
+		LojaMaisFrequente:  &input.LojaMaisFrequente,
+		LojaUltimaCompra:   &input.LojaUltimaCompra,
+		Actor:              input.Actor,
+		RequestID:          input.RequestID,
+	}
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/klassmann/cpfcnpj"
+)
+
+const cpfLength = 11
+
+// minPaddedCpfLength is the shortest input taken as a CPF that lost leading
+// zeros; shorter digit strings are too ambiguous to pad.
+const minPaddedCpfLength = 9
+
+// CPF renderings accepted by Cpf.Render.
+const (
+	CpfFormatDigits    = "digits"
+	CpfFormatFormatted = "formatted"
+	CpfFormatMasked    = "masked"
+)
+
+var ErrUnknownCpfFormat = errors.New("unknown cpf format")
+
+// Cpf is a CPF reduced to its digits, so "026.987.379-13", "02698737913" and
+// "2698737913" (a spreadsheet that dropped the leading zero) are the same
+// value. Inputs without digits, such as "NULL", give an empty Cpf.
+type Cpf struct {
+	digits string
+}
+
+// NewCpf keeps the digits of value. Nine or ten digits are left-padded with
+// zeros to 11 when the check digits confirm the zeros were dropped; any other
+// length keeps its digits as given and is simply invalid.
+func NewCpf(value string) Cpf {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+
+	if len(digits) >= minPaddedCpfLength && len(digits) < cpfLength {
+		padded := strings.Repeat("0", cpfLength-len(digits)) + digits
+		if (Cpf{digits: padded}).IsValid() {
+			digits = padded
+		}
+	}
+	return Cpf{digits: digits}
+}
+
+// Digits is the normalized form used for storage and lookups; empty when the
+// input had no digits.
+func (c Cpf) Digits() string {
+	return c.digits
+}
+
+func (c Cpf) IsEmpty() bool {
+	return c.digits == ""
+}
+
+func (c Cpf) IsValid() bool {
+	cpf := cpfcnpj.NewCPF(c.digits)
+
+	return cpf.IsValid()
+}
+
+// Formatted renders 000.000.000-00. Values that are not 11 digits long are
+// returned as digits.
+func (c Cpf) Formatted() string {
+	if len(c.digits) != cpfLength {
+		return c.digits
+	}
+	return fmt.Sprintf("%s.%s.%s-%s", c.digits[:3], c.digits[3:6], c.digits[6:9], c.digits[9:])
+}
+
+// Masked renders ***.456.789-**, hiding the first three digits and the check
+// digits. Values that are not 11 digits long are masked entirely.
+func (c Cpf) Masked() string {
+	if len(c.digits) != cpfLength {
+		return strings.Repeat("*", len(c.digits))
+	}
+	return fmt.Sprintf("***.%s.%s-**", c.digits[3:6], c.digits[6:9])
+}
+
+// Render returns the CPF in format, which is digits, formatted or masked;
+// empty means digits.
+func (c Cpf) Render(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", CpfFormatDigits:
+		return c.digits, nil
+	case CpfFormatFormatted:
+		return c.Formatted(), nil
+	case CpfFormatMasked:
+		return c.Masked(), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownCpfFormat, format)
+}
+
+// ValidateCpfFormat reports whether format is accepted by Render.
+func ValidateCpfFormat(format string) error {
+	_, err := Cpf{}.Render(format)
+	return err
+}
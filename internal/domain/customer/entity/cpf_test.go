@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCpf(t *testing.T) {
+	assert.Equal(t, "92248810920", NewCpf("922.488.109-20").Digits())
+	assert.Equal(t, "92248810920", NewCpf(" 92248810920 ").Digits())
+	assert.Equal(t, "02698737913", NewCpf("2698737913").Digits())
+	assert.Equal(t, "123", NewCpf("123").Digits())
+	assert.Equal(t, "2698737914", NewCpf("2698737914").Digits())
+	assert.Equal(t, "", NewCpf("NULL").Digits())
+	assert.True(t, NewCpf("NULL").IsEmpty())
+}
+
+func TestCpf_IsValid(t *testing.T) {
+	assert.True(t, NewCpf("922.488.109-20").IsValid())
+	assert.True(t, NewCpf("2698737913").IsValid())
+	assert.False(t, NewCpf("123.456.789-00").IsValid())
+	assert.False(t, NewCpf("").IsValid())
+	assert.False(t, NewCpf("123").IsValid())
+}
+
+func TestCpf_Render(t *testing.T) {
+	cpf := NewCpf("12345678909")
+
+	assert.Equal(t, "123.456.789-09", cpf.Formatted())
+	assert.Equal(t, "***.456.789-**", cpf.Masked())
+
+	for format, expected := range map[string]string{
+		"":                 "12345678909",
+		CpfFormatDigits:    "12345678909",
+		CpfFormatFormatted: "123.456.789-09",
+		"MASKED":           "***.456.789-**",
+	} {
+		rendered, err := cpf.Render(format)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, rendered)
+	}
+
+	_, err := cpf.Render("hex")
+	assert.ErrorIs(t, err, ErrUnknownCpfFormat)
+}
+
+func TestCpf_RenderTooLong(t *testing.T) {
+	cpf := NewCpf("123456789012")
+
+	assert.Equal(t, "123456789012", cpf.Formatted())
+	assert.Equal(t, "************", cpf.Masked())
+	assert.False(t, cpf.IsValid())
+}
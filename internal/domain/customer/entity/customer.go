@@ -1,21 +1,25 @@
 package entity
 
 import (
+	purchaseEntity "neoway_test/internal/domain/purchase/entity"
 	shared "neoway_test/internal/domain/shared/entity"
+	storeEntity "neoway_test/internal/domain/store/entity"
 	internalerrors "neoway_test/internal/internal-errors"
 	"strings"
 	"time"
 
 	"github.com/klassmann/cpfcnpj"
 	"github.com/mozillazg/go-unidecode"
+	"gorm.io/gorm"
 )
 
 type Customer struct {
 	shared.BaseEntity
 	Cpf                         string     `json:"cpf" gorm:"size:20;not null"`
+	CpfNormalizado              string     `json:"-" gorm:"size:20;not null;default:'';uniqueIndex:idx_customers_cpf_normalizado,where:cpf_normalizado <> ''"`
 	CpfValido                   bool       `json:"cpf_valido" gorm:"not null"`
-	Private                     string     `json:"private"`
-	Incompleto                  string     `json:"incompleto"`
+	Private                     bool       `json:"private" gorm:"not null;default:false"`
+	Incompleto                  bool       `json:"incompleto" gorm:"not null;default:false"`
 	DataUltimaCompra            *time.Time `json:"data_ultima_compra"`
 	TicketMedio                 float64    `json:"ticket_medio" gorm:"type:numeric(10,2)"`
 	TicketUltimaCompra          float64    `json:"ticket_ultima_compra" gorm:"type:numeric(10,2)"`
@@ -23,12 +27,29 @@ type Customer struct {
 	CnpjLojaMaisFrequenteValido bool       `json:"cnpj_loja_mais_frequente_valido" gorm:"not null"`
 	LojaUltimaCompra            string     `json:"loja_ultima_compra" gorm:"size:20"`
 	CnpjLojaUltimaCompraValido  bool       `json:"cnpj_loja_ultima_compra_valido" gorm:"not null"`
+	// CNPJ digits of the two stores above, referencing stores.cnpj; nil when
+	// the store is missing or its CNPJ has no digits.
+	LojaMaisFrequenteCnpj  *string            `json:"-" gorm:"size:20;index"`
+	LojaUltimaCompraCnpj   *string            `json:"-" gorm:"size:20;index"`
+	LojaMaisFrequenteStore *storeEntity.Store `json:"-" gorm:"foreignKey:LojaMaisFrequenteCnpj;references:Cnpj;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	LojaUltimaCompraStore  *storeEntity.Store `json:"-" gorm:"foreignKey:LojaUltimaCompraCnpj;references:Cnpj;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	// Purchases go away with the customer.
+	Purchases []purchaseEntity.Purchase `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	// Provenance of customers loaded from a file; empty for customers created
+	// through the API.
+	ImportBatchID    string `json:"import_batch_id" gorm:"size:50;not null;default:'';index"`
+	SourceFileName   string `json:"source_file_name" gorm:"size:500;not null;default:''"`
+	SourceFileHash   string `json:"source_file_hash" gorm:"size:64;not null;default:''"`
+	SourceLineNumber int    `json:"source_line_number" gorm:"not null;default:0"`
+	// DeletedAt is set when the customer is deleted. Deleted customers are
+	// left out of queries until restored or purged.
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
 
 func NewCustomer(
 	cpf string,
-	private string,
-	incompleto string,
+	private bool,
+	incompleto bool,
 	dataUltimaCompra *time.Time,
 	ticketMedio float64,
 	ticketUltimaCompra float64,
@@ -36,14 +57,19 @@ func NewCustomer(
 	lojaUltimaCompra string,
 ) (*Customer, error) {
 
-	cpf = sanitizeInput(cpf)
+	normalized := NewCpf(cpf)
+	cpf = normalized.Digits()
+	if normalized.IsEmpty() {
+		cpf = "NULL"
+	}
 	lojaMaisFrequente = sanitizeInput(lojaMaisFrequente)
 	lojaUltimaCompra = sanitizeInput(lojaUltimaCompra)
 
 	customer := &Customer{
 		BaseEntity:                  shared.NewBaseEntity(),
 		Cpf:                         cpf,
-		CpfValido:                   validateCpf(cpf),
+		CpfNormalizado:              normalized.Digits(),
+		CpfValido:                   normalized.IsValid(),
 		Private:                     private,
 		Incompleto:                  incompleto,
 		DataUltimaCompra:            dataUltimaCompra,
@@ -53,6 +79,8 @@ func NewCustomer(
 		CnpjLojaMaisFrequenteValido: validateCnpj(lojaMaisFrequente),
 		LojaUltimaCompra:            lojaUltimaCompra,
 		CnpjLojaUltimaCompraValido:  validateCnpj(lojaUltimaCompra),
+		LojaMaisFrequenteCnpj:       storeCnpj(lojaMaisFrequente),
+		LojaUltimaCompraCnpj:        storeCnpj(lojaUltimaCompra),
 	}
 
 	err := internalerrors.ValidateStruct(customer)
@@ -63,6 +91,51 @@ func NewCustomer(
 	return customer, nil
 }
 
+// TraceTo records the import batch, file and line the customer was read from.
+func (c *Customer) TraceTo(batchID string, fileName string, fileHash string, lineNumber int) {
+	c.ImportBatchID = batchID
+	c.SourceFileName = fileName
+	c.SourceFileHash = fileHash
+	c.SourceLineNumber = lineNumber
+}
+
+// ApplyPurchases replaces the ticket, last purchase and store fields with the
+// ones derived from the customer's purchases.
+func (c *Customer) ApplyPurchases(summary purchaseEntity.PurchaseSummary) {
+	dataUltimaCompra := summary.DataUltimaCompra
+	c.DataUltimaCompra = &dataUltimaCompra
+	c.TicketMedio = summary.TicketMedio
+	c.TicketUltimaCompra = summary.TicketUltimaCompra
+	c.LojaMaisFrequente = summary.LojaMaisFrequente
+	c.CnpjLojaMaisFrequenteValido = validateCnpj(summary.LojaMaisFrequente)
+	c.LojaMaisFrequenteCnpj = storeCnpj(summary.LojaMaisFrequente)
+	c.LojaUltimaCompra = summary.LojaUltimaCompra
+	c.CnpjLojaUltimaCompraValido = validateCnpj(summary.LojaUltimaCompra)
+	c.LojaUltimaCompraCnpj = storeCnpj(summary.LojaUltimaCompra)
+}
+
+// DeletionTime returns when the customer was deleted, or nil when it was not.
+func (c *Customer) DeletionTime() *time.Time {
+	if !c.DeletedAt.Valid {
+		return nil
+	}
+	deletedAt := c.DeletedAt.Time
+	return &deletedAt
+}
+
+// RenderCpf returns the customer's CPF in format (see Cpf.Render). Customers
+// stored without a CPF keep their "NULL" placeholder.
+func (c *Customer) RenderCpf(format string) (string, error) {
+	cpf := NewCpf(c.Cpf)
+	if cpf.IsEmpty() {
+		if err := ValidateCpfFormat(format); err != nil {
+			return "", err
+		}
+		return c.Cpf, nil
+	}
+	return cpf.Render(format)
+}
+
 func sanitizeInput(input string) string {
 	result := strings.ToUpper(unidecode.Unidecode(input))
 	if result == "" {
@@ -71,10 +144,14 @@ func sanitizeInput(input string) string {
 	return result
 }
 
-func validateCpf(value string) bool {
-	cpf := cpfcnpj.NewCPF(value)
-
-	return cpf.IsValid()
+// storeCnpj returns the CNPJ a customer's store is referenced by, or nil when
+// the store has none.
+func storeCnpj(loja string) *string {
+	cnpj := storeEntity.NormalizeCnpj(loja)
+	if cnpj == "" {
+		return nil
+	}
+	return &cnpj
 }
 
 func validateCnpj(value string) bool {
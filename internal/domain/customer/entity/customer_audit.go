@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"encoding/json"
+	shared "neoway_test/internal/domain/shared/entity"
+)
+
+// Operations recorded in the audit log of a customer.
+const (
+	AuditOperationCreate   = "create"
+	AuditOperationImport   = "import"
+	AuditOperationUpdate   = "update"
+	AuditOperationDelete   = "delete"
+	AuditOperationRestore  = "restore"
+	AuditOperationRollback = "rollback"
+	AuditOperationPurge    = "purge"
+)
+
+// AuditInfo identifies who asked for a change and in which request.
+type AuditInfo struct {
+	Actor     string
+	RequestID string
+}
+
+// FieldChange holds the values of a customer field before and after a change.
+// Before is nil when the customer did not exist and After when it was purged.
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// CustomerAudit is an entry of the append-only log of changes to customers.
+// Entries are kept after the customer is purged.
+type CustomerAudit struct {
+	shared.BaseEntity
+	CustomerID    string                 `json:"customer_id" gorm:"size:50;not null;index"`
+	Operation     string                 `json:"operation" gorm:"size:20;not null"`
+	Actor         string                 `json:"actor" gorm:"size:200;not null;default:''"`
+	RequestID     string                 `json:"request_id" gorm:"size:200;not null;default:''"`
+	ImportBatchID string                 `json:"import_batch_id" gorm:"size:50;not null;default:''"`
+	Changes       map[string]FieldChange `json:"changes" gorm:"type:jsonb;serializer:json"`
+}
+
+// NewCustomerAudit records a change from before to after, either of which may
+// be nil. It returns nil when no field changed.
+func NewCustomerAudit(info AuditInfo, operation string, batchID string, before *Customer, after *Customer) *CustomerAudit {
+	changes := DiffCustomers(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	customerID := ""
+	if after != nil {
+		customerID = after.ID
+	} else if before != nil {
+		customerID = before.ID
+	}
+
+	return &CustomerAudit{
+		BaseEntity:    shared.NewBaseEntity(),
+		CustomerID:    customerID,
+		Operation:     operation,
+		Actor:         info.Actor,
+		RequestID:     info.RequestID,
+		ImportBatchID: batchID,
+		Changes:       changes,
+	}
+}
+
+// DiffCustomers compares the fields customers show in the API, keyed by their
+// JSON names. A missing customer counts as having every field empty. The ID and
+// creation time never change and are left out.
+func DiffCustomers(before *Customer, after *Customer) map[string]FieldChange {
+	beforeFields := customerFields(before)
+	afterFields := customerFields(after)
+
+	names := make(map[string]bool, len(afterFields))
+	for name := range beforeFields {
+		names[name] = true
+	}
+	for name := range afterFields {
+		names[name] = true
+	}
+
+	changes := map[string]FieldChange{}
+	for name := range names {
+		if !sameValue(beforeFields[name], afterFields[name]) {
+			changes[name] = FieldChange{Before: beforeFields[name], After: afterFields[name]}
+		}
+	}
+	return changes
+}
+
+func customerFields(customer *Customer) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if customer == nil {
+		return fields
+	}
+
+	// Customer only holds plain values, so encoding it cannot fail.
+	encoded, _ := json.Marshal(customer)
+	json.Unmarshal(encoded, &fields)
+	delete(fields, "id")
+	delete(fields, "created_at")
+	return fields
+}
+
+func sameValue(a interface{}, b interface{}) bool {
+	encodedA, _ := json.Marshal(a)
+	encodedB, _ := json.Marshal(b)
+	return string(encodedA) == string(encodedB)
+}
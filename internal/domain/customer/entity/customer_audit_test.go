@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCustomerAudit(t *testing.T) {
+	info := AuditInfo{Actor: "alice", RequestID: "host/abc-000001"}
+	before, _ := NewCustomer("922.488.109-20", false, false, nil, 10, 10, "79.379.491/0001-83", "NULL")
+	after := *before
+	after.TicketMedio = 20
+	after.LojaUltimaCompra = "79.379.491/0001-83"
+
+	audit := NewCustomerAudit(info, AuditOperationUpdate, "", before, &after)
+
+	assert.NotNil(t, audit)
+	assert.NotEmpty(t, audit.ID)
+	assert.Equal(t, before.ID, audit.CustomerID)
+	assert.Equal(t, AuditOperationUpdate, audit.Operation)
+	assert.Equal(t, "alice", audit.Actor)
+	assert.Equal(t, "host/abc-000001", audit.RequestID)
+	assert.Equal(t, map[string]FieldChange{
+		"ticket_medio":       {Before: 10.0, After: 20.0},
+		"loja_ultima_compra": {Before: "NULL", After: "79.379.491/0001-83"},
+	}, audit.Changes)
+}
+
+func TestNewCustomerAuditWithoutChanges(t *testing.T) {
+	customer, _ := NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+	same := *customer
+
+	audit := NewCustomerAudit(AuditInfo{}, AuditOperationImport, "batch-1", customer, &same)
+
+	assert.Nil(t, audit)
+}
+
+func TestDiffCustomersCreatedAndPurged(t *testing.T) {
+	customer, _ := NewCustomer("922.488.109-20", true, false, nil, 10, 10, "NULL", "NULL")
+
+	created := DiffCustomers(nil, customer)
+	assert.Equal(t, FieldChange{Before: nil, After: "92248810920"}, created["cpf"])
+	assert.Equal(t, FieldChange{Before: nil, After: true}, created["private"])
+	assert.NotContains(t, created, "id")
+	assert.NotContains(t, created, "created_at")
+	assert.NotContains(t, created, "data_ultima_compra")
+
+	purged := DiffCustomers(customer, nil)
+	assert.Equal(t, FieldChange{Before: "92248810920", After: nil}, purged["cpf"])
+	assert.Equal(t, len(created), len(purged))
+}
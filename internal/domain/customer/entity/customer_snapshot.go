@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// CustomerSnapshot keeps the values an import batch overwrote on an existing
+// customer, so the batch can be rolled back. Only the first overwrite of each
+// customer by a batch is kept, which is the state before the batch.
+type CustomerSnapshot struct {
+	ImportBatchID               string `gorm:"primaryKey;size:50"`
+	CustomerID                  string `gorm:"primaryKey;size:50"`
+	DataUltimaCompra            *time.Time
+	TicketMedio                 float64 `gorm:"type:numeric(10,2)"`
+	TicketUltimaCompra          float64 `gorm:"type:numeric(10,2)"`
+	LojaMaisFrequente           string  `gorm:"size:20"`
+	CnpjLojaMaisFrequenteValido bool    `gorm:"not null"`
+	LojaUltimaCompra            string  `gorm:"size:20"`
+	CnpjLojaUltimaCompraValido  bool    `gorm:"not null"`
+	LojaMaisFrequenteCnpj       *string `gorm:"size:20"`
+	LojaUltimaCompraCnpj        *string `gorm:"size:20"`
+	PreviousImportBatchID       string  `gorm:"size:50;not null;default:''"`
+	SourceFileName              string  `gorm:"size:500;not null;default:''"`
+	SourceFileHash              string  `gorm:"size:64;not null;default:''"`
+	SourceLineNumber            int     `gorm:"not null;default:0"`
+	DeletedAt                   *time.Time
+	CreatedAt                   time.Time `gorm:"not null"`
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	purchaseEntity "neoway_test/internal/domain/purchase/entity"
 	internalerrors "neoway_test/internal/internal-errors"
 	"testing"
 	"time"
@@ -12,8 +13,8 @@ func TestNewCustomer(t *testing.T) {
 	dataUltimaCompra := time.Date(2011, 1, 27, 0, 0, 0, 0, time.UTC)
 	customer, err := NewCustomer(
 		"922.488.109-20",
-		"0",
-		"0",
+		false,
+		false,
 		&dataUltimaCompra,
 		130.54,
 		130.54,
@@ -23,9 +24,10 @@ func TestNewCustomer(t *testing.T) {
 
 	assert.Nil(t, err)
 	assert.NotNil(t, customer)
-	assert.Equal(t, "922.488.109-20", customer.Cpf)
-	assert.Equal(t, "0", customer.Private)
-	assert.Equal(t, "0", customer.Incompleto)
+	assert.Equal(t, "92248810920", customer.Cpf)
+	assert.Equal(t, "92248810920", customer.CpfNormalizado)
+	assert.False(t, customer.Private)
+	assert.False(t, customer.Incompleto)
 	assert.True(t, customer.CpfValido)
 	assert.Equal(t, "79.379.491/0001-83", customer.LojaMaisFrequente)
 	assert.Equal(t, "79.379.491/0001-83", customer.LojaUltimaCompra)
@@ -36,11 +38,6 @@ func TestNewCustomer(t *testing.T) {
 	assert.True(t, customer.CnpjLojaUltimaCompraValido)
 }
 
-func TestValidateCpf(t *testing.T) {
-	assert.True(t, validateCpf("922.488.109-20"))
-	assert.False(t, validateCpf("123.456.789-00"))
-}
-
 func TestValidateCnpj(t *testing.T) {
 	assert.True(t, validateCnpj("79.379.491/0001-83"))
 	assert.False(t, validateCnpj("12.312.312/3123-12"))
@@ -49,8 +46,8 @@ func TestValidateCnpj(t *testing.T) {
 func TestCustomerWithNullDataUltimaCompra(t *testing.T) {
 	customer, err := NewCustomer(
 		"041.091.641-25",
-		"0",
-		"0",
+		false,
+		false,
 		nil,
 		50.00,
 		50.00,
@@ -65,8 +62,8 @@ func TestCustomerWithNullDataUltimaCompra(t *testing.T) {
 func TestInvalidCustomerCnpjValidation(t *testing.T) {
 	customer, err := NewCustomer(
 		"922.488.109-20",
-		"0",
-		"0",
+		false,
+		false,
 		nil,
 		100.00,
 		100.00,
@@ -81,7 +78,7 @@ func TestInvalidCustomerCnpjValidation(t *testing.T) {
 
 func TestEmptyCustomerFields(t *testing.T) {
 	customer, err := NewCustomer(
-		"", "", "", nil, 0.0, 0.0, "", "",
+		"", false, false, nil, 0.0, 0.0, "", "",
 	)
 	assert.Nil(t, err)
 	assert.NotNil(t, customer)
@@ -101,8 +98,8 @@ func TestCustomer_Validate_ValidCPF(t *testing.T) {
 	customer := &Customer{
 		Cpf:                         "922.488.109-20",
 		CpfValido:                   true,
-		Private:                     "1",
-		Incompleto:                  "0",
+		Private:                     true,
+		Incompleto:                  false,
 		DataUltimaCompra:            &dataUltimaCompra,
 		TicketMedio:                 130.54,
 		TicketUltimaCompra:          130.54,
@@ -125,3 +122,41 @@ func TestSanitizeInput(t *testing.T) {
 	assert.Equal(t, "NULL", sanitizeInput("NULL"))
 	assert.Equal(t, "PRIVATE", sanitizeInput("Private"))
 }
+
+func TestCustomer_RenderCpf(t *testing.T) {
+	customer, _ := NewCustomer("922.488.109-20", false, false, nil, 0, 0, "", "")
+
+	formatted, err := customer.RenderCpf(CpfFormatFormatted)
+	assert.Nil(t, err)
+	assert.Equal(t, "922.488.109-20", formatted)
+
+	empty, _ := NewCustomer("NULL", false, false, nil, 0, 0, "", "")
+	masked, err := empty.RenderCpf(CpfFormatMasked)
+	assert.Nil(t, err)
+	assert.Equal(t, "NULL", masked)
+
+	_, err = empty.RenderCpf("hex")
+	assert.ErrorIs(t, err, ErrUnknownCpfFormat)
+}
+
+func TestCustomer_ApplyPurchases(t *testing.T) {
+	customer, _ := NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+
+	customer.ApplyPurchases(purchaseEntity.PurchaseSummary{
+		TicketMedio:        75,
+		TicketUltimaCompra: 50,
+		DataUltimaCompra:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		LojaUltimaCompra:   "79379491000850",
+		LojaMaisFrequente:  "79379491000183",
+	})
+
+	assert.Equal(t, 75.0, customer.TicketMedio)
+	assert.Equal(t, 50.0, customer.TicketUltimaCompra)
+	assert.Equal(t, "2024-03-01", customer.DataUltimaCompra.Format("2006-01-02"))
+	assert.Equal(t, "79379491000183", customer.LojaMaisFrequente)
+	assert.Equal(t, "79379491000183", *customer.LojaMaisFrequenteCnpj)
+	assert.True(t, customer.CnpjLojaMaisFrequenteValido)
+	assert.Equal(t, "79379491000850", customer.LojaUltimaCompra)
+	assert.Equal(t, "79379491000850", *customer.LojaUltimaCompraCnpj)
+	assert.True(t, customer.CnpjLojaUltimaCompraValido)
+}
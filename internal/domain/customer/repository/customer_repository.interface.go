@@ -3,10 +3,66 @@ package repository
 import (
 	"neoway_test/internal/domain/customer/entity"
 	shared "neoway_test/internal/domain/shared/repository"
+	"time"
 )
 
+// CustomerFilter narrows a customer export; zero fields match everything.
+type CustomerFilter struct {
+	ImportBatchID string
+	CpfValido     *bool
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+}
+
+// Relations between a customer and a store accepted by GetByStore; empty
+// matches either.
+const (
+	StoreRelationMostFrequent = "most_frequent"
+	StoreRelationLastPurchase = "last_purchase"
+)
+
+// CustomerRepository stores customers. Delete only marks a customer as
+// deleted, which hides it from every query until it is restored or purged.
+// Every change is appended to the audit log of the customer, in the same
+// transaction where there is one.
 type CustomerRepository interface {
 	shared.RepositoryInterface[entity.Customer]
+	// WithDeleted returns a view of the repository whose queries also see
+	// deleted customers. Delete through it removes a customer for good.
+	WithDeleted() CustomerRepository
+	// WithAudit returns a view of the repository whose changes are logged as
+	// made by info.
+	WithAudit(info entity.AuditInfo) CustomerRepository
+	// GetHistory lists, 100 per page, the audit log of the customer with the
+	// given ID, oldest first. The log outlives the customer.
+	GetHistory(customerID string, page int) ([]*entity.CustomerAudit, error)
+	// Restore undeletes the customer, failing with gorm.ErrRecordNotFound when
+	// it is not deleted.
+	Restore(customer *entity.Customer) error
+	// Purge removes for good the customers deleted before deletedBefore,
+	// with their purchases and rollback snapshots, and returns how many.
+	Purge(deletedBefore time.Time) (int, error)
 	GetByCpf(cpf string) (*entity.Customer, error)
+	// GetByStore lists, 100 per page, the customers related to the store with
+	// the given CNPJ, oldest first.
+	GetByStore(cnpj string, relation string, page int) ([]*entity.Customer, error)
 	CreateBulk(customers []*entity.Customer) error
+	// Update overwrites the stored customer with the same ID, failing with
+	// gorm.ErrRecordNotFound when there is none. The fields of a customer with
+	// purchases are derived from them instead.
+	Update(customer *entity.Customer) error
+	// Upsert reports whether the customer was inserted rather than updated.
+	Upsert(customer *entity.Customer) (bool, error)
+	// UpsertBulk returns how many customers were inserted, how many updated a
+	// stored row and how many were superseded by a later customer of the batch
+	// with the same CPF, and so never written.
+	UpsertBulk(customers []*entity.Customer) (int, int, int, error)
+	// RollbackBatch soft-deletes the customers an import batch created and restores
+	// the ones it updated, returning how many were removed and reverted and how
+	// many were skipped because a later import changed them again.
+	RollbackBatch(batchID string) (int, int, int, error)
+	// Stream hands every customer matching filter to yield, oldest first,
+	// without loading them all into memory. It stops at the first error
+	// returned by yield.
+	Stream(filter CustomerFilter, yield func(*entity.Customer) error) error
 }
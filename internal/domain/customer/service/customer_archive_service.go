@@ -0,0 +1,132 @@
+package service
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+)
+
+// ArchiveMember is one file of an upload. Archived is false when the upload was
+// not compressed and Content is the upload itself.
+type ArchiveMember struct {
+	Name     string
+	Content  io.Reader
+	Archived bool
+}
+
+// ExpandUpload detects gzip and zip uploads by their magic bytes and calls each
+// with every file they contain, decompressing as a stream. Any other upload is
+// passed through as a single member. Zip archives need random access, so they
+// are read in place when file is an io.ReaderAt and io.Seeker and spooled to a
+// temporary file otherwise.
+func ExpandUpload(file io.Reader, name string, each func(ArchiveMember) error) error {
+	buffered := bufio.NewReader(file)
+	magic, _ := buffered.Peek(len(zipMagic))
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return expandGzip(buffered, name, each)
+	case bytes.HasPrefix(magic, zipMagic):
+		return expandZip(file, buffered, each)
+	default:
+		return each(ArchiveMember{Name: name, Content: buffered})
+	}
+}
+
+// IsCompressed reports whether the upload read by file starts with a gzip or
+// zip signature, without consuming it.
+func IsCompressed(file *bufio.Reader) bool {
+	magic, _ := file.Peek(len(zipMagic))
+	return bytes.HasPrefix(magic, gzipMagic) || bytes.HasPrefix(magic, zipMagic)
+}
+
+func expandGzip(file io.Reader, name string, each func(ArchiveMember) error) error {
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	memberName := reader.Name
+	if memberName == "" {
+		memberName = strings.TrimSuffix(strings.TrimSuffix(path.Base(name), ".gz"), ".gzip")
+	}
+
+	return each(ArchiveMember{Name: memberName, Content: reader, Archived: true})
+}
+
+func expandZip(original io.Reader, buffered io.Reader, each func(ArchiveMember) error) error {
+	readerAt, size, cleanup, err := randomAccess(original, buffered)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	archive, err := zip.NewReader(readerAt, size)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range archive.File {
+		if member.FileInfo().IsDir() || isArchiveMetadata(member.Name) {
+			continue
+		}
+
+		content, err := member.Open()
+		if err != nil {
+			return err
+		}
+
+		err = each(ArchiveMember{Name: member.Name, Content: content, Archived: true})
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func randomAccess(original io.Reader, buffered io.Reader) (io.ReaderAt, int64, func(), error) {
+	if seeker, ok := original.(interface {
+		io.ReaderAt
+		io.Seeker
+	}); ok {
+		size, err := seeker.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		return seeker, size, func() {}, nil
+	}
+
+	spool, err := os.CreateTemp("", "upload-*.zip")
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	cleanup := func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}
+
+	size, err := io.Copy(spool, buffered)
+	if err != nil {
+		cleanup()
+		return nil, 0, nil, err
+	}
+
+	return spool, size, cleanup, nil
+}
+
+func isArchiveMetadata(name string) bool {
+	return strings.HasPrefix(name, "__MACOSX/") || path.Base(name) == ".DS_Store"
+}
@@ -0,0 +1,77 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"neoway_test/internal/domain/customer/service"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type expandedMember struct {
+	Name     string
+	Content  string
+	Archived bool
+}
+
+func expandAll(t *testing.T, file io.Reader, name string) []expandedMember {
+	var members []expandedMember
+	err := service.ExpandUpload(file, name, func(member service.ArchiveMember) error {
+		content, err := io.ReadAll(member.Content)
+		assert.Nil(t, err)
+		members = append(members, expandedMember{member.Name, string(content), member.Archived})
+		return nil
+	})
+	assert.Nil(t, err)
+	return members
+}
+
+func zipBytes(t *testing.T, files map[string]string, order ...string) []byte {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for _, name := range order {
+		entry, err := writer.Create(name)
+		assert.Nil(t, err)
+		_, err = entry.Write([]byte(files[name]))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func TestExpandUpload_PassesPlainFileThrough(t *testing.T) {
+	members := expandAll(t, strings.NewReader("plain content"), "base.txt")
+
+	assert.Equal(t, []expandedMember{{"base.txt", "plain content", false}}, members)
+}
+
+func TestExpandUpload_DecompressesGzip(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	writer.Write([]byte("gzipped content"))
+	writer.Close()
+
+	members := expandAll(t, &buffer, "base.csv.gz")
+
+	assert.Equal(t, []expandedMember{{"base.csv", "gzipped content", true}}, members)
+}
+
+func TestExpandUpload_ImportsEveryZipMember(t *testing.T) {
+	content := zipBytes(t, map[string]string{
+		"a.txt":            "first",
+		"dir/b.csv":        "second",
+		"__MACOSX/._a.txt": "metadata",
+		"dir/.DS_Store":    "metadata",
+	}, "a.txt", "dir/b.csv", "__MACOSX/._a.txt", "dir/.DS_Store")
+
+	expected := []expandedMember{{"a.txt", "first", true}, {"dir/b.csv", "second", true}}
+
+	// Seekable uploads are read in place.
+	assert.Equal(t, expected, expandAll(t, bytes.NewReader(content), "bases.zip"))
+	// Plain streams are spooled first.
+	assert.Equal(t, expected, expandAll(t, io.MultiReader(bytes.NewReader(content)), "bases.zip"))
+}
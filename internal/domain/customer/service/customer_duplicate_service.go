@@ -0,0 +1,177 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
+	"time"
+)
+
+// Policies for a CPF that appears more than once in the same file. An empty
+// policy leaves every occurrence to the repository upsert, so the last one
+// read overwrites the others without being reported.
+const (
+	DuplicateKeepFirst = "keep_first"
+	DuplicateKeepLast  = "keep_last"
+	DuplicateReject    = "reject"
+	DuplicateMerge     = "merge"
+)
+
+// Resolutions recorded for each occurrence a policy does not import as read.
+const (
+	ResolutionDropped  = "dropped"
+	ResolutionRejected = "rejected"
+	ResolutionMerged   = "merged"
+)
+
+// DuplicateCpfLimit is how many distinct CPFs a resolver tracks for one file.
+// Each one costs around a hundred bytes, so a file reaching it holds the
+// resolver at about 100MB; larger files must be split or imported without a
+// policy.
+const DuplicateCpfLimit = 1000000
+
+var (
+	ErrUnknownDuplicatePolicy = errors.New("unknown duplicate policy")
+	ErrDuplicateCpf           = errors.New("duplicate cpf in file")
+	ErrTooManyCpfs            = fmt.Errorf("file has more than %d distinct cpfs to check for duplicates", DuplicateCpfLimit)
+)
+
+func ValidateDuplicatePolicy(policy string) error {
+	switch policy {
+	case "", DuplicateKeepFirst, DuplicateKeepLast, DuplicateReject, DuplicateMerge:
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownDuplicatePolicy, policy)
+}
+
+// cpfOccurrences is what the first pass learns about one CPF.
+type cpfOccurrences struct {
+	count        int
+	firstLine    int
+	lastLine     int
+	latestLine   int
+	latestDate   *time.Time
+	latestTicket float64
+}
+
+// DuplicateResolver applies a duplicate policy to the customers of one file.
+// Every policy except keep_first needs to know about later lines, so the file
+// is read twice: Observe is called for every customer of the first pass and
+// Resolve for every customer of the second. Customers without a CPF are never
+// treated as duplicates. Both fail with ErrTooManyCpfs past the limit of
+// distinct CPFs.
+type DuplicateResolver struct {
+	policy     string
+	limit      int
+	cpfs       map[string]*cpfOccurrences
+	firstLines map[string]int
+}
+
+func NewDuplicateResolver(policy string) *DuplicateResolver {
+	return &DuplicateResolver{policy: policy, limit: DuplicateCpfLimit, cpfs: map[string]*cpfOccurrences{}, firstLines: map[string]int{}}
+}
+
+// WithLimit changes how many distinct CPFs the resolver tracks.
+func (r *DuplicateResolver) WithLimit(limit int) *DuplicateResolver {
+	r.limit = limit
+	return r
+}
+
+// NeedsPlan reports whether Observe must see the whole file before Resolve.
+func (r *DuplicateResolver) NeedsPlan() bool {
+	switch r.policy {
+	case DuplicateKeepLast, DuplicateReject, DuplicateMerge:
+		return true
+	}
+	return false
+}
+
+// Observe records customer, read at lineNumber, during the first pass.
+func (r *DuplicateResolver) Observe(lineNumber int, customer *entity.Customer) error {
+	if customer.CpfNormalizado == "" {
+		return nil
+	}
+
+	occurrences, ok := r.cpfs[customer.CpfNormalizado]
+	if !ok {
+		if len(r.cpfs) >= r.limit {
+			return ErrTooManyCpfs
+		}
+		occurrences = &cpfOccurrences{firstLine: lineNumber, latestLine: lineNumber}
+		occurrences.latestDate = customer.DataUltimaCompra
+		occurrences.latestTicket = customer.TicketUltimaCompra
+		r.cpfs[customer.CpfNormalizado] = occurrences
+	}
+
+	occurrences.count++
+	occurrences.lastLine = lineNumber
+	if isMoreRecent(customer.DataUltimaCompra, occurrences.latestDate) {
+		occurrences.latestLine = lineNumber
+		occurrences.latestDate = customer.DataUltimaCompra
+		occurrences.latestTicket = customer.TicketUltimaCompra
+	}
+	return nil
+}
+
+// Resolve reports whether customer, read at lineNumber, should be imported and
+// describes the collision when it is part of one. Under merge the first
+// occurrence is imported with the most recent purchase date and ticket of all
+// occurrences, and the collisions point at the line that purchase came from.
+func (r *DuplicateResolver) Resolve(lineNumber int, customer *entity.Customer) (bool, *dto.DuplicateCollisionDto, error) {
+	if r.policy == "" || customer.CpfNormalizado == "" {
+		return true, nil, nil
+	}
+
+	// keep_first only needs the first line of each CPF, not the whole plan.
+	if r.policy == DuplicateKeepFirst {
+		if firstLine, seen := r.firstLines[customer.CpfNormalizado]; seen {
+			return false, newCollision(customer, lineNumber, firstLine, ResolutionDropped), nil
+		}
+		if len(r.firstLines) >= r.limit {
+			return false, nil, ErrTooManyCpfs
+		}
+		r.firstLines[customer.CpfNormalizado] = lineNumber
+		return true, nil, nil
+	}
+
+	occurrences := r.cpfs[customer.CpfNormalizado]
+	if occurrences == nil || occurrences.count < 2 {
+		return true, nil, nil
+	}
+
+	switch r.policy {
+	case DuplicateKeepLast:
+		if lineNumber == occurrences.lastLine {
+			return true, nil, nil
+		}
+		return false, newCollision(customer, lineNumber, occurrences.lastLine, ResolutionDropped), nil
+	case DuplicateReject:
+		return false, newCollision(customer, lineNumber, 0, ResolutionRejected), nil
+	default:
+		if lineNumber == occurrences.firstLine {
+			customer.DataUltimaCompra = occurrences.latestDate
+			customer.TicketUltimaCompra = occurrences.latestTicket
+			return true, nil, nil
+		}
+		return false, newCollision(customer, lineNumber, occurrences.latestLine, ResolutionMerged), nil
+	}
+}
+
+func newCollision(customer *entity.Customer, lineNumber int, keptLineNumber int, resolution string) *dto.DuplicateCollisionDto {
+	return &dto.DuplicateCollisionDto{
+		Cpf:            customer.Cpf,
+		LineNumber:     lineNumber,
+		KeptLineNumber: keptLineNumber,
+		Resolution:     resolution,
+	}
+}
+
+// isMoreRecent reports whether date is later than current; a missing date is
+// never more recent.
+func isMoreRecent(date *time.Time, current *time.Time) bool {
+	if date == nil {
+		return false
+	}
+	return current == nil || date.After(*current)
+}
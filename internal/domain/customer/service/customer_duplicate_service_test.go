@@ -0,0 +1,133 @@
+package service_test
+
+import (
+	"errors"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func occurrence(cpf string, date string, ticket float64) *entity.Customer {
+	var purchase *time.Time
+	if date != "" {
+		parsed, _ := time.Parse("2006-01-02", date)
+		purchase = &parsed
+	}
+	customer, _ := entity.NewCustomer(cpf, false, false, purchase, 100, ticket, "NULL", "NULL")
+	return customer
+}
+
+// resolve runs both passes of the resolver over customers, numbered from line 2.
+func resolve(policy string, customers []*entity.Customer) ([]int, []dto.DuplicateCollisionDto) {
+	resolver := service.NewDuplicateResolver(policy)
+	if resolver.NeedsPlan() {
+		for i, customer := range customers {
+			_ = resolver.Observe(i+2, customer)
+		}
+	}
+
+	var kept []int
+	var collisions []dto.DuplicateCollisionDto
+	for i, customer := range customers {
+		keep, collision, _ := resolver.Resolve(i+2, customer)
+		if keep {
+			kept = append(kept, i+2)
+		}
+		if collision != nil {
+			collisions = append(collisions, *collision)
+		}
+	}
+	return kept, collisions
+}
+
+func TestDuplicateResolver_Policies(t *testing.T) {
+	customers := func() []*entity.Customer {
+		return []*entity.Customer{
+			occurrence("026.987.379-13", "2011-01-20", 10),
+			occurrence("041.091.641-25", "", 0),
+			occurrence("02698737913", "2012-05-01", 20),
+			occurrence("026.987.379-13", "2011-12-31", 30),
+		}
+	}
+
+	kept, collisions := resolve("", customers())
+	assert.Equal(t, []int{2, 3, 4, 5}, kept)
+	assert.Empty(t, collisions)
+
+	kept, collisions = resolve(service.DuplicateKeepFirst, customers())
+	assert.Equal(t, []int{2, 3}, kept)
+	assert.Equal(t, []dto.DuplicateCollisionDto{
+		{Cpf: "02698737913", LineNumber: 4, KeptLineNumber: 2, Resolution: "dropped"},
+		{Cpf: "02698737913", LineNumber: 5, KeptLineNumber: 2, Resolution: "dropped"},
+	}, collisions)
+
+	kept, collisions = resolve(service.DuplicateKeepLast, customers())
+	assert.Equal(t, []int{3, 5}, kept)
+	assert.Equal(t, 5, collisions[0].KeptLineNumber)
+	assert.Len(t, collisions, 2)
+
+	kept, collisions = resolve(service.DuplicateReject, customers())
+	assert.Equal(t, []int{3}, kept)
+	assert.Len(t, collisions, 3)
+	assert.Equal(t, "rejected", collisions[0].Resolution)
+	assert.Zero(t, collisions[0].KeptLineNumber)
+}
+
+func TestDuplicateResolver_MergeKeepsMostRecentPurchase(t *testing.T) {
+	customers := []*entity.Customer{
+		occurrence("026.987.379-13", "2011-01-20", 10),
+		occurrence("026.987.379-13", "2012-05-01", 20),
+		occurrence("026.987.379-13", "", 0),
+	}
+
+	kept, collisions := resolve(service.DuplicateMerge, customers)
+
+	assert.Equal(t, []int{2}, kept)
+	assert.Equal(t, "2012-05-01", customers[0].DataUltimaCompra.Format("2006-01-02"))
+	assert.Equal(t, 20.0, customers[0].TicketUltimaCompra)
+	assert.Equal(t, []dto.DuplicateCollisionDto{
+		{Cpf: "02698737913", LineNumber: 3, KeptLineNumber: 3, Resolution: "merged"},
+		{Cpf: "02698737913", LineNumber: 4, KeptLineNumber: 3, Resolution: "merged"},
+	}, collisions)
+}
+
+func TestDuplicateResolver_Limit(t *testing.T) {
+	first := occurrence("026.987.379-13", "", 0)
+	second := occurrence("041.091.641-25", "", 0)
+
+	resolver := service.NewDuplicateResolver(service.DuplicateKeepFirst).WithLimit(1)
+	keep, _, err := resolver.Resolve(2, first)
+	assert.True(t, keep)
+	assert.Nil(t, err)
+	keep, collision, err := resolver.Resolve(3, first)
+	assert.False(t, keep)
+	assert.NotNil(t, collision)
+	assert.Nil(t, err)
+	_, _, err = resolver.Resolve(4, second)
+	assert.ErrorIs(t, err, service.ErrTooManyCpfs)
+
+	resolver = service.NewDuplicateResolver(service.DuplicateMerge).WithLimit(1)
+	assert.Nil(t, resolver.Observe(2, first))
+	assert.Nil(t, resolver.Observe(3, first))
+	assert.ErrorIs(t, resolver.Observe(4, second), service.ErrTooManyCpfs)
+}
+
+func TestDuplicateResolver_IgnoresMissingCpf(t *testing.T) {
+	kept, collisions := resolve(service.DuplicateReject, []*entity.Customer{
+		occurrence("NULL", "", 0),
+		occurrence("NULL", "", 0),
+	})
+
+	assert.Equal(t, []int{2, 3}, kept)
+	assert.Empty(t, collisions)
+}
+
+func TestValidateDuplicatePolicy(t *testing.T) {
+	assert.Nil(t, service.ValidateDuplicatePolicy(""))
+	assert.Nil(t, service.ValidateDuplicatePolicy(service.DuplicateMerge))
+	assert.True(t, errors.Is(service.ValidateDuplicatePolicy("newest"), service.ErrUnknownDuplicatePolicy))
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/transform"
+)
+
+const (
+	EncodingUtf8        = "utf-8"
+	EncodingWindows1252 = "windows-1252"
+	EncodingIso88591    = "iso-8859-1"
+	EncodingIso885915   = "iso-8859-15"
+)
+
+var ErrUnknownEncoding = errors.New("unknown character encoding")
+
+var (
+	utf8Bom = []byte{0xef, 0xbb, 0xbf}
+
+	// encodingAliases maps the names partners use to the encodings above.
+	encodingAliases = map[string]string{
+		"utf-8":        EncodingUtf8,
+		"utf8":         EncodingUtf8,
+		"windows-1252": EncodingWindows1252,
+		"cp1252":       EncodingWindows1252,
+		"iso-8859-1":   EncodingIso88591,
+		"iso8859-1":    EncodingIso88591,
+		"latin1":       EncodingIso88591,
+		"latin-1":      EncodingIso88591,
+		"iso-8859-15":  EncodingIso885915,
+		"latin9":       EncodingIso885915,
+	}
+
+	charmaps = map[string]encoding.Encoding{
+		EncodingWindows1252: charmap.Windows1252,
+		EncodingIso88591:    charmap.ISO8859_1,
+		EncodingIso885915:   charmap.ISO8859_15,
+	}
+)
+
+// encodingSniffSize is how much of a file is inspected to tell UTF-8 from a
+// legacy single-byte encoding.
+const encodingSniffSize = 64 * 1024
+
+// ResolveEncoding returns the canonical name of the encoding options ask for:
+// Encoding when set, otherwise the charset of ContentType. An empty result
+// means the encoding is detected from the content.
+func ResolveEncoding(options ParseOptions) (string, error) {
+	name := options.Encoding
+	if name == "" {
+		if _, params, err := mime.ParseMediaType(options.ContentType); err == nil {
+			name = params["charset"]
+		}
+		// us-ascii is what many clients send by default and is valid UTF-8.
+		if strings.EqualFold(name, "us-ascii") {
+			name = EncodingUtf8
+		}
+	}
+	if name == "" {
+		return "", nil
+	}
+
+	canonical, ok := encodingAliases[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownEncoding, name)
+	}
+	return canonical, nil
+}
+
+// DecodeUpload returns file as UTF-8 with a leading BOM removed and CRLF or
+// bare CR line endings turned into LF. Without an explicit encoding, files
+// whose first bytes are not valid UTF-8 are read as Windows-1252, which also
+// covers ISO-8859-1 text.
+func DecodeUpload(file io.Reader, options ParseOptions) (io.Reader, error) {
+	name, err := ResolveEncoding(options)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewReaderSize(file, encodingSniffSize)
+	if bom, _ := buffered.Peek(len(utf8Bom)); bytes.Equal(bom, utf8Bom) {
+		buffered.Discard(len(utf8Bom))
+		name = EncodingUtf8
+	}
+
+	if name == "" {
+		name = EncodingWindows1252
+		if sample, _ := buffered.Peek(encodingSniffSize); validUtf8Prefix(sample) {
+			name = EncodingUtf8
+		}
+	}
+
+	var decoded io.Reader = buffered
+	if charmap, ok := charmaps[name]; ok {
+		decoded = transform.NewReader(buffered, charmap.NewDecoder())
+	}
+
+	return transform.NewReader(decoded, lineEndingNormalizer{}), nil
+}
+
+// validUtf8Prefix reports whether sample is valid UTF-8, ignoring a rune cut in
+// half at the end of the sample.
+func validUtf8Prefix(sample []byte) bool {
+	for i := 0; i < utf8.UTFMax && len(sample) > 0; i++ {
+		if utf8.Valid(sample) {
+			return true
+		}
+		sample = sample[:len(sample)-1]
+	}
+	return utf8.Valid(sample)
+}
+
+// lineEndingNormalizer turns CRLF and bare CR line endings into LF.
+type lineEndingNormalizer struct{ transform.NopResetter }
+
+func (lineEndingNormalizer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		if nDst >= len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+
+		c := src[nSrc]
+		if c == '\r' {
+			if nSrc+1 == len(src) && !atEOF {
+				// The next byte decides whether this CR is part of a CRLF.
+				return nDst, nSrc, transform.ErrShortSrc
+			}
+			if nSrc+1 < len(src) && src[nSrc+1] == '\n' {
+				nSrc++
+			}
+			c = '\n'
+		}
+
+		dst[nDst] = c
+		nDst++
+		nSrc++
+	}
+	return nDst, nSrc, nil
+}
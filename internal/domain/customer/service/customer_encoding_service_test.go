@@ -0,0 +1,103 @@
+package service_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/service"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func decodeAll(t *testing.T, content []byte, options service.ParseOptions) string {
+	decoded, err := service.DecodeUpload(bytes.NewReader(content), options)
+	assert.Nil(t, err)
+
+	result, err := io.ReadAll(decoded)
+	assert.Nil(t, err)
+	return string(result)
+}
+
+func TestDecodeUpload_DetectsWindows1252(t *testing.T) {
+	content, _ := charmap.Windows1252.NewEncoder().Bytes([]byte("DATA DA ÚLTIMA COMPRA\nLOJA MAIS FREQUÊNTE\n"))
+
+	assert.Equal(t, "DATA DA ÚLTIMA COMPRA\nLOJA MAIS FREQUÊNTE\n", decodeAll(t, content, service.ParseOptions{}))
+}
+
+func TestDecodeUpload_KeepsUtf8AndStripsBom(t *testing.T) {
+	content := append([]byte{0xef, 0xbb, 0xbf}, []byte("TICKET MÉDIO\n")...)
+
+	assert.Equal(t, "TICKET MÉDIO\n", decodeAll(t, content, service.ParseOptions{}))
+}
+
+func TestDecodeUpload_NormalizesLineEndings(t *testing.T) {
+	content := []byte("linha 1\r\nlinha 2\rlinha 3\r")
+
+	assert.Equal(t, "linha 1\nlinha 2\nlinha 3\n", decodeAll(t, content, service.ParseOptions{}))
+}
+
+func TestDecodeUpload_NormalizesCrlfAcrossReads(t *testing.T) {
+	// Long enough for the CR and LF of some lines to land in different reads.
+	line := strings.Repeat("x", 1023) + "\r\n"
+	content := []byte(strings.Repeat(line, 20))
+
+	assert.Equal(t, strings.Repeat(strings.Repeat("x", 1023)+"\n", 20), decodeAll(t, content, service.ParseOptions{}))
+}
+
+func TestDecodeUpload_ExplicitEncoding(t *testing.T) {
+	content, _ := charmap.ISO8859_15.NewEncoder().Bytes([]byte("preço €\n"))
+
+	assert.Equal(t, "preço €\n", decodeAll(t, content, service.ParseOptions{Encoding: "latin9"}))
+	assert.Equal(t, "preço €\n", decodeAll(t, content, service.ParseOptions{ContentType: "text/plain; charset=ISO-8859-15"}))
+}
+
+func TestResolveEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  service.ParseOptions
+		expected string
+	}{
+		{"explicit encoding wins", service.ParseOptions{Encoding: "CP1252", ContentType: "text/plain; charset=utf-8"}, service.EncodingWindows1252},
+		{"charset from content type", service.ParseOptions{ContentType: "text/csv; charset=latin1"}, service.EncodingIso88591},
+		{"us-ascii is read as utf-8", service.ParseOptions{ContentType: "text/plain; charset=us-ascii"}, service.EncodingUtf8},
+		{"detected when missing", service.ParseOptions{ContentType: "text/plain"}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoding, err := service.ResolveEncoding(test.options)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, encoding)
+		})
+	}
+
+	_, err := service.ResolveEncoding(service.ParseOptions{Encoding: "ebcdic"})
+	assert.True(t, errors.Is(err, service.ErrUnknownEncoding))
+}
+
+func TestFileFormatRegistry_StreamParseWindows1252Txt(t *testing.T) {
+	fileContent := "CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA\r\n" +
+		"026.987.379-13     1           0           2011-01-20            159,31                159,31                  LOJA SÃO JOÃO       79.379.491/0001-83\r\n"
+	content, _ := charmap.Windows1252.NewEncoder().Bytes([]byte(fileContent))
+	registry := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+
+	var lines []dto.ParsedCustomerLineDto
+	err := registry.StreamParse(bytes.NewReader(content), service.ParseOptions{FileName: "base.txt"}, func(line dto.ParsedCustomerLineDto) error {
+		lines = append(lines, line)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, lines, 1)
+	assert.Nil(t, lines[0].Err)
+	// Columns are counted in characters, so the accented value does not shift
+	// the ones after it.
+	assert.True(t, lines[0].Customer.Private)
+	assert.Equal(t, 159.31, lines[0].Customer.TicketMedio)
+	assert.Equal(t, "LOJA SÃO JOÃO", lines[0].Customer.LojaMaisFrequente)
+	assert.Equal(t, "79.379.491/0001-83", lines[0].Customer.LojaUltimaCompra)
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrValueTooWide = errors.New("value does not fit the layout column")
+
+// ExportOptions selects how customers are written out.
+type ExportOptions struct {
+	// Format is txt, csv, tsv or ndjson; empty means csv.
+	Format string
+	// Layout is the fixed-width layout name; empty means DefaultLayoutName.
+	Layout string
+}
+
+// CustomerWriter encodes customers in one export format. Flush must be called
+// after the last customer, even when there were none.
+type CustomerWriter interface {
+	Write(customer *entity.Customer) error
+	Flush() error
+}
+
+var exportContentTypes = map[string]string{
+	FormatTxt:    "text/plain; charset=utf-8",
+	FormatCsv:    "text/csv; charset=utf-8",
+	FormatTsv:    "text/tab-separated-values; charset=utf-8",
+	FormatNdjson: "application/x-ndjson",
+}
+
+// exportFields lists the importable customer fields in the order delimited
+// exports write them.
+var exportFields = []string{
+	FieldCpf,
+	FieldPrivate,
+	FieldIncompleto,
+	FieldDataUltimaCompra,
+	FieldTicketMedio,
+	FieldTicketUltimaCompra,
+	FieldLojaMaisFrequente,
+	FieldLojaUltimaCompra,
+}
+
+// ExportService writes customers in the formats the bulk import reads, so an
+// export can be imported back without changes.
+type ExportService struct {
+	layouts *LayoutRegistry
+}
+
+func NewExportService(layouts *LayoutRegistry) *ExportService {
+	return &ExportService{layouts: layouts}
+}
+
+// Resolve returns the format options refer to and its content type, checking
+// the layout of fixed-width exports.
+func (s *ExportService) Resolve(options ExportOptions) (string, string, error) {
+	format := strings.ToLower(options.Format)
+	if format == "" {
+		format = FormatCsv
+	}
+
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ErrUnknownFormat, options.Format)
+	}
+
+	if format == FormatTxt {
+		if _, err := s.layouts.Get(options.Layout); err != nil {
+			return "", "", err
+		}
+	}
+
+	return format, contentType, nil
+}
+
+// NewWriter returns a CustomerWriter for options that writes to w.
+func (s *ExportService) NewWriter(w io.Writer, options ExportOptions) (CustomerWriter, error) {
+	format, _, err := s.Resolve(options)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatTxt:
+		layout, _ := s.layouts.Get(options.Layout)
+		return &fixedWidthWriter{writer: bufio.NewWriter(w), layout: layout}, nil
+	case FormatTsv:
+		return newDelimitedWriter(w, '\t'), nil
+	case FormatNdjson:
+		buffered := bufio.NewWriter(w)
+		return &ndjsonWriter{writer: buffered, encoder: json.NewEncoder(buffered)}, nil
+	default:
+		return newDelimitedWriter(w, ','), nil
+	}
+}
+
+// exportValue renders field of customer the way the parsers read it back.
+// Stored "NULL" values and missing dates are written as the field's null token.
+func exportValue(customer *entity.Customer, field LayoutField, decimalSeparator string) string {
+	nullable := func(value string) string {
+		if value == "NULL" {
+			return field.NullToken
+		}
+		return value
+	}
+
+	switch field.Name {
+	case FieldCpf:
+		return nullable(customer.Cpf)
+	case FieldPrivate:
+		return formatFlag(customer.Private)
+	case FieldIncompleto:
+		return formatFlag(customer.Incompleto)
+	case FieldDataUltimaCompra:
+		if customer.DataUltimaCompra == nil {
+			return field.NullToken
+		}
+		// Dates are parsed as midnight UTC; the database hands them back in
+		// the local time zone.
+		return customer.DataUltimaCompra.UTC().Format(field.Format)
+	case FieldTicketMedio:
+		return formatAmount(customer.TicketMedio, decimalSeparator)
+	case FieldTicketUltimaCompra:
+		return formatAmount(customer.TicketUltimaCompra, decimalSeparator)
+	case FieldLojaMaisFrequente:
+		return nullable(customer.LojaMaisFrequente)
+	case FieldLojaUltimaCompra:
+		return nullable(customer.LojaUltimaCompra)
+	}
+	return ""
+}
+
+// formatFlag writes booleans as 0 and 1, like the base file.
+func formatFlag(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+func formatAmount(value float64, decimalSeparator string) string {
+	return strings.Replace(strconv.FormatFloat(value, 'f', 2, 64), ".", decimalSeparator, 1)
+}
+
+// fixedWidthWriter writes the layout's header lines and one padded line per
+// customer, with amounts using a decimal comma like the base file.
+type fixedWidthWriter struct {
+	writer        *bufio.Writer
+	layout        Layout
+	headerWritten bool
+}
+
+func (f *fixedWidthWriter) Write(customer *entity.Customer) error {
+	if err := f.writeHeader(); err != nil {
+		return err
+	}
+
+	values := make([]string, len(f.layout.Fields))
+	for i, field := range f.layout.Fields {
+		values[i] = exportValue(customer, field, ",")
+		if field.Width > 0 && utf8.RuneCountInString(values[i]) > field.Width {
+			return fmt.Errorf("%w: %s %q is wider than %d characters", ErrValueTooWide, field.Name, values[i], field.Width)
+		}
+	}
+	return f.writeLine(values)
+}
+
+func (f *fixedWidthWriter) writeHeader() error {
+	if f.headerWritten {
+		return nil
+	}
+	f.headerWritten = true
+
+	for i := 0; i < f.layout.HeaderLines; i++ {
+		var names []string
+		if i == 0 {
+			for _, field := range f.layout.Fields {
+				names = append(names, strings.ToUpper(field.Name))
+			}
+		}
+		if err := f.writeLine(names); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLine places each value at its field's start and pads the line to the
+// layout's minimum length, so a trailing empty column is not read as a short
+// line.
+func (f *fixedWidthWriter) writeLine(values []string) error {
+	var line strings.Builder
+	length := 0
+	pad := func(to int) {
+		for ; length < to; length++ {
+			line.WriteByte(' ')
+		}
+	}
+
+	for i, value := range values {
+		pad(f.layout.Fields[i].Start)
+		line.WriteString(value)
+		length += utf8.RuneCountInString(value)
+	}
+	if len(values) > 0 {
+		pad(f.layout.MinLength)
+	}
+	line.WriteByte('\n')
+
+	_, err := f.writer.WriteString(line.String())
+	return err
+}
+
+func (f *fixedWidthWriter) Flush() error {
+	if err := f.writeHeader(); err != nil {
+		return err
+	}
+	return f.writer.Flush()
+}
+
+// delimitedWriter writes a header row named after the customer fields, which
+// the CSV parser maps back to the same fields.
+type delimitedWriter struct {
+	writer        *csv.Writer
+	fields        []LayoutField
+	headerWritten bool
+}
+
+func newDelimitedWriter(w io.Writer, delimiter rune) *delimitedWriter {
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+
+	fields := make([]LayoutField, len(exportFields))
+	for i, name := range exportFields {
+		fields[i] = LayoutField{Name: name, Type: customerFieldTypes[name], NullToken: "NULL", Format: "2006-01-02"}
+	}
+
+	return &delimitedWriter{writer: writer, fields: fields}
+}
+
+func (d *delimitedWriter) Write(customer *entity.Customer) error {
+	if err := d.writeHeader(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(d.fields))
+	for i, field := range d.fields {
+		record[i] = exportValue(customer, field, ".")
+	}
+	return d.writer.Write(record)
+}
+
+func (d *delimitedWriter) writeHeader() error {
+	if d.headerWritten {
+		return nil
+	}
+	d.headerWritten = true
+	return d.writer.Write(exportFields)
+}
+
+func (d *delimitedWriter) Flush() error {
+	if err := d.writeHeader(); err != nil {
+		return err
+	}
+	d.writer.Flush()
+	return d.writer.Error()
+}
+
+// ndjsonWriter writes one dto.InputCreateCustomerDto per line, the shape the
+// NDJSON parser reads.
+type ndjsonWriter struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(customer *entity.Customer) error {
+	dateField := LayoutField{Name: FieldDataUltimaCompra, NullToken: "NULL", Format: "2006-01-02"}
+
+	return n.encoder.Encode(dto.InputCreateCustomerDto{
+		Cpf:                customer.Cpf,
+		Private:            dto.BoolFlag(customer.Private),
+		Incompleto:         dto.BoolFlag(customer.Incompleto),
+		DataUltimaCompra:   exportValue(customer, dateField, "."),
+		TicketMedio:        customer.TicketMedio,
+		TicketUltimaCompra: customer.TicketUltimaCompra,
+		LojaMaisFrequente:  customer.LojaMaisFrequente,
+		LojaUltimaCompra:   customer.LojaUltimaCompra,
+	})
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return n.writer.Flush()
+}
@@ -0,0 +1,139 @@
+package service_test
+
+import (
+	"bytes"
+	"errors"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func exportCustomers(t *testing.T, exportService *service.ExportService, options service.ExportOptions, customers ...*entity.Customer) string {
+	var out bytes.Buffer
+	writer, err := exportService.NewWriter(&out, options)
+	assert.Nil(t, err)
+
+	for _, customer := range customers {
+		assert.Nil(t, writer.Write(customer))
+	}
+	assert.Nil(t, writer.Flush())
+	return out.String()
+}
+
+func exportFixtures() []*entity.Customer {
+	// The database returns dates in the local time zone.
+	date := time.Date(2011, 1, 20, 0, 0, 0, 0, time.UTC).In(time.FixedZone("BRT", -3*60*60))
+	full, _ := entity.NewCustomer("026.987.379-13", false, false, &date, 159.31, 1200, "79.379.491/0001-83", "79.379.491/0001-83")
+	empty, _ := entity.NewCustomer("041.091.641-25", true, true, nil, 0, 0, "NULL", "NULL")
+	return []*entity.Customer{full, empty}
+}
+
+func TestExportService_RoundTrip(t *testing.T) {
+	layouts := service.NewLayoutRegistry()
+	exportService := service.NewExportService(layouts)
+	registry := service.NewFileFormatRegistry(service.NewParseTxtFileService(layouts))
+	customers := exportFixtures()
+
+	for _, format := range []string{service.FormatTxt, service.FormatCsv, service.FormatTsv, service.FormatNdjson} {
+		t.Run(format, func(t *testing.T) {
+			content := exportCustomers(t, exportService, service.ExportOptions{Format: format}, customers...)
+
+			var lines []dto.ParsedCustomerLineDto
+			err := registry.StreamParse(strings.NewReader(content), service.ParseOptions{Format: format, Strict: true}, func(line dto.ParsedCustomerLineDto) error {
+				lines = append(lines, line)
+				return nil
+			})
+
+			assert.Nil(t, err)
+			assert.Len(t, lines, len(customers))
+			for i, line := range lines {
+				customer := customers[i]
+				assert.Nil(t, line.Err)
+				assert.Empty(t, line.Warnings)
+				assert.Equal(t, customer.Cpf, line.Customer.Cpf)
+				assert.Equal(t, customer.Private, line.Customer.Private)
+				assert.Equal(t, customer.Incompleto, line.Customer.Incompleto)
+				assert.Equal(t, customer.TicketMedio, line.Customer.TicketMedio)
+				assert.Equal(t, customer.TicketUltimaCompra, line.Customer.TicketUltimaCompra)
+				assert.Equal(t, customer.LojaMaisFrequente, line.Customer.LojaMaisFrequente)
+				assert.Equal(t, customer.LojaUltimaCompra, line.Customer.LojaUltimaCompra)
+				if customer.DataUltimaCompra == nil {
+					assert.Nil(t, line.Customer.DataUltimaCompra)
+				} else {
+					assert.True(t, customer.DataUltimaCompra.Equal(*line.Customer.DataUltimaCompra))
+				}
+			}
+		})
+	}
+}
+
+func TestExportService_FixedWidthMatchesLayout(t *testing.T) {
+	exportService := service.NewExportService(service.NewLayoutRegistry())
+
+	content := exportCustomers(t, exportService, service.ExportOptions{Format: service.FormatTxt}, exportFixtures()...)
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "CPF                PRIVATE"))
+	assert.Equal(t, "02698737913        0           0           2011-01-20            159,31                1200,00                 79.379.491/0001-83  79.379.491/0001-83", lines[1])
+	assert.Equal(t, "04109164125        1           1           NULL                  0,00                  0,00                    NULL                NULL", lines[2])
+}
+
+func TestExportService_EmptyExportKeepsHeader(t *testing.T) {
+	exportService := service.NewExportService(service.NewLayoutRegistry())
+
+	assert.Equal(t, "cpf,private,incompleto,data_ultima_compra,ticket_medio,ticket_ultima_compra,loja_mais_frequente,loja_ultima_compra\n",
+		exportCustomers(t, exportService, service.ExportOptions{}))
+	assert.Empty(t, exportCustomers(t, exportService, service.ExportOptions{Format: service.FormatNdjson}))
+}
+
+func TestExportService_CustomLayout(t *testing.T) {
+	layouts := service.NewLayoutRegistry()
+	err := layouts.Register(service.Layout{
+		Name: "parceiro",
+		Fields: []service.LayoutField{
+			{Name: service.FieldCpf, Start: 0, Width: 14},
+			{Name: service.FieldDataUltimaCompra, Start: 15, Width: 8, Format: "02012006", NullToken: "-"},
+		},
+	})
+	assert.Nil(t, err)
+	exportService := service.NewExportService(layouts)
+
+	content := exportCustomers(t, exportService, service.ExportOptions{Format: service.FormatTxt, Layout: "parceiro"}, exportFixtures()...)
+	assert.Equal(t, "02698737913    20012011\n04109164125    -\n", content)
+
+	_, err = exportService.NewWriter(&bytes.Buffer{}, service.ExportOptions{Format: service.FormatTxt, Layout: "desconhecido"})
+	assert.True(t, errors.Is(err, service.ErrUnknownLayout))
+}
+
+func TestExportService_ValueTooWide(t *testing.T) {
+	layouts := service.NewLayoutRegistry()
+	layouts.Register(service.Layout{
+		Name:   "curto",
+		Fields: []service.LayoutField{{Name: service.FieldCpf, Start: 0, Width: 10}, {Name: service.FieldPrivate, Start: 10, Width: 1}},
+	})
+	exportService := service.NewExportService(layouts)
+
+	writer, err := exportService.NewWriter(&bytes.Buffer{}, service.ExportOptions{Format: service.FormatTxt, Layout: "curto"})
+	assert.Nil(t, err)
+
+	err = writer.Write(exportFixtures()[0])
+	assert.True(t, errors.Is(err, service.ErrValueTooWide))
+}
+
+func TestExportService_UnknownFormat(t *testing.T) {
+	exportService := service.NewExportService(service.NewLayoutRegistry())
+
+	_, _, err := exportService.Resolve(service.ExportOptions{Format: "xlsx"})
+	assert.True(t, errors.Is(err, service.ErrUnknownFormat))
+
+	format, contentType, err := exportService.Resolve(service.ExportOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, service.FormatCsv, format)
+	assert.Equal(t, "text/csv; charset=utf-8", contentType)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"neoway_test/internal/domain/customer/dto"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	FormatTxt    = "txt"
+	FormatCsv    = "csv"
+	FormatTsv    = "tsv"
+	FormatNdjson = "ndjson"
+)
+
+var ErrUnknownFormat = errors.New("unknown file format")
+
+// ParseOptions selects how an uploaded file is read.
+type ParseOptions struct {
+	// Format forces a format; when empty it is picked from ContentType, then
+	// from the FileName extension, falling back to fixed-width TXT.
+	Format      string
+	ContentType string
+	FileName    string
+	// Layout is the fixed-width layout name; empty means DefaultLayoutName.
+	Layout string
+	// Encoding is the character encoding of the file; when empty it is taken
+	// from the ContentType charset or detected from the content.
+	Encoding string
+	// Strict turns malformed dates and amounts into line errors. Otherwise
+	// they are stored as NULL or 0 and reported as warnings.
+	Strict bool
+}
+
+// FileParser streams customers out of a file in one specific format.
+type FileParser interface {
+	StreamParse(file io.Reader, options ParseOptions, yield func(dto.ParsedCustomerLineDto) error) error
+}
+
+type fileFormat struct {
+	parser       FileParser
+	extensions   []string
+	contentTypes []string
+}
+
+// FileFormatRegistry picks the parser for an upload and delegates to it.
+type FileFormatRegistry struct {
+	formats map[string]fileFormat
+}
+
+// NewFileFormatRegistry returns a registry with fixed-width TXT, CSV, TSV and
+// NDJSON registered.
+func NewFileFormatRegistry(parseTxtFileService *ParseTxtFileService) *FileFormatRegistry {
+	registry := &FileFormatRegistry{formats: map[string]fileFormat{}}
+
+	registry.Register(FormatTxt, parseTxtFileService, []string{".txt"}, nil)
+	registry.Register(FormatCsv, NewParseCsvFileService(','), []string{".csv"}, []string{"text/csv", "application/csv"})
+	registry.Register(FormatTsv, NewParseCsvFileService('\t'), []string{".tsv", ".tab"}, []string{"text/tab-separated-values"})
+	registry.Register(FormatNdjson, NewParseNdjsonFileService(), []string{".ndjson", ".jsonl"}, []string{"application/x-ndjson", "application/ndjson", "application/jsonl"})
+
+	return registry
+}
+
+func (r *FileFormatRegistry) Register(format string, parser FileParser, extensions []string, contentTypes []string) {
+	r.formats[strings.ToLower(format)] = fileFormat{
+		parser:       parser,
+		extensions:   extensions,
+		contentTypes: contentTypes,
+	}
+}
+
+func (r *FileFormatRegistry) Formats() []string {
+	formats := make([]string, 0, len(r.formats))
+	for format := range r.formats {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Resolve returns the format options refer to. Generic content types such as
+// text/plain and application/octet-stream are ignored in favour of the file
+// extension.
+func (r *FileFormatRegistry) Resolve(options ParseOptions) (string, error) {
+	if options.Format != "" {
+		format := strings.ToLower(options.Format)
+		if _, ok := r.formats[format]; !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnknownFormat, options.Format)
+		}
+		return format, nil
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(options.ContentType); err == nil {
+		for format, candidate := range r.formats {
+			for _, contentType := range candidate.contentTypes {
+				if mediaType == contentType {
+					return format, nil
+				}
+			}
+		}
+	}
+
+	extension := strings.ToLower(filepath.Ext(options.FileName))
+	for format, candidate := range r.formats {
+		for _, known := range candidate.extensions {
+			if extension == known {
+				return format, nil
+			}
+		}
+	}
+
+	return FormatTxt, nil
+}
+
+// ValidateOptions reports whether options resolve to a known format and
+// encoding and, for fixed-width files, a registered layout.
+func (r *FileFormatRegistry) ValidateOptions(options ParseOptions) error {
+	format, err := r.Resolve(options)
+	if err != nil {
+		return err
+	}
+	if _, err := ResolveEncoding(options); err != nil {
+		return err
+	}
+
+	if validator, ok := r.formats[format].parser.(interface{ ValidateOptions(ParseOptions) error }); ok {
+		return validator.ValidateOptions(options)
+	}
+	return nil
+}
+
+// StreamParse transcodes file to UTF-8 and hands it to the parser of its
+// format.
+func (r *FileFormatRegistry) StreamParse(file io.Reader, options ParseOptions, yield func(dto.ParsedCustomerLineDto) error) error {
+	format, err := r.Resolve(options)
+	if err != nil {
+		return err
+	}
+
+	decoded, err := DecodeUpload(file, options)
+	if err != nil {
+		return err
+	}
+
+	return r.formats[format].parser.StreamParse(decoded, options, yield)
+}
@@ -0,0 +1,42 @@
+package service_test
+
+import (
+	"errors"
+	"neoway_test/internal/domain/customer/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileFormatRegistry_Resolve(t *testing.T) {
+	registry := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+
+	tests := []struct {
+		name     string
+		options  service.ParseOptions
+		expected string
+	}{
+		{"explicit format wins", service.ParseOptions{Format: "TSV", ContentType: "text/csv", FileName: "base.ndjson"}, service.FormatTsv},
+		{"content type", service.ParseOptions{ContentType: "text/csv; charset=utf-8", FileName: "base.txt"}, service.FormatCsv},
+		{"generic content type falls back to extension", service.ParseOptions{ContentType: "application/octet-stream", FileName: "base.jsonl"}, service.FormatNdjson},
+		{"text/plain falls back to extension", service.ParseOptions{ContentType: "text/plain", FileName: "BASE.CSV"}, service.FormatCsv},
+		{"default is fixed-width", service.ParseOptions{FileName: "base_teste"}, service.FormatTxt},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			format, err := registry.Resolve(test.options)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, format)
+		})
+	}
+}
+
+func TestFileFormatRegistry_ValidateOptions(t *testing.T) {
+	registry := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+
+	assert.Nil(t, registry.ValidateOptions(service.ParseOptions{FileName: "base.csv", Layout: "ignored for csv"}))
+	assert.True(t, errors.Is(registry.ValidateOptions(service.ParseOptions{Format: "xml"}), service.ErrUnknownFormat))
+	assert.True(t, errors.Is(registry.ValidateOptions(service.ParseOptions{Layout: "missing"}), service.ErrUnknownLayout))
+	assert.Equal(t, []string{"csv", "ndjson", "tsv", "txt"}, registry.Formats())
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const DefaultLayoutName = "neoway"
+
+var ErrUnknownLayout = errors.New("unknown file layout")
+
+type FieldType string
+
+const (
+	FieldTypeString  FieldType = "string"
+	FieldTypeDate    FieldType = "date"
+	FieldTypeDecimal FieldType = "decimal"
+	FieldTypeBoolean FieldType = "boolean"
+)
+
+// Customer fields a layout can map columns to.
+const (
+	FieldCpf                = "cpf"
+	FieldPrivate            = "private"
+	FieldIncompleto         = "incompleto"
+	FieldDataUltimaCompra   = "data_ultima_compra"
+	FieldTicketMedio        = "ticket_medio"
+	FieldTicketUltimaCompra = "ticket_ultima_compra"
+	FieldLojaMaisFrequente  = "loja_mais_frequente"
+	FieldLojaUltimaCompra   = "loja_ultima_compra"
+)
+
+var customerFieldTypes = map[string]FieldType{
+	FieldCpf:                FieldTypeString,
+	FieldPrivate:            FieldTypeBoolean,
+	FieldIncompleto:         FieldTypeBoolean,
+	FieldDataUltimaCompra:   FieldTypeDate,
+	FieldTicketMedio:        FieldTypeDecimal,
+	FieldTicketUltimaCompra: FieldTypeDecimal,
+	FieldLojaMaisFrequente:  FieldTypeString,
+	FieldLojaUltimaCompra:   FieldTypeString,
+}
+
+// LayoutField describes one fixed-width column. A zero Width means the column
+// runs until the end of the line, which is only allowed for the last column.
+type LayoutField struct {
+	Name      string    `json:"name" yaml:"name"`
+	Start     int       `json:"start" yaml:"start"`
+	Width     int       `json:"width" yaml:"width"`
+	Type      FieldType `json:"type" yaml:"type"`
+	NullToken string    `json:"null_token" yaml:"null_token"`
+	// Format is the Go time layout used by date columns.
+	Format string `json:"format" yaml:"format"`
+}
+
+// Layout is a named fixed-width file definition.
+type Layout struct {
+	Name        string        `json:"name" yaml:"name"`
+	HeaderLines int           `json:"header_lines" yaml:"header_lines"`
+	MinLength   int           `json:"min_length" yaml:"min_length"`
+	Fields      []LayoutField `json:"fields" yaml:"fields"`
+}
+
+// DefaultLayout is the layout of the base file shipped with the tech case.
+func DefaultLayout() Layout {
+	return Layout{
+		Name:        DefaultLayoutName,
+		HeaderLines: 1,
+		MinLength:   135,
+		Fields: []LayoutField{
+			{Name: FieldCpf, Start: 0, Width: 19, Type: FieldTypeString},
+			{Name: FieldPrivate, Start: 19, Width: 12, Type: FieldTypeBoolean},
+			{Name: FieldIncompleto, Start: 31, Width: 12, Type: FieldTypeBoolean},
+			{Name: FieldDataUltimaCompra, Start: 43, Width: 22, Type: FieldTypeDate},
+			{Name: FieldTicketMedio, Start: 65, Width: 22, Type: FieldTypeDecimal},
+			{Name: FieldTicketUltimaCompra, Start: 87, Width: 24, Type: FieldTypeDecimal},
+			{Name: FieldLojaMaisFrequente, Start: 111, Width: 20, Type: FieldTypeString},
+			{Name: FieldLojaUltimaCompra, Start: 131, Width: 0, Type: FieldTypeString},
+		},
+	}
+}
+
+// Validate checks the layout and fills in defaults for null tokens, date
+// formats and the minimum line length.
+func (l *Layout) Validate() error {
+	if strings.TrimSpace(l.Name) == "" {
+		return errors.New("layout name is required")
+	}
+	if len(l.Fields) == 0 {
+		return fmt.Errorf("layout %s: fields are required", l.Name)
+	}
+	if l.HeaderLines < 0 || l.MinLength < 0 {
+		return fmt.Errorf("layout %s: header_lines and min_length cannot be negative", l.Name)
+	}
+
+	sort.SliceStable(l.Fields, func(i, j int) bool { return l.Fields[i].Start < l.Fields[j].Start })
+
+	seen := map[string]bool{}
+	for i := range l.Fields {
+		field := &l.Fields[i]
+
+		expectedType, known := customerFieldTypes[field.Name]
+		if !known {
+			return fmt.Errorf("layout %s: unknown field %q", l.Name, field.Name)
+		}
+		if seen[field.Name] {
+			return fmt.Errorf("layout %s: field %q declared twice", l.Name, field.Name)
+		}
+		seen[field.Name] = true
+
+		if field.Type == "" {
+			field.Type = expectedType
+		}
+		if field.Type != expectedType {
+			return fmt.Errorf("layout %s: field %q must be of type %s", l.Name, field.Name, expectedType)
+		}
+		if field.Start < 0 || field.Width < 0 {
+			return fmt.Errorf("layout %s: field %q has a negative start or width", l.Name, field.Name)
+		}
+
+		last := i == len(l.Fields)-1
+		if field.Width == 0 && !last {
+			return fmt.Errorf("layout %s: only the last field may omit its width, %q does not", l.Name, field.Name)
+		}
+		if !last && field.Start+field.Width > l.Fields[i+1].Start {
+			return fmt.Errorf("layout %s: field %q overlaps %q", l.Name, field.Name, l.Fields[i+1].Name)
+		}
+
+		if field.NullToken == "" {
+			field.NullToken = "NULL"
+		}
+		if field.Type == FieldTypeDate && field.Format == "" {
+			field.Format = "2006-01-02"
+		}
+		if field.Type == FieldTypeDate && !validDateFormat(field.Format) {
+			return fmt.Errorf("layout %s: field %q has an invalid date format %q", l.Name, field.Name, field.Format)
+		}
+	}
+
+	if !seen[FieldCpf] {
+		return fmt.Errorf("layout %s: field %q is required", l.Name, FieldCpf)
+	}
+
+	if l.MinLength == 0 {
+		l.MinLength = l.Fields[len(l.Fields)-1].Start + 1
+	}
+
+	return nil
+}
+
+// validDateFormat reports whether format is a Go time layout that keeps the
+// year, month and day of a date it formats.
+func validDateFormat(format string) bool {
+	reference := time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC)
+	parsed, err := time.Parse(format, reference.Format(format))
+	if err != nil {
+		return false
+	}
+	year, month, day := parsed.Date()
+	return year == 2006 && month == time.January && day == 2
+}
+
+// column returns the trimmed raw value of field in line, or "" when the line
+// ends before the field starts. Offsets count characters, so accented text
+// does not shift the columns after it.
+func (f LayoutField) column(line []rune) string {
+	if f.Start >= len(line) {
+		return ""
+	}
+	end := len(line)
+	if f.Width > 0 && f.Start+f.Width < end {
+		end = f.Start + f.Width
+	}
+	return strings.TrimSpace(string(line[f.Start:end]))
+}
+
+// LayoutRegistry holds the layouts available to the fixed-width parser.
+type LayoutRegistry struct {
+	mu      sync.RWMutex
+	layouts map[string]Layout
+}
+
+// NewLayoutRegistry returns a registry with DefaultLayout already registered.
+func NewLayoutRegistry() *LayoutRegistry {
+	registry := &LayoutRegistry{layouts: map[string]Layout{}}
+	if err := registry.Register(DefaultLayout()); err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// Register validates layout and makes it available by name, replacing any
+// layout previously registered under the same name.
+func (r *LayoutRegistry) Register(layout Layout) error {
+	if err := layout.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.layouts[strings.ToLower(layout.Name)] = layout
+	return nil
+}
+
+// Get returns the named layout, or DefaultLayout when name is empty.
+func (r *LayoutRegistry) Get(name string) (Layout, error) {
+	if name == "" {
+		name = DefaultLayoutName
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	layout, ok := r.layouts[strings.ToLower(name)]
+	if !ok {
+		return Layout{}, fmt.Errorf("%w: %s", ErrUnknownLayout, name)
+	}
+	return layout, nil
+}
+
+func (r *LayoutRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.layouts))
+	for _, layout := range r.layouts {
+		names = append(names, layout.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadFile registers every layout declared in a YAML or JSON file shaped as
+// {"layouts": [...]}. Nothing is registered if any layout is invalid, if two
+// layouts share a name or if one would replace DefaultLayout.
+func (r *LayoutRegistry) LoadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var file struct {
+		Layouts []Layout `json:"layouts" yaml:"layouts"`
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(content, &file)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &file)
+	default:
+		return fmt.Errorf("unsupported layout file extension: %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid layout file %s: %w", path, err)
+	}
+
+	names := map[string]bool{}
+	for i := range file.Layouts {
+		if err := file.Layouts[i].Validate(); err != nil {
+			return err
+		}
+
+		name := strings.ToLower(file.Layouts[i].Name)
+		if name == DefaultLayoutName {
+			return fmt.Errorf("layout %s: the built-in layout cannot be replaced", file.Layouts[i].Name)
+		}
+		if names[name] {
+			return fmt.Errorf("layout %s: declared twice", file.Layouts[i].Name)
+		}
+		names[name] = true
+	}
+	for _, layout := range file.Layouts {
+		if err := r.Register(layout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
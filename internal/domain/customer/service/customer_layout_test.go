@@ -0,0 +1,143 @@
+package service_test
+
+import (
+	"bytes"
+	"errors"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/service"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func partnerLayout() service.Layout {
+	return service.Layout{
+		Name:        "partner",
+		HeaderLines: 0,
+		Fields: []service.LayoutField{
+			{Name: service.FieldCpf, Start: 0, Width: 14},
+			{Name: service.FieldDataUltimaCompra, Start: 15, Width: 10, Format: "02/01/2006", NullToken: "-"},
+			{Name: service.FieldTicketMedio, Start: 26, Width: 8, NullToken: "-"},
+			{Name: service.FieldLojaUltimaCompra, Start: 35},
+		},
+	}
+}
+
+func TestLayoutRegistry_DefaultLayout(t *testing.T) {
+	registry := service.NewLayoutRegistry()
+
+	layout, err := registry.Get("")
+
+	assert.Nil(t, err)
+	assert.Equal(t, service.DefaultLayoutName, layout.Name)
+	assert.Equal(t, 135, layout.MinLength)
+	assert.Equal(t, []string{"neoway"}, registry.Names())
+}
+
+func TestLayoutRegistry_UnknownLayout(t *testing.T) {
+	registry := service.NewLayoutRegistry()
+
+	_, err := registry.Get("missing")
+
+	assert.True(t, errors.Is(err, service.ErrUnknownLayout))
+}
+
+func TestLayoutRegistry_ParsesWithRegisteredLayout(t *testing.T) {
+	registry := service.NewLayoutRegistry()
+	assert.Nil(t, registry.Register(partnerLayout()))
+
+	fileContent := `02698737913    20/01/2011 159,31   79.379.491/0001-83
+04109164125    -          -        NULL`
+
+	parseService := service.NewParseTxtFileService(registry)
+
+	var lines []dto.ParsedCustomerLineDto
+	err := parseService.StreamParseTxtFileService(bytes.NewReader([]byte(fileContent)), service.ParseOptions{Layout: "partner"}, func(line dto.ParsedCustomerLineDto) error {
+		lines = append(lines, line)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, 1, lines[0].LineNumber)
+	assert.Equal(t, "02698737913", lines[0].Customer.Cpf)
+	assert.Equal(t, "2011-01-20", lines[0].Customer.DataUltimaCompra.Format("2006-01-02"))
+	assert.Equal(t, 159.31, lines[0].Customer.TicketMedio)
+	assert.Equal(t, "79.379.491/0001-83", lines[0].Customer.LojaUltimaCompra)
+	assert.Nil(t, lines[1].Customer.DataUltimaCompra)
+	assert.Equal(t, 0.0, lines[1].Customer.TicketMedio)
+	assert.Equal(t, "NULL", lines[1].Customer.LojaUltimaCompra)
+}
+
+func TestLayout_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(layout *service.Layout)
+		err    string
+	}{
+		{"missing name", func(l *service.Layout) { l.Name = "" }, "layout name is required"},
+		{"unknown field", func(l *service.Layout) { l.Fields[1].Name = "email" }, `layout partner: unknown field "email"`},
+		{"duplicated field", func(l *service.Layout) { l.Fields[1].Name = service.FieldCpf }, `layout partner: field "cpf" declared twice`},
+		{"wrong type", func(l *service.Layout) { l.Fields[2].Type = service.FieldTypeDate }, `layout partner: field "ticket_medio" must be of type decimal`},
+		{"overlap", func(l *service.Layout) { l.Fields[0].Width = 20 }, `layout partner: field "cpf" overlaps "data_ultima_compra"`},
+		{"open width", func(l *service.Layout) { l.Fields[0].Width = 0 }, `layout partner: only the last field may omit its width, "cpf" does not`},
+		{"date format", func(l *service.Layout) { l.Fields[1].Format = "dd/mm/yyyy" }, `layout partner: field "data_ultima_compra" has an invalid date format "dd/mm/yyyy"`},
+		{"missing cpf", func(l *service.Layout) { l.Fields = l.Fields[1:] }, `layout partner: field "cpf" is required`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			layout := partnerLayout()
+			test.modify(&layout)
+			assert.EqualError(t, layout.Validate(), test.err)
+		})
+	}
+}
+
+func TestLayoutRegistry_LoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlPath := filepath.Join(dir, "layouts.yaml")
+	os.WriteFile(yamlPath, []byte(`
+layouts:
+  - name: partner_yaml
+    header_lines: 1
+    fields:
+      - {name: cpf, start: 0, width: 14, type: string}
+      - {name: loja_ultima_compra, start: 15, type: string, null_token: "-"}
+`), 0o644)
+
+	jsonPath := filepath.Join(dir, "layouts.json")
+	os.WriteFile(jsonPath, []byte(`{"layouts": [{"name": "partner_json", "fields": [{"name": "cpf", "start": 0, "width": 14}]}]}`), 0o644)
+
+	invalidPath := filepath.Join(dir, "invalid.yaml")
+	os.WriteFile(invalidPath, []byte(`
+layouts:
+  - name: broken
+    fields:
+      - {name: email, start: 0, width: 10}
+`), 0o644)
+
+	duplicatedPath := filepath.Join(dir, "duplicated.json")
+	os.WriteFile(duplicatedPath, []byte(`{"layouts": [{"name": "twice", "fields": [{"name": "cpf", "start": 0, "width": 14}]}, {"name": "Twice", "fields": [{"name": "cpf", "start": 0, "width": 11}]}]}`), 0o644)
+
+	defaultPath := filepath.Join(dir, "default.json")
+	os.WriteFile(defaultPath, []byte(`{"layouts": [{"name": "Neoway", "fields": [{"name": "cpf", "start": 0, "width": 14}]}]}`), 0o644)
+
+	registry := service.NewLayoutRegistry()
+
+	assert.Nil(t, registry.LoadFile(yamlPath))
+	assert.Nil(t, registry.LoadFile(jsonPath))
+	assert.EqualError(t, registry.LoadFile(invalidPath), `layout broken: unknown field "email"`)
+	assert.EqualError(t, registry.LoadFile(duplicatedPath), "layout Twice: declared twice")
+	assert.EqualError(t, registry.LoadFile(defaultPath), "layout Neoway: the built-in layout cannot be replaced")
+	assert.Equal(t, []string{"neoway", "partner_json", "partner_yaml"}, registry.Names())
+
+	layout, err := registry.Get("partner_yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, layout.HeaderLines)
+	assert.Equal(t, "-", layout.Fields[1].NullToken)
+	assert.Equal(t, 16, layout.MinLength)
+}
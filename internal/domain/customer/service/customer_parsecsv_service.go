@@ -0,0 +1,110 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"neoway_test/internal/domain/customer/dto"
+	"regexp"
+	"strings"
+
+	"github.com/mozillazg/go-unidecode"
+)
+
+var ErrMissingCpfColumn = errors.New("invalid file format: missing cpf column")
+
+var headerSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ParseCsvFileService reads delimited files whose first row names the columns.
+type ParseCsvFileService struct {
+	delimiter rune
+}
+
+func NewParseCsvFileService(delimiter rune) *ParseCsvFileService {
+	return &ParseCsvFileService{delimiter: delimiter}
+}
+
+// StreamParse maps columns to customer fields by header name, so column order
+// does not matter and unknown columns are ignored. Rows that cannot be read are
+// yielded with Err set.
+func (s *ParseCsvFileService) StreamParse(file io.Reader, options ParseOptions, yield func(dto.ParsedCustomerLineDto) error) error {
+	reader := csv.NewReader(file)
+	reader.Comma = s.delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	columns := map[int]LayoutField{}
+	for i, name := range header {
+		field := normalizeHeader(name)
+		if fieldType, ok := customerFieldTypes[field]; ok {
+			columns[i] = LayoutField{Name: field, Type: fieldType, NullToken: "NULL", Format: "2006-01-02"}
+		}
+	}
+	if !hasField(columns, FieldCpf) {
+		return ErrMissingCpfColumn
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			return err
+		}
+
+		parsed := dto.ParsedCustomerLineDto{Raw: strings.Join(record, string(s.delimiter))}
+
+		if err != nil {
+			parsed.LineNumber = parseErr.StartLine
+			parsed.Err = err
+		} else {
+			parsed.LineNumber, _ = reader.FieldPos(0)
+			for i, value := range record {
+				if field, ok := columns[i]; ok {
+					recordFieldError(&parsed, setCustomerField(&parsed.Customer, field, strings.TrimSpace(value)), options)
+				}
+			}
+		}
+
+		if err := yield(parsed); err != nil {
+			return err
+		}
+	}
+}
+
+// normalizeHeader turns headers such as "DATA DA ÚLTIMA COMPRA" into field
+// names such as "data_ultima_compra".
+func normalizeHeader(header string) string {
+	header = strings.ToLower(unidecode.Unidecode(strings.TrimSpace(header)))
+
+	var words []string
+	for _, word := range headerSeparator.Split(header, -1) {
+		switch word {
+		case "", "da", "de", "do":
+			continue
+		}
+		words = append(words, word)
+	}
+
+	return strings.Join(words, "_")
+}
+
+func hasField(columns map[int]LayoutField, name string) bool {
+	for _, field := range columns {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package service_test
+
+import (
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/service"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collectLines(t *testing.T, parser service.FileParser, content string) []dto.ParsedCustomerLineDto {
+	var lines []dto.ParsedCustomerLineDto
+	err := parser.StreamParse(strings.NewReader(content), service.ParseOptions{}, func(line dto.ParsedCustomerLineDto) error {
+		lines = append(lines, line)
+		return nil
+	})
+	assert.Nil(t, err)
+	return lines
+}
+
+func TestParseCsvFileService_MapsColumnsByHeader(t *testing.T) {
+	content := `LOJA DA ÚLTIMA COMPRA,CPF,Ticket Médio,DATA DA ÚLTIMA COMPRA,PRIVATE,INCOMPLETO,observacao
+79.379.491/0001-83,026.987.379-13,"159,31",2011-01-20,0,1,ignored
+NULL,041.091.641-25,NULL,NULL,1,0,`
+
+	lines := collectLines(t, service.NewParseCsvFileService(','), content)
+
+	assert.Len(t, lines, 2)
+	assert.Equal(t, 2, lines[0].LineNumber)
+	assert.Nil(t, lines[0].Err)
+	assert.Equal(t, "026.987.379-13", lines[0].Customer.Cpf)
+	assert.False(t, lines[0].Customer.Private)
+	assert.True(t, lines[0].Customer.Incompleto)
+	assert.Equal(t, 159.31, lines[0].Customer.TicketMedio)
+	assert.Equal(t, "2011-01-20", lines[0].Customer.DataUltimaCompra.Format("2006-01-02"))
+	assert.Equal(t, "79.379.491/0001-83", lines[0].Customer.LojaUltimaCompra)
+
+	assert.Equal(t, 3, lines[1].LineNumber)
+	assert.Nil(t, lines[1].Customer.DataUltimaCompra)
+	assert.Equal(t, 0.0, lines[1].Customer.TicketMedio)
+	assert.Equal(t, "NULL", lines[1].Customer.LojaUltimaCompra)
+}
+
+func TestParseCsvFileService_Tsv(t *testing.T) {
+	content := "cpf\tticket_ultima_compra\tloja_mais_frequente\n026.987.379-13\t89.90\t79.379.491/0001-83\n"
+
+	lines := collectLines(t, service.NewParseCsvFileService('\t'), content)
+
+	assert.Len(t, lines, 1)
+	assert.Equal(t, "026.987.379-13", lines[0].Customer.Cpf)
+	assert.Equal(t, 89.90, lines[0].Customer.TicketUltimaCompra)
+	assert.Equal(t, "79.379.491/0001-83", lines[0].Customer.LojaMaisFrequente)
+	assert.Equal(t, "026.987.379-13\t89.90\t79.379.491/0001-83", lines[0].Raw)
+}
+
+func TestParseCsvFileService_MissingCpfColumn(t *testing.T) {
+	parser := service.NewParseCsvFileService(',')
+
+	err := parser.StreamParse(strings.NewReader("nome,ticket_medio\nfulano,10\n"), service.ParseOptions{}, func(line dto.ParsedCustomerLineDto) error {
+		return nil
+	})
+
+	assert.Equal(t, service.ErrMissingCpfColumn, err)
+}
+
+func TestParseCsvFileService_StrictReportsFirstMalformedColumn(t *testing.T) {
+	content := "cpf,ticket_medio,data_ultima_compra\n026.987.379-13,abc,2011-02-30\n"
+
+	var lines []dto.ParsedCustomerLineDto
+	err := service.NewParseCsvFileService(',').StreamParse(strings.NewReader(content), service.ParseOptions{Strict: true}, func(line dto.ParsedCustomerLineDto) error {
+		lines = append(lines, line)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, lines, 1)
+	assert.EqualError(t, lines[0].Err, `ticket_medio "abc": invalid amount`)
+}
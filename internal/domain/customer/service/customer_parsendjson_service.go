@@ -0,0 +1,51 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"neoway_test/internal/domain/customer/dto"
+	"strings"
+)
+
+const maxNdjsonLineSize = 1024 * 1024
+
+// ParseNdjsonFileService reads one dto.InputCreateCustomerDto JSON object per line.
+type ParseNdjsonFileService struct{}
+
+func NewParseNdjsonFileService() *ParseNdjsonFileService {
+	return &ParseNdjsonFileService{}
+}
+
+// StreamParse skips blank lines and yields objects that fail to decode with Err set.
+func (s *ParseNdjsonFileService) StreamParse(file io.Reader, options ParseOptions, yield func(dto.ParsedCustomerLineDto) error) error {
+	reader := bufio.NewScanner(file)
+	reader.Buffer(make([]byte, 0, 64*1024), maxNdjsonLineSize)
+
+	lineNumber := 0
+	for reader.Scan() {
+		line := reader.Text()
+		lineNumber++
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		parsed := dto.ParsedCustomerLineDto{LineNumber: lineNumber, Raw: line}
+
+		var input dto.InputCreateCustomerDto
+		if err := json.Unmarshal([]byte(line), &input); err != nil {
+			parsed.Err = err
+		} else {
+			var fieldErr *FieldError
+			parsed.Customer, fieldErr = parseInputCustomer(input)
+			recordFieldError(&parsed, fieldErr, options)
+		}
+
+		if err := yield(parsed); err != nil {
+			return err
+		}
+	}
+
+	return reader.Err()
+}
@@ -0,0 +1,48 @@
+package service_test
+
+import (
+	"errors"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNdjsonFileService_StreamParse(t *testing.T) {
+	content := `{"Cpf": "026.987.379-13", "Private": "0", "Incompleto": "0", "DataUltimaCompra": "2011-01-20", "TicketMedio": 159.31, "TicketUltimaCompra": 159.31, "LojaMaisFrequente": "79.379.491/0001-83", "LojaUltimaCompra": "79.379.491/0001-83"}
+
+{"cpf": "041.091.641-25", "DataUltimaCompra": "NULL", "Private": true, "Incompleto": 1}
+{"Cpf": "058.189.421-98", "TicketMedio": "not a number"}
+{"Cpf": "070.135.231-06", "Private": "maybe"}`
+
+	lines := collectLines(t, service.NewParseNdjsonFileService(), content)
+
+	assert.Len(t, lines, 4)
+	assert.Equal(t, 1, lines[0].LineNumber)
+	assert.Nil(t, lines[0].Err)
+	assert.Equal(t, "026.987.379-13", lines[0].Customer.Cpf)
+	assert.Equal(t, 159.31, lines[0].Customer.TicketMedio)
+	assert.Equal(t, "2011-01-20", lines[0].Customer.DataUltimaCompra.Format("2006-01-02"))
+
+	assert.Equal(t, 3, lines[1].LineNumber)
+	assert.Equal(t, "041.091.641-25", lines[1].Customer.Cpf)
+	assert.Nil(t, lines[1].Customer.DataUltimaCompra)
+	assert.True(t, lines[1].Customer.Private)
+	assert.True(t, lines[1].Customer.Incompleto)
+
+	assert.Equal(t, 4, lines[2].LineNumber)
+	assert.NotNil(t, lines[2].Err)
+
+	assert.True(t, errors.Is(lines[3].Err, dto.ErrInvalidBoolean))
+}
+
+func TestParseNdjsonFileService_WarnsAboutMalformedDate(t *testing.T) {
+	lines := collectLines(t, service.NewParseNdjsonFileService(), `{"Cpf": "026.987.379-13", "DataUltimaCompra": "20/01/2011"}`)
+
+	assert.Len(t, lines, 1)
+	assert.Nil(t, lines[0].Err)
+	assert.Nil(t, lines[0].Customer.DataUltimaCompra)
+	assert.Len(t, lines[0].Warnings, 1)
+	assert.Equal(t, "data_ultima_compra", lines[0].Warnings[0].Field)
+}
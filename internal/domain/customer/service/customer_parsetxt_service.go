@@ -3,6 +3,7 @@ package service
 import (
 	"bufio"
 	"errors"
+	"fmt"
 	"io"
 	"neoway_test/internal/domain/customer/dto"
 
@@ -11,97 +12,250 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
-type ParseTxtFileService struct{}
+var (
+	ErrLineTooShort  = errors.New("invalid file format: line too short")
+	ErrInvalidDate   = errors.New("invalid date")
+	ErrInvalidAmount = errors.New("invalid amount")
+)
+
+// FieldError is a column value that could not be converted to its field type.
+type FieldError struct {
+	Field string
+	Value string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s %q: %v", e.Field, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+type ParseTxtFileService struct {
+	layouts *LayoutRegistry
+}
 type ParseService struct{}
 
 // NewParseTxtFileService cria uma nova instância do serviço.
-func NewParseTxtFileService() *ParseTxtFileService {
-	return &ParseTxtFileService{}
+func NewParseTxtFileService(layouts *LayoutRegistry) *ParseTxtFileService {
+	return &ParseTxtFileService{layouts: layouts}
+}
+
+// ValidateOptions reports whether options refer to a registered layout.
+func (s *ParseTxtFileService) ValidateOptions(options ParseOptions) error {
+	_, err := s.layouts.Get(options.Layout)
+	return err
+}
+
+func (s *ParseTxtFileService) StreamParse(file io.Reader, options ParseOptions, yield func(dto.ParsedCustomerLineDto) error) error {
+	return s.StreamParseTxtFileService(file, options, yield)
 }
 
 func NewParseService() *ParseService {
 	return &ParseService{}
 }
 
-func (s *ParseTxtFileService) ExecuteParseTxtFileService(file io.Reader) ([]dto.OutputCreateCustomerDto, error) {
-	var customers []dto.OutputCreateCustomerDto
+// StreamParseTxtFileService parses the file line by line using the layout in
+// options and hands each line to yield as soon as it is read, so callers never
+// hold the whole file in memory. Malformed lines are yielded with Err set
+// instead of aborting the parse. Parsing stops at the first error returned by
+// yield.
+func (s *ParseTxtFileService) StreamParseTxtFileService(file io.Reader, options ParseOptions, yield func(dto.ParsedCustomerLineDto) error) error {
+	layout, err := s.layouts.Get(options.Layout)
+	if err != nil {
+		return err
+	}
+
 	reader := bufio.NewScanner(file)
 
-	lineIndex := 0
+	lineNumber := 0
 	for reader.Scan() {
 		line := reader.Text()
-		// Skip header
-		if lineIndex == 0 {
-			lineIndex++
+		lineNumber++
+
+		if lineNumber <= layout.HeaderLines {
 			continue
 		}
 
-		if len(line) < 135 {
-			return nil, errors.New("invalid file format: line too short")
+		parsed := dto.ParsedCustomerLineDto{LineNumber: lineNumber, Raw: line}
+
+		if utf8.RuneCountInString(line) < layout.MinLength {
+			parsed.Err = ErrLineTooShort
+		} else {
+			parseFixedWidthLine(&parsed, layout, line, options)
 		}
 
-		customer := dto.OutputCreateCustomerDto{
-			Cpf:                parseNull(strings.TrimSpace(line[0:19])),
-			Private:            strings.TrimSpace(line[19:31]),
-			Incompleto:         strings.TrimSpace(line[31:43]),
-			DataUltimaCompra:   parseDate(strings.TrimSpace(line[43:65])),
-			TicketMedio:        parseFloat(strings.TrimSpace(line[65:87])),
-			TicketUltimaCompra: parseFloat(strings.TrimSpace(line[87:111])),
-			LojaMaisFrequente:  parseNull(strings.TrimSpace(line[111:131])),
-			LojaUltimaCompra:   parseNull(strings.TrimSpace(line[131:])),
+		if err := yield(parsed); err != nil {
+			return err
 		}
+	}
+
+	return reader.Err()
+}
+
+func parseFixedWidthLine(parsed *dto.ParsedCustomerLineDto, layout Layout, line string, options ParseOptions) {
+	runes := []rune(line)
 
-		customers = append(customers, customer)
-		lineIndex++
+	for _, field := range layout.Fields {
+		recordFieldError(parsed, setCustomerField(&parsed.Customer, field, field.column(runes)), options)
 	}
+}
 
-	if err := reader.Err(); err != nil {
+// recordFieldError fails the line with err in strict mode, keeping only the
+// first error, and adds it to the line's warnings otherwise. Booleans have no
+// neutral value to fall back to, so an invalid one fails the line either way.
+func recordFieldError(parsed *dto.ParsedCustomerLineDto, err *FieldError, options ParseOptions) {
+	if err == nil {
+		return
+	}
+	if options.Strict || errors.Is(err, dto.ErrInvalidBoolean) {
+		if parsed.Err == nil {
+			parsed.Err = err
+		}
+		return
+	}
+
+	message := err.Err.Error() + ", stored as NULL"
+	if errors.Is(err, ErrInvalidAmount) {
+		message = err.Err.Error() + ", stored as 0"
+	}
+	parsed.Warnings = append(parsed.Warnings, dto.CustomerFieldWarningDto{
+		LineNumber: parsed.LineNumber,
+		Field:      err.Field,
+		Value:      err.Value,
+		Message:    message,
+	})
+}
+
+// setCustomerField converts a raw column value according to field and stores it
+// on customer. Empty values and the field's null token are treated as null.
+// Booleans accept 0/1, true/false and S/N, with empty values read as false.
+// Values that cannot be read are left null, zero or false and returned as a
+// FieldError.
+func setCustomerField(customer *dto.OutputCreateCustomerDto, field LayoutField, value string) *FieldError {
+	isNull := value == "" || strings.EqualFold(value, field.NullToken)
+
+	switch field.Name {
+	case FieldCpf:
+		customer.Cpf = parseNullable(value, isNull)
+	case FieldPrivate, FieldIncompleto:
+		if isNull {
+			break
+		}
+		flag, err := dto.ParseBoolFlag(value)
+		if err != nil {
+			return &FieldError{Field: field.Name, Value: value, Err: dto.ErrInvalidBoolean}
+		}
+		if field.Name == FieldPrivate {
+			customer.Private = flag
+		} else {
+			customer.Incompleto = flag
+		}
+	case FieldDataUltimaCompra:
+		if !isNull {
+			customer.DataUltimaCompra = parseDateFormat(value, field.Format)
+			if customer.DataUltimaCompra == nil {
+				return &FieldError{Field: field.Name, Value: value, Err: ErrInvalidDate}
+			}
+		}
+	case FieldTicketMedio:
+		if !isNull {
+			ticket, err := parseAmount(value)
+			if err != nil {
+				return &FieldError{Field: field.Name, Value: value, Err: ErrInvalidAmount}
+			}
+			customer.TicketMedio = ticket
+		}
+	case FieldTicketUltimaCompra:
+		if !isNull {
+			ticket, err := parseAmount(value)
+			if err != nil {
+				return &FieldError{Field: field.Name, Value: value, Err: ErrInvalidAmount}
+			}
+			customer.TicketUltimaCompra = ticket
+		}
+	case FieldLojaMaisFrequente:
+		customer.LojaMaisFrequente = parseNullable(value, isNull)
+	case FieldLojaUltimaCompra:
+		customer.LojaUltimaCompra = parseNullable(value, isNull)
+	}
+	return nil
+}
+
+// ExecuteParseTxtFileService parses the whole file into memory and fails on the
+// first malformed line. Prefer StreamParseTxtFileService for large files.
+func (s *ParseTxtFileService) ExecuteParseTxtFileService(file io.Reader) ([]dto.OutputCreateCustomerDto, error) {
+	var customers []dto.OutputCreateCustomerDto
+
+	err := s.StreamParseTxtFileService(file, ParseOptions{}, func(line dto.ParsedCustomerLineDto) error {
+		if line.Err != nil {
+			return line.Err
+		}
+		customers = append(customers, line.Customer)
+		return nil
+	})
+
+	if err != nil {
 		return nil, err
 	}
 
 	return customers, nil
 }
 
+// ExecuteParseService normalizes a customer received through the API, failing
+// with a FieldError on a malformed date instead of storing it as NULL.
 func (s *ParseService) ExecuteParseService(input dto.InputCreateCustomerDto) (dto.OutputCreateCustomerDto, error) {
+	customer, err := parseInputCustomer(input)
+	if err != nil {
+		return dto.OutputCreateCustomerDto{}, err
+	}
+	return customer, nil
+}
 
+// parseInputCustomer normalizes a customer received as structured input. A
+// malformed date is left nil and returned as a FieldError.
+func parseInputCustomer(input dto.InputCreateCustomerDto) (dto.OutputCreateCustomerDto, *FieldError) {
 	customer := dto.OutputCreateCustomerDto{
 		Cpf:                parseNull(input.Cpf),
-		Private:            strings.TrimSpace(input.Private),
-		Incompleto:         strings.TrimSpace(input.Incompleto),
-		DataUltimaCompra:   parseDate(strings.TrimSpace(input.DataUltimaCompra)),
+		Private:            bool(input.Private),
+		Incompleto:         bool(input.Incompleto),
 		TicketMedio:        input.TicketMedio,
 		TicketUltimaCompra: input.TicketUltimaCompra,
 		LojaMaisFrequente:  parseNull(strings.TrimSpace(input.LojaMaisFrequente)),
 		LojaUltimaCompra:   parseNull(strings.TrimSpace(input.LojaUltimaCompra)),
 	}
 
-	return customer, nil
+	dateField := LayoutField{Name: FieldDataUltimaCompra, Type: FieldTypeDate, NullToken: "NULL", Format: "2006-01-02"}
+	err := setCustomerField(&customer, dateField, strings.TrimSpace(input.DataUltimaCompra))
+
+	return customer, err
 }
 
-// parseDate converts a string date to *time.Time (or nil if "NULL")
-func parseDate(value string) *time.Time {
-	if strings.ToUpper(value) == "NULL" || value == "" {
-		return nil
-	}
-	t, err := time.Parse("2006-01-02", value)
+// parseDateFormat converts a string date in the given layout to *time.Time (or nil if invalid)
+func parseDateFormat(value string, format string) *time.Time {
+	t, err := time.Parse(format, value)
 	if err != nil {
 		return nil
 	}
 	return &t
 }
 
-// parseFloat converts a string to float64 (or 0 if "NULL")
-func parseFloat(value string) float64 {
-	if strings.ToUpper(value) == "NULL" || value == "" {
-		return 0
-	}
-	f, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
-	if err != nil {
-		return 0
+// parseAmount converts an amount with a decimal comma or point to float64
+func parseAmount(value string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+}
+
+// parseNullable upper-cases value, mapping a layout null token to "NULL"
+func parseNullable(value string, isNull bool) string {
+	if isNull {
+		return "NULL"
 	}
-	return f
+	return parseNull(value)
 }
 
 // parseNull converts "NULL" to upper
@@ -2,6 +2,8 @@ package service_test
 
 import (
 	"bytes"
+	"errors"
+	"neoway_test/internal/domain/customer/dto"
 	"neoway_test/internal/domain/customer/service"
 	"testing"
 
@@ -14,15 +16,15 @@ func TestExecuteParseTxtFileService_ValidFile(t *testing.T) {
 041.091.641-25     0           1           NULL                  NULL                  NULL                    NULL                NULL`
 
 	reader := bytes.NewReader([]byte(fileContent))
-	service := service.NewParseTxtFileService()
+	service := service.NewParseTxtFileService(service.NewLayoutRegistry())
 
 	customers, err := service.ExecuteParseTxtFileService(reader)
 
 	assert.Nil(t, err)
 	assert.Len(t, customers, 2)
 	assert.Equal(t, "026.987.379-13", customers[0].Cpf)
-	assert.Equal(t, "0", customers[0].Private)
-	assert.Equal(t, "0", customers[0].Incompleto)
+	assert.False(t, customers[0].Private)
+	assert.False(t, customers[0].Incompleto)
 	assert.NotNil(t, customers[0].DataUltimaCompra)
 	assert.Equal(t, 159.31, customers[0].TicketMedio)
 	assert.Equal(t, 159.31, customers[0].TicketUltimaCompra)
@@ -30,8 +32,8 @@ func TestExecuteParseTxtFileService_ValidFile(t *testing.T) {
 	assert.Equal(t, "79.379.491/0001-83", customers[0].LojaUltimaCompra)
 
 	assert.Equal(t, "041.091.641-25", customers[1].Cpf)
-	assert.Equal(t, "0", customers[1].Private)
-	assert.Equal(t, "1", customers[1].Incompleto)
+	assert.False(t, customers[1].Private)
+	assert.True(t, customers[1].Incompleto)
 	assert.Nil(t, customers[1].DataUltimaCompra)
 	assert.Equal(t, 0.0, customers[1].TicketMedio)
 	assert.Equal(t, 0.0, customers[1].TicketUltimaCompra)
@@ -44,10 +46,129 @@ func TestExecuteParseTxtFileService_InvalidLineLength(t *testing.T) {
 922.488.109-20   0              0              2011-01-27`
 
 	reader := bytes.NewReader([]byte(fileContent))
-	service := service.NewParseTxtFileService()
+	service := service.NewParseTxtFileService(service.NewLayoutRegistry())
 
 	customers, err := service.ExecuteParseTxtFileService(reader)
 
 	assert.Nil(t, customers)
 	assert.EqualError(t, err, "invalid file format: line too short")
 }
+
+func TestStreamParseTxtFileService_StopsWhenYieldFails(t *testing.T) {
+	fileContent := `CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA
+026.987.379-13     0           0           2011-01-20            159,31                159,31                  79.379.491/0001-83  79.379.491/0001-83
+041.091.641-25     0           1           NULL                  NULL                  NULL                    NULL                NULL`
+
+	reader := bytes.NewReader([]byte(fileContent))
+	parseService := service.NewParseTxtFileService(service.NewLayoutRegistry())
+
+	var cpfs []string
+	err := parseService.StreamParseTxtFileService(reader, service.ParseOptions{}, func(line dto.ParsedCustomerLineDto) error {
+		cpfs = append(cpfs, line.Customer.Cpf)
+		return errors.New("stop")
+	})
+
+	assert.EqualError(t, err, "stop")
+	assert.Equal(t, []string{"026.987.379-13"}, cpfs)
+}
+
+func TestStreamParseTxtFileService_YieldsShortLinesAsErrors(t *testing.T) {
+	fileContent := `CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA
+041.091.641-25     0           1
+026.987.379-13     0           0           2011-01-20            159,31                159,31                  79.379.491/0001-83  79.379.491/0001-83`
+
+	reader := bytes.NewReader([]byte(fileContent))
+	parseService := service.NewParseTxtFileService(service.NewLayoutRegistry())
+
+	var lines []dto.ParsedCustomerLineDto
+	err := parseService.StreamParseTxtFileService(reader, service.ParseOptions{}, func(line dto.ParsedCustomerLineDto) error {
+		lines = append(lines, line)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, 2, lines[0].LineNumber)
+	assert.Equal(t, "041.091.641-25     0           1", lines[0].Raw)
+	assert.Equal(t, service.ErrLineTooShort, lines[0].Err)
+	assert.Equal(t, 3, lines[1].LineNumber)
+	assert.Nil(t, lines[1].Err)
+	assert.Equal(t, "026.987.379-13", lines[1].Customer.Cpf)
+}
+
+const malformedTxtFile = `CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA
+026.987.379-13     0           0           2023-13-45            12,3x                 159,31                  79.379.491/0001-83  79.379.491/0001-83
+041.091.641-25     0           1           NULL                  NULL                  NULL                    NULL                NULL`
+
+func TestStreamParseTxtFileService_StrictRejectsMalformedValues(t *testing.T) {
+	parseService := service.NewParseTxtFileService(service.NewLayoutRegistry())
+
+	var lines []dto.ParsedCustomerLineDto
+	err := parseService.StreamParseTxtFileService(bytes.NewReader([]byte(malformedTxtFile)), service.ParseOptions{Strict: true}, func(line dto.ParsedCustomerLineDto) error {
+		lines = append(lines, line)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, lines, 2)
+
+	var fieldErr *service.FieldError
+	assert.True(t, errors.As(lines[0].Err, &fieldErr))
+	assert.Equal(t, "data_ultima_compra", fieldErr.Field)
+	assert.Equal(t, "2023-13-45", fieldErr.Value)
+	assert.True(t, errors.Is(lines[0].Err, service.ErrInvalidDate))
+	assert.Equal(t, `data_ultima_compra "2023-13-45": invalid date`, lines[0].Err.Error())
+
+	// NULL stays a legal null in strict mode.
+	assert.Nil(t, lines[1].Err)
+	assert.Nil(t, lines[1].Customer.DataUltimaCompra)
+}
+
+func TestStreamParseTxtFileService_LenientWarnsAboutMalformedValues(t *testing.T) {
+	parseService := service.NewParseTxtFileService(service.NewLayoutRegistry())
+
+	var lines []dto.ParsedCustomerLineDto
+	err := parseService.StreamParseTxtFileService(bytes.NewReader([]byte(malformedTxtFile)), service.ParseOptions{}, func(line dto.ParsedCustomerLineDto) error {
+		lines = append(lines, line)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, lines, 2)
+	assert.Nil(t, lines[0].Err)
+	assert.Nil(t, lines[0].Customer.DataUltimaCompra)
+	assert.Equal(t, 0.0, lines[0].Customer.TicketMedio)
+	assert.Equal(t, 159.31, lines[0].Customer.TicketUltimaCompra)
+	assert.Equal(t, []dto.CustomerFieldWarningDto{
+		{LineNumber: 2, Field: "data_ultima_compra", Value: "2023-13-45", Message: "invalid date, stored as NULL"},
+		{LineNumber: 2, Field: "ticket_medio", Value: "12,3x", Message: "invalid amount, stored as 0"},
+	}, lines[0].Warnings)
+	assert.Empty(t, lines[1].Warnings)
+}
+
+func TestStreamParseTxtFileService_ParsesBooleanFlags(t *testing.T) {
+	header := "CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA\n"
+	fileContent := header +
+		"026.987.379-13     S           n           NULL                  NULL                  NULL                    NULL                NULL\n" +
+		"041.091.641-25     true        FALSE       NULL                  NULL                  NULL                    NULL                NULL\n" +
+		"058.189.421-98     X           0           NULL                  NULL                  NULL                    NULL                NULL\n"
+	parseService := service.NewParseTxtFileService(service.NewLayoutRegistry())
+
+	var lines []dto.ParsedCustomerLineDto
+	err := parseService.StreamParseTxtFileService(bytes.NewReader([]byte(fileContent)), service.ParseOptions{}, func(line dto.ParsedCustomerLineDto) error {
+		lines = append(lines, line)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, lines, 3)
+	assert.Nil(t, lines[0].Err)
+	assert.True(t, lines[0].Customer.Private)
+	assert.False(t, lines[0].Customer.Incompleto)
+	assert.Nil(t, lines[1].Err)
+	assert.True(t, lines[1].Customer.Private)
+	assert.False(t, lines[1].Customer.Incompleto)
+	// Invalid flags fail the line even outside strict mode.
+	assert.True(t, errors.Is(lines[2].Err, dto.ErrInvalidBoolean))
+	assert.Empty(t, lines[2].Warnings)
+}
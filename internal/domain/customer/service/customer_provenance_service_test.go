@@ -0,0 +1,22 @@
+package service_test
+
+import (
+	"io"
+	"neoway_test/internal/domain/customer/service"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashUpload(t *testing.T) {
+	file := strings.NewReader("file content")
+
+	hash, err := service.HashUpload(file)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "e0ac3601005dfa1864f5392aabaf7d898b1b5bab854f1acb4491bcd806b76b0c", hash)
+
+	content, _ := io.ReadAll(file)
+	assert.Equal(t, "file content", string(content))
+}
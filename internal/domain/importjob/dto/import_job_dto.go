@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"io"
+	customerDto "neoway_test/internal/domain/customer/dto"
+	"time"
+)
+
+type InputCreateImportJobDto struct {
+	FileName    string
+	ContentType string
+	File        io.Reader
+	// FilePath points at an upload already on disk; File is ignored when set.
+	FilePath string
+	Format   string
+	Layout   string
+	Encoding string
+	Strict   bool
+	// DuplicatePolicy resolves CPFs repeated within the file.
+	DuplicatePolicy string
+	// Actor and RequestID identify who queued the job in the audit log.
+	Actor     string
+	RequestID string
+}
+
+type InputGetImportJobByIdDto struct {
+	ID string
+}
+
+type InputGetImportJobsListDto struct {
+	Page int
+}
+
+type OutputImportJobDto struct {
+	ID              string                                   `json:"id"`
+	Status          string                                   `json:"status"`
+	FileName        string                                   `json:"file_name"`
+	FileHash        string                                   `json:"file_hash,omitempty"`
+	Format          string                                   `json:"format"`
+	Layout          string                                   `json:"layout"`
+	Encoding        string                                   `json:"encoding,omitempty"`
+	Strict          bool                                     `json:"strict"`
+	DuplicatePolicy string                                   `json:"duplicate_policy,omitempty"`
+	RowsProcessed   int                                      `json:"rows_processed"`
+	RowsRejected    int                                      `json:"rows_rejected"`
+	Error           string                                   `json:"error,omitempty"`
+	Report          *customerDto.OutputCreateCustomerBulkDto `json:"report,omitempty"`
+	CreatedAt       time.Time                                `json:"created_at"`
+	StartedAt       *time.Time                               `json:"started_at"`
+	FinishedAt      *time.Time                               `json:"finished_at"`
+	// HeartbeatAt is when the running job last reported progress.
+	HeartbeatAt *time.Time `json:"heartbeat_at"`
+}
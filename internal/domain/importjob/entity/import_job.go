@@ -0,0 +1,96 @@
+package entity
+
+import (
+	customerDto "neoway_test/internal/domain/customer/dto"
+	shared "neoway_test/internal/domain/shared/entity"
+	"time"
+)
+
+type ImportJobStatus string
+
+const (
+	ImportJobQueued    ImportJobStatus = "queued"
+	ImportJobRunning   ImportJobStatus = "running"
+	ImportJobSucceeded ImportJobStatus = "succeeded"
+	ImportJobFailed    ImportJobStatus = "failed"
+)
+
+type ImportJob struct {
+	shared.BaseEntity
+	Status          ImportJobStatus                          `json:"status" gorm:"size:20;not null;index"`
+	FileName        string                                   `json:"file_name" gorm:"size:255"`
+	FilePath        string                                   `json:"-" gorm:"size:500"`
+	FileHash        string                                   `json:"file_hash" gorm:"size:64"`
+	Format          string                                   `json:"format" gorm:"size:20"`
+	Layout          string                                   `json:"layout" gorm:"size:100"`
+	Encoding        string                                   `json:"encoding" gorm:"size:20"`
+	Strict          bool                                     `json:"strict" gorm:"not null;default:false"`
+	DuplicatePolicy string                                   `json:"duplicate_policy" gorm:"size:20"`
+	RowsProcessed   int                                      `json:"rows_processed" gorm:"not null;default:0"`
+	RowsRejected    int                                      `json:"rows_rejected" gorm:"not null;default:0"`
+	Error           string                                   `json:"error"`
+	Report          *customerDto.OutputCreateCustomerBulkDto `json:"report" gorm:"type:jsonb;serializer:json"`
+	StartedAt       *time.Time                               `json:"started_at"`
+	FinishedAt      *time.Time                               `json:"finished_at"`
+	// HeartbeatAt is when the process running the job last reported progress.
+	// A running job whose heartbeat is too old is taken as abandoned.
+	HeartbeatAt *time.Time `json:"heartbeat_at" gorm:"index"`
+	// Actor and RequestID identify who queued the job, so the customers it
+	// writes are attributed to them in the audit log.
+	Actor     string `json:"-" gorm:"size:200;not null;default:''"`
+	RequestID string `json:"-" gorm:"size:200;not null;default:''"`
+}
+
+func NewImportJob(fileName string, filePath string, format string, layout string, encoding string, strict bool, duplicatePolicy string) *ImportJob {
+	return &ImportJob{
+		BaseEntity:      shared.NewBaseEntity(),
+		Status:          ImportJobQueued,
+		FileName:        fileName,
+		FilePath:        filePath,
+		Format:          format,
+		Layout:          layout,
+		Encoding:        encoding,
+		Strict:          strict,
+		DuplicatePolicy: duplicatePolicy,
+	}
+}
+
+// RequestBy records who queued the job.
+func (j *ImportJob) RequestBy(actor string, requestID string) {
+	j.Actor = actor
+	j.RequestID = requestID
+}
+
+func (j *ImportJob) Start() {
+	now := time.Now()
+	j.Status = ImportJobRunning
+	j.StartedAt = &now
+	j.HeartbeatAt = &now
+}
+
+func (j *ImportJob) Progress(processed int, rejected int) {
+	now := time.Now()
+	j.RowsProcessed = processed
+	j.RowsRejected = rejected
+	j.HeartbeatAt = &now
+}
+
+func (j *ImportJob) Succeed(report customerDto.OutputCreateCustomerBulkDto) {
+	now := time.Now()
+	j.Status = ImportJobSucceeded
+	j.RowsProcessed = report.Accepted + report.Rejected
+	j.RowsRejected = report.Rejected
+	j.Report = &report
+	j.FinishedAt = &now
+}
+
+func (j *ImportJob) Fail(err error) {
+	now := time.Now()
+	j.Status = ImportJobFailed
+	j.Error = err.Error()
+	j.FinishedAt = &now
+}
+
+func (j *ImportJob) IsFinished() bool {
+	return j.Status == ImportJobSucceeded || j.Status == ImportJobFailed
+}
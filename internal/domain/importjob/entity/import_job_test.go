@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"errors"
+	customerDto "neoway_test/internal/domain/customer/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewImportJob(t *testing.T) {
+	job := NewImportJob("base.txt", "/tmp/import-123", "txt", "", "", false, "")
+
+	assert.NotEmpty(t, job.ID)
+	assert.Equal(t, ImportJobQueued, job.Status)
+	assert.Equal(t, "base.txt", job.FileName)
+	assert.Equal(t, "/tmp/import-123", job.FilePath)
+	assert.Nil(t, job.StartedAt)
+	assert.Nil(t, job.FinishedAt)
+	assert.False(t, job.IsFinished())
+}
+
+func TestImportJobLifecycle_Succeed(t *testing.T) {
+	job := NewImportJob("base.txt", "/tmp/import-123", "txt", "", "", false, "")
+
+	job.Start()
+	assert.Equal(t, ImportJobRunning, job.Status)
+	assert.NotNil(t, job.StartedAt)
+	assert.NotNil(t, job.HeartbeatAt)
+
+	job.Progress(10, 2)
+	assert.Equal(t, 10, job.RowsProcessed)
+	assert.Equal(t, 2, job.RowsRejected)
+
+	job.Succeed(customerDto.OutputCreateCustomerBulkDto{Accepted: 18, Rejected: 2})
+	assert.Equal(t, ImportJobSucceeded, job.Status)
+	assert.Equal(t, 20, job.RowsProcessed)
+	assert.Equal(t, 2, job.RowsRejected)
+	assert.NotNil(t, job.Report)
+	assert.NotNil(t, job.FinishedAt)
+	assert.True(t, job.IsFinished())
+}
+
+func TestImportJobLifecycle_Fail(t *testing.T) {
+	job := NewImportJob("base.txt", "/tmp/import-123", "txt", "", "", false, "")
+
+	job.Start()
+	job.Fail(errors.New("internal server error"))
+
+	assert.Equal(t, ImportJobFailed, job.Status)
+	assert.Equal(t, "internal server error", job.Error)
+	assert.NotNil(t, job.FinishedAt)
+	assert.True(t, job.IsFinished())
+}
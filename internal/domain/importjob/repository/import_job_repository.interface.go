@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"neoway_test/internal/domain/importjob/entity"
+	shared "neoway_test/internal/domain/shared/repository"
+	"time"
+)
+
+type ImportJobRepository interface {
+	shared.RepositoryInterface[entity.ImportJob]
+	Update(job *entity.ImportJob) error
+	// ClaimNext atomically moves the oldest queued job to running and returns it.
+	// It returns gorm.ErrRecordNotFound when the queue is empty.
+	ClaimNext() (*entity.ImportJob, error)
+	// FailStale marks as failed the running jobs whose last heartbeat is older
+	// than staleBefore, left behind by a process that stopped (e.g. a crash).
+	FailStale(staleBefore time.Time, reason string) error
+	// Heartbeat renews the lease of a running job. It does nothing once the job
+	// is no longer running.
+	Heartbeat(id string) error
+	// SaveProgress stores the row counters of a running job and renews its
+	// lease. It does nothing once the job is no longer running, so it cannot
+	// undo FailStale.
+	SaveProgress(job *entity.ImportJob) error
+	// Finish stores the outcome of a running job. It returns false, storing
+	// nothing, when the job is no longer running.
+	Finish(job *entity.ImportJob) (bool, error)
+}
@@ -0,0 +1,31 @@
+package dto
+
+// InputCreatePurchaseDto is one purchase of the customer with the given CPF,
+// at the store with the given CNPJ, on a date formatted as 2006-01-02.
+type InputCreatePurchaseDto struct {
+	Cpf   string  `json:"cpf"`
+	Loja  string  `json:"loja"`
+	Data  string  `json:"data"`
+	Valor float64 `json:"valor"`
+}
+
+type InputCreatePurchasesDto struct {
+	Purchases []InputCreatePurchaseDto
+	// Actor and RequestID identify, in the audit log, the change of the
+	// customers whose purchase fields are derived again.
+	Actor     string
+	RequestID string
+}
+
+// RejectedPurchaseDto describes a purchase that was not recorded. Index is its
+// position in the request, from zero.
+type RejectedPurchaseDto struct {
+	Index  int    `json:"index"`
+	Reason string `json:"reason"`
+}
+
+type OutputCreatePurchasesDto struct {
+	Accepted          int                   `json:"accepted"`
+	Rejected          int                   `json:"rejected"`
+	RejectedPurchases []RejectedPurchaseDto `json:"rejected_purchases"`
+}
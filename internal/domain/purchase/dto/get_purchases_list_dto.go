@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+// InputGetPurchasesListDto filters purchases by customer CPF, store CNPJ and
+// an inclusive date range formatted as 2006-01-02. Empty fields match every
+// purchase.
+type InputGetPurchasesListDto struct {
+	Cpf  string
+	Cnpj string
+	From string
+	To   string
+	Page int
+}
+
+type OutputGetPurchaseDto struct {
+	ID         string    `json:"id"`
+	CustomerID string    `json:"customer_id"`
+	LojaCnpj   string    `json:"loja_cnpj"`
+	Data       string    `json:"data"`
+	Valor      float64   `json:"valor"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"errors"
+	"math"
+	shared "neoway_test/internal/domain/shared/entity"
+	storeEntity "neoway_test/internal/domain/store/entity"
+	"time"
+)
+
+var (
+	ErrPurchaseStoreRequired = errors.New("purchase store cnpj is required")
+	ErrPurchaseDateRequired  = errors.New("purchase date is required")
+	ErrPurchaseInvalidAmount = errors.New("purchase amount must be greater than zero")
+)
+
+// Purchase is one transaction of a customer at a store. A customer's tickets,
+// last purchase and most frequent store are derived from its purchases.
+type Purchase struct {
+	shared.BaseEntity
+	CustomerID string             `json:"customer_id" gorm:"size:50;not null;index"`
+	LojaCnpj   string             `json:"loja_cnpj" gorm:"size:20;not null;index"`
+	Store      *storeEntity.Store `json:"-" gorm:"foreignKey:LojaCnpj;references:Cnpj;constraint:OnUpdate:CASCADE"`
+	Data       time.Time          `json:"data" gorm:"type:date;not null"`
+	Valor      float64            `json:"valor" gorm:"type:numeric(10,2);not null"`
+}
+
+// NewPurchase records a purchase on the day of data, at the store whose CNPJ
+// is lojaCnpj, with or without punctuation.
+func NewPurchase(customerID string, lojaCnpj string, data time.Time, valor float64) (*Purchase, error) {
+	lojaCnpj = storeEntity.NormalizeCnpj(lojaCnpj)
+	if lojaCnpj == "" {
+		return nil, ErrPurchaseStoreRequired
+	}
+	if data.IsZero() {
+		return nil, ErrPurchaseDateRequired
+	}
+	if valor <= 0 || math.IsNaN(valor) || math.IsInf(valor, 0) {
+		return nil, ErrPurchaseInvalidAmount
+	}
+
+	return &Purchase{
+		BaseEntity: shared.NewBaseEntity(),
+		CustomerID: customerID,
+		LojaCnpj:   lojaCnpj,
+		Data:       time.Date(data.Year(), data.Month(), data.Day(), 0, 0, 0, 0, time.UTC),
+		Valor:      math.Round(valor*100) / 100,
+	}, nil
+}
+
+// after reports whether p happened after other. Purchases on the same day are
+// ordered by when they were recorded.
+func (p *Purchase) after(other *Purchase) bool {
+	if !p.Data.Equal(other.Data) {
+		return p.Data.After(other.Data)
+	}
+	return p.CreatedAt.After(other.CreatedAt)
+}
+
+// PurchaseSummary holds the customer fields derived from its purchases.
+type PurchaseSummary struct {
+	TicketMedio        float64
+	TicketUltimaCompra float64
+	DataUltimaCompra   time.Time
+	LojaUltimaCompra   string
+	// LojaMaisFrequente is the store with the most purchases; ties go to the
+	// store of the most recent one.
+	LojaMaisFrequente string
+}
+
+// Summarize derives the customer fields from the purchases of one customer.
+// It reports false when there are no purchases.
+func Summarize(purchases []*Purchase) (PurchaseSummary, bool) {
+	if len(purchases) == 0 {
+		return PurchaseSummary{}, false
+	}
+
+	var total float64
+	last := purchases[0]
+	counts := make(map[string]int)
+	latest := make(map[string]*Purchase)
+	for _, purchase := range purchases {
+		total += purchase.Valor
+		if purchase.after(last) {
+			last = purchase
+		}
+		counts[purchase.LojaCnpj]++
+		if previous, ok := latest[purchase.LojaCnpj]; !ok || purchase.after(previous) {
+			latest[purchase.LojaCnpj] = purchase
+		}
+	}
+
+	mostFrequent := last.LojaCnpj
+	for cnpj, count := range counts {
+		best := counts[mostFrequent]
+		if count > best || (count == best && latest[cnpj].after(latest[mostFrequent])) {
+			mostFrequent = cnpj
+		}
+	}
+
+	return PurchaseSummary{
+		TicketMedio:        math.Round(total/float64(len(purchases))*100) / 100,
+		TicketUltimaCompra: last.Valor,
+		DataUltimaCompra:   last.Data,
+		LojaUltimaCompra:   last.LojaCnpj,
+		LojaMaisFrequente:  mostFrequent,
+	}, true
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(value string) time.Time {
+	date, _ := time.Parse("2006-01-02", value)
+	return date
+}
+
+func TestNewPurchase(t *testing.T) {
+	data := time.Date(2024, 3, 10, 15, 30, 0, 0, time.FixedZone("BRT", -3*60*60))
+	purchase, err := NewPurchase("customer-1", "79.379.491/0001-83", data, 10.005)
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, purchase.ID)
+	assert.Equal(t, "customer-1", purchase.CustomerID)
+	assert.Equal(t, "79379491000183", purchase.LojaCnpj)
+	assert.Equal(t, day("2024-03-10"), purchase.Data)
+	assert.Equal(t, 10.01, purchase.Valor)
+}
+
+func TestNewPurchase_Invalid(t *testing.T) {
+	_, err := NewPurchase("customer-1", "NULL", day("2024-03-10"), 10)
+	assert.Equal(t, ErrPurchaseStoreRequired, err)
+
+	_, err = NewPurchase("customer-1", "79379491000183", time.Time{}, 10)
+	assert.Equal(t, ErrPurchaseDateRequired, err)
+
+	_, err = NewPurchase("customer-1", "79379491000183", day("2024-03-10"), 0)
+	assert.Equal(t, ErrPurchaseInvalidAmount, err)
+}
+
+func TestSummarize(t *testing.T) {
+	purchase := func(cnpj string, date string, valor float64) *Purchase {
+		p, _ := NewPurchase("customer-1", cnpj, day(date), valor)
+		return p
+	}
+
+	purchases := []*Purchase{
+		purchase("79379491000183", "2024-01-05", 100),
+		purchase("79379491000850", "2024-03-01", 50),
+		purchase("79379491000183", "2024-02-10", 30),
+		purchase("79379491000850", "2023-12-24", 20.5),
+	}
+	summary, ok := Summarize(purchases)
+
+	assert.True(t, ok)
+	assert.Equal(t, 50.13, summary.TicketMedio)
+	assert.Equal(t, 50.0, summary.TicketUltimaCompra)
+	assert.Equal(t, day("2024-03-01"), summary.DataUltimaCompra)
+	assert.Equal(t, "79379491000850", summary.LojaUltimaCompra)
+	// Two purchases at each store: the one bought at most recently wins.
+	assert.Equal(t, "79379491000850", summary.LojaMaisFrequente)
+
+	summary, _ = Summarize(append(purchases, purchase("79379491000183", "2020-01-01", 10)))
+	assert.Equal(t, "79379491000183", summary.LojaMaisFrequente)
+	assert.Equal(t, "79379491000850", summary.LojaUltimaCompra)
+
+	_, ok = Summarize(nil)
+	assert.False(t, ok)
+}
+
+func TestSummarize_SameDayUsesRecordingOrder(t *testing.T) {
+	first, _ := NewPurchase("customer-1", "79379491000183", day("2024-03-01"), 10)
+	second, _ := NewPurchase("customer-1", "79379491000850", day("2024-03-01"), 20)
+	second.CreatedAt = first.CreatedAt.Add(time.Second)
+
+	summary, _ := Summarize([]*Purchase{second, first})
+	assert.Equal(t, 20.0, summary.TicketUltimaCompra)
+	assert.Equal(t, "79379491000850", summary.LojaUltimaCompra)
+}
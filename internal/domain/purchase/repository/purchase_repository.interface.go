@@ -0,0 +1,32 @@
+package repository
+
+import (
+	customerEntity "neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/purchase/entity"
+	shared "neoway_test/internal/domain/shared/repository"
+	"time"
+)
+
+// PurchaseFilter narrows a purchase query; zero fields match everything.
+type PurchaseFilter struct {
+	CustomerID string
+	LojaCnpj   string
+	From       *time.Time
+	To         *time.Time
+}
+
+// PurchaseRepository stores purchases. Every write derives again the ticket,
+// last purchase and store fields of the customers involved, and logs the
+// change of each in its audit log, in the same transaction.
+type PurchaseRepository interface {
+	shared.RepositoryInterface[entity.Purchase]
+	// WithAudit returns a view of the repository whose changes to customers are
+	// logged as made by info.
+	WithAudit(info customerEntity.AuditInfo) PurchaseRepository
+	// CreateBulk records purchases in a single transaction, creating the stores
+	// they were made at.
+	CreateBulk(purchases []*entity.Purchase) error
+	// Find lists, 100 per page, the purchases matching filter, most recent
+	// first.
+	Find(filter PurchaseFilter, page int) ([]*entity.Purchase, error)
+}
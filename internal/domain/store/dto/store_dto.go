@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type InputGetStoresListDto struct {
+	Page int
+}
+
+type InputGetStoreByCnpjDto struct {
+	Cnpj string
+}
+
+// InputGetStoreCustomersDto lists the customers of a store. Relation narrows
+// them to the customers whose most frequent store (most_frequent) or last
+// purchase store (last_purchase) it is; empty means either.
+type InputGetStoreCustomersDto struct {
+	Cnpj      string
+	Relation  string
+	Page      int
+	CpfFormat string
+}
+
+type OutputGetStoreDto struct {
+	ID         string            `json:"id"`
+	Cnpj       string            `json:"cnpj"`
+	CnpjValido bool              `json:"cnpj_valido"`
+	Nome       string            `json:"nome,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	shared "neoway_test/internal/domain/shared/entity"
+	"strings"
+
+	"github.com/klassmann/cpfcnpj"
+)
+
+const cnpjLength = 14
+
+// Store is a shop customers buy from, identified by the digits of its CNPJ.
+// Stores are created as customers mentioning them are written; the name and
+// metadata are optional.
+type Store struct {
+	shared.BaseEntity
+	Cnpj       string            `json:"cnpj" gorm:"size:20;not null;uniqueIndex"`
+	CnpjValido bool              `json:"cnpj_valido" gorm:"not null"`
+	Nome       string            `json:"nome" gorm:"size:200;not null;default:''"`
+	Metadata   map[string]string `json:"metadata" gorm:"type:jsonb;serializer:json"`
+}
+
+// NewStore returns the store identified by cnpj, or nil when cnpj has no
+// digits, like the "NULL" of a customer without a store.
+func NewStore(cnpj string) *Store {
+	cnpj = NormalizeCnpj(cnpj)
+	if cnpj == "" {
+		return nil
+	}
+
+	store := cpfcnpj.NewCNPJ(cnpj)
+
+	return &Store{
+		BaseEntity: shared.NewBaseEntity(),
+		Cnpj:       cnpj,
+		CnpjValido: store.IsValid(),
+	}
+}
+
+// NormalizeCnpj keeps the digits of a CNPJ and left-pads them with zeros to
+// 14, so "79.379.491/0001-83" and "79379491000183" are the same store. Values
+// without digits normalize to an empty string.
+func NormalizeCnpj(cnpj string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, cnpj)
+
+	if digits != "" && len(digits) < cnpjLength {
+		digits = strings.Repeat("0", cnpjLength-len(digits)) + digits
+	}
+	return digits
+}
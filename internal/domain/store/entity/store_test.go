@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStore(t *testing.T) {
+	store := NewStore("79.379.491/0001-83")
+
+	assert.NotNil(t, store)
+	assert.NotEmpty(t, store.ID)
+	assert.Equal(t, "79379491000183", store.Cnpj)
+	assert.True(t, store.CnpjValido)
+	assert.Empty(t, store.Nome)
+
+	store = NewStore("12.312.312/3123-12")
+	assert.Equal(t, "12312312312312", store.Cnpj)
+	assert.False(t, store.CnpjValido)
+}
+
+func TestNewStore_WithoutCnpj(t *testing.T) {
+	assert.Nil(t, NewStore("NULL"))
+	assert.Nil(t, NewStore(""))
+}
+
+func TestNormalizeCnpj(t *testing.T) {
+	assert.Equal(t, "79379491000183", NormalizeCnpj("79.379.491/0001-83"))
+	assert.Equal(t, "79379491000183", NormalizeCnpj("79379491000183"))
+	assert.Equal(t, "01234567000189", NormalizeCnpj("1234567000189"))
+	assert.Equal(t, "", NormalizeCnpj("NULL"))
+}
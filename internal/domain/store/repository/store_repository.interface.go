@@ -0,0 +1,12 @@
+package repository
+
+import (
+	shared "neoway_test/internal/domain/shared/repository"
+	"neoway_test/internal/domain/store/entity"
+)
+
+type StoreRepository interface {
+	shared.RepositoryInterface[entity.Store]
+	// GetByCnpj finds a store by its CNPJ, with or without punctuation.
+	GetByCnpj(cnpj string) (*entity.Store, error)
+}
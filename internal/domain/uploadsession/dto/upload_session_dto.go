@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"io"
+	"time"
+)
+
+type InputCreateUploadSessionDto struct {
+	FileName        string `json:"file_name"`
+	ContentType     string `json:"content_type"`
+	Format          string `json:"format"`
+	Layout          string `json:"layout"`
+	Encoding        string `json:"encoding"`
+	Strict          bool   `json:"strict"`
+	DuplicatePolicy string `json:"duplicate_policy"`
+	// TotalBytes is the size of the whole file; 0 when unknown.
+	TotalBytes int64 `json:"total_bytes"`
+}
+
+type InputAppendUploadChunkDto struct {
+	ID     string
+	Offset int64
+	Chunk  io.Reader
+}
+
+type InputGetUploadSessionByIdDto struct {
+	ID string
+}
+
+type InputFinalizeUploadSessionDto struct {
+	ID string
+	// Actor and RequestID identify who queued the import in the audit log.
+	Actor     string
+	RequestID string
+}
+
+type OutputUploadSessionDto struct {
+	ID              string    `json:"id"`
+	Status          string    `json:"status"`
+	FileName        string    `json:"file_name"`
+	Format          string    `json:"format"`
+	Layout          string    `json:"layout"`
+	Encoding        string    `json:"encoding,omitempty"`
+	Strict          bool      `json:"strict"`
+	DuplicatePolicy string    `json:"duplicate_policy,omitempty"`
+	TotalBytes      int64     `json:"total_bytes"`
+	ReceivedBytes   int64     `json:"received_bytes"`
+	ImportJobID     string    `json:"import_job_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
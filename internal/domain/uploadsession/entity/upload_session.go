@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	shared "neoway_test/internal/domain/shared/entity"
+	internalerrors "neoway_test/internal/internal-errors"
+	"time"
+)
+
+type UploadSessionStatus string
+
+const (
+	UploadSessionOpen      UploadSessionStatus = "open"
+	UploadSessionFinalized UploadSessionStatus = "finalized"
+)
+
+var (
+	ErrUploadFinalized  = fmt.Errorf("%w: upload session already finalized", internalerrors.ErrConflict)
+	ErrUploadBusy       = fmt.Errorf("%w: another request is changing the upload session", internalerrors.ErrConflict)
+	ErrUploadTooLarge   = errors.New("chunk goes past the declared upload size")
+	ErrUploadIncomplete = errors.New("upload is missing bytes from the declared size")
+)
+
+// ChunkLease is how long a chunk may take to arrive before another request may
+// take over the session, e.g. after the request writing it died. It is long
+// enough that the request it replaces is no longer writing.
+const ChunkLease = time.Hour
+
+// UploadSession tracks a file sent in chunks. ReceivedBytes is the offset the
+// next chunk must start at.
+type UploadSession struct {
+	shared.BaseEntity
+	Status          UploadSessionStatus `json:"status" gorm:"size:20;not null"`
+	FileName        string              `json:"file_name" gorm:"size:255"`
+	ContentType     string              `json:"content_type" gorm:"size:100"`
+	FilePath        string              `json:"-" gorm:"size:500"`
+	Format          string              `json:"format" gorm:"size:20"`
+	Layout          string              `json:"layout" gorm:"size:100"`
+	Encoding        string              `json:"encoding" gorm:"size:20"`
+	Strict          bool                `json:"strict" gorm:"not null;default:false"`
+	DuplicatePolicy string              `json:"duplicate_policy" gorm:"size:20"`
+	TotalBytes      int64               `json:"total_bytes" gorm:"not null;default:0"`
+	ReceivedBytes   int64               `json:"received_bytes" gorm:"not null;default:0"`
+	ImportJobID     string              `json:"import_job_id" gorm:"size:50"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+	// ChunkToken identifies the request writing a chunk, and is empty when
+	// none is; ChunkClaimedAt is when that request claimed the session.
+	ChunkToken     string     `json:"-" gorm:"size:50;not null;default:''"`
+	ChunkClaimedAt *time.Time `json:"-"`
+}
+
+// NewUploadSession opens a session. totalBytes is 0 when the client does not
+// know the size up front.
+func NewUploadSession(fileName string, contentType string, filePath string, format string, layout string, encoding string, strict bool, duplicatePolicy string, totalBytes int64) *UploadSession {
+	base := shared.NewBaseEntity()
+	return &UploadSession{
+		BaseEntity:      base,
+		Status:          UploadSessionOpen,
+		FileName:        fileName,
+		ContentType:     contentType,
+		FilePath:        filePath,
+		Format:          format,
+		Layout:          layout,
+		Encoding:        encoding,
+		Strict:          strict,
+		DuplicatePolicy: duplicatePolicy,
+		TotalBytes:      totalBytes,
+		UpdatedAt:       base.CreatedAt,
+	}
+}
+
+// CheckOffset tells whether a chunk starting at offset can be appended.
+func (s *UploadSession) CheckOffset(offset int64) error {
+	if s.Status != UploadSessionOpen {
+		return ErrUploadFinalized
+	}
+	if offset != s.ReceivedBytes {
+		return fmt.Errorf("%w: expected offset %d, got %d", internalerrors.ErrConflict, s.ReceivedBytes, offset)
+	}
+	return nil
+}
+
+// Remaining is how many bytes may still be received, or -1 when the size is
+// unknown.
+func (s *UploadSession) Remaining() int64 {
+	if s.TotalBytes == 0 {
+		return -1
+	}
+	return s.TotalBytes - s.ReceivedBytes
+}
+
+func (s *UploadSession) Receive(size int64) error {
+	if remaining := s.Remaining(); remaining >= 0 && size > remaining {
+		return ErrUploadTooLarge
+	}
+	s.ReceivedBytes += size
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// CheckFinalize tells whether the upload is complete and can be imported.
+func (s *UploadSession) CheckFinalize() error {
+	if s.Status != UploadSessionOpen {
+		return ErrUploadFinalized
+	}
+	if s.Remaining() > 0 {
+		return ErrUploadIncomplete
+	}
+	return nil
+}
+
+func (s *UploadSession) Finalize(importJobID string) {
+	s.Status = UploadSessionFinalized
+	s.ImportJobID = importJobID
+	s.UpdatedAt = time.Now()
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	internalerrors "neoway_test/internal/internal-errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUploadSession(t *testing.T) {
+	session := NewUploadSession("base.txt", "text/plain", "/tmp/upload-123", "", "", "", false, "", 100)
+
+	assert.NotEmpty(t, session.ID)
+	assert.Equal(t, UploadSessionOpen, session.Status)
+	assert.Equal(t, int64(0), session.ReceivedBytes)
+	assert.Equal(t, int64(100), session.Remaining())
+}
+
+func TestUploadSession_ReceivesChunksInOrder(t *testing.T) {
+	session := NewUploadSession("base.txt", "", "/tmp/upload-123", "", "", "", false, "", 100)
+
+	assert.Nil(t, session.CheckOffset(0))
+	assert.Nil(t, session.Receive(60))
+
+	err := session.CheckOffset(0)
+	assert.ErrorIs(t, err, internalerrors.ErrConflict)
+	assert.Contains(t, err.Error(), "expected offset 60")
+
+	assert.Nil(t, session.CheckOffset(60))
+	assert.Equal(t, ErrUploadTooLarge, session.Receive(41))
+	assert.Equal(t, ErrUploadIncomplete, session.CheckFinalize())
+
+	assert.Nil(t, session.Receive(40))
+	assert.Nil(t, session.CheckFinalize())
+}
+
+func TestUploadSession_UnknownSize(t *testing.T) {
+	session := NewUploadSession("base.txt", "", "/tmp/upload-123", "", "", "", false, "", 0)
+
+	assert.Equal(t, int64(-1), session.Remaining())
+	assert.Nil(t, session.Receive(1<<40))
+	assert.Nil(t, session.CheckFinalize())
+}
+
+func TestUploadSession_Finalize(t *testing.T) {
+	session := NewUploadSession("base.txt", "", "/tmp/upload-123", "", "", "", false, "", 0)
+
+	session.Finalize("job-1")
+
+	assert.Equal(t, UploadSessionFinalized, session.Status)
+	assert.Equal(t, "job-1", session.ImportJobID)
+	assert.ErrorIs(t, session.CheckOffset(0), internalerrors.ErrConflict)
+	assert.Equal(t, ErrUploadFinalized, session.CheckFinalize())
+}
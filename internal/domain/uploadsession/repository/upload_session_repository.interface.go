@@ -0,0 +1,28 @@
+package repository
+
+import (
+	shared "neoway_test/internal/domain/shared/repository"
+	"neoway_test/internal/domain/uploadsession/entity"
+	"time"
+)
+
+type UploadSessionRepository interface {
+	shared.RepositoryInterface[entity.UploadSession]
+	Update(session *entity.UploadSession) error
+	// ClaimChunk reserves the session for the chunk of the request identified
+	// by token, only if it is still open with the bytes it had when read and no
+	// other chunk claimed it within entity.ChunkLease, and reports whether it
+	// did.
+	ClaimChunk(session *entity.UploadSession, token string) (bool, error)
+	// CommitChunk stores the bytes the session received and releases its
+	// claim, only if token still holds it at offset, and reports whether it
+	// did.
+	CommitChunk(session *entity.UploadSession, token string, offset int64) (bool, error)
+	// ClaimFinalize marks the session finalized only if it is still open with
+	// the bytes it had when read and no chunk is being written, and reports
+	// whether it did, so that a single request finalizes it.
+	ClaimFinalize(session *entity.UploadSession) (bool, error)
+	// DeleteStale removes the open sessions last written to before the given
+	// time and returns them, so their files can be removed too.
+	DeleteStale(before time.Time) ([]*entity.UploadSession, error)
+}
@@ -0,0 +1,18 @@
+package dto
+
+import (
+	customerDto "neoway_test/internal/domain/customer/dto"
+	"time"
+)
+
+// OutputWatchedFileReportDto is the sidecar report written next to a processed
+// file in the done/ or failed/ folder.
+type OutputWatchedFileReportDto struct {
+	BatchID    string                                   `json:"batch_id"`
+	FileName   string                                   `json:"file_name"`
+	FileHash   string                                   `json:"file_hash"`
+	Status     string                                   `json:"status"`
+	Error      string                                   `json:"error,omitempty"`
+	Report     *customerDto.OutputCreateCustomerBulkDto `json:"report,omitempty"`
+	FinishedAt time.Time                                `json:"finished_at"`
+}
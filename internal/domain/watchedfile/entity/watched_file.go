@@ -0,0 +1,74 @@
+package entity
+
+import (
+	customerDto "neoway_test/internal/domain/customer/dto"
+	shared "neoway_test/internal/domain/shared/entity"
+	"time"
+)
+
+type WatchedFileStatus string
+
+const (
+	WatchedFileProcessing WatchedFileStatus = "processing"
+	WatchedFileImported   WatchedFileStatus = "imported"
+	WatchedFileFailed     WatchedFileStatus = "failed"
+)
+
+// WatchedFile records a file picked up from the watch folder. The content hash
+// is unique, so the same file is imported only once whatever its name; the ID
+// doubles as the import batch ID of the customers it writes.
+type WatchedFile struct {
+	shared.BaseEntity
+	FileHash   string                                   `json:"file_hash" gorm:"size:64;not null;uniqueIndex"`
+	FileName   string                                   `json:"file_name" gorm:"size:500"`
+	Status     WatchedFileStatus                        `json:"status" gorm:"size:20;not null;index"`
+	Error      string                                   `json:"error"`
+	Report     *customerDto.OutputCreateCustomerBulkDto `json:"report" gorm:"type:jsonb;serializer:json"`
+	FinishedAt *time.Time                               `json:"finished_at"`
+	// HeartbeatAt is when the process importing the file last reported
+	// progress. A file processing whose heartbeat is too old is taken as
+	// abandoned.
+	HeartbeatAt *time.Time `json:"heartbeat_at" gorm:"index"`
+	// Attempt numbers the imports of the file. A run only writes to the file
+	// while its attempt is the current one, so a run taken as abandoned cannot
+	// overwrite the retry that replaced it.
+	Attempt int `json:"attempt" gorm:"not null;default:1"`
+}
+
+func NewWatchedFile(fileName string, fileHash string) *WatchedFile {
+	now := time.Now()
+	return &WatchedFile{
+		BaseEntity:  shared.NewBaseEntity(),
+		FileHash:    fileHash,
+		FileName:    fileName,
+		Status:      WatchedFileProcessing,
+		HeartbeatAt: &now,
+		Attempt:     1,
+	}
+}
+
+// Retry starts a new attempt at a file whose previous import failed.
+func (f *WatchedFile) Retry(fileName string) {
+	now := time.Now()
+	f.FileName = fileName
+	f.HeartbeatAt = &now
+	f.Status = WatchedFileProcessing
+	f.Attempt++
+	f.Error = ""
+	f.Report = nil
+	f.FinishedAt = nil
+}
+
+func (f *WatchedFile) Succeed(report customerDto.OutputCreateCustomerBulkDto) {
+	now := time.Now()
+	f.Status = WatchedFileImported
+	f.Report = &report
+	f.FinishedAt = &now
+}
+
+func (f *WatchedFile) Fail(err error) {
+	now := time.Now()
+	f.Status = WatchedFileFailed
+	f.Error = err.Error()
+	f.FinishedAt = &now
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"errors"
+	customerDto "neoway_test/internal/domain/customer/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWatchedFile(t *testing.T) {
+	file := NewWatchedFile("base.txt", "abc123")
+
+	assert.NotEmpty(t, file.ID)
+	assert.Equal(t, WatchedFileProcessing, file.Status)
+	assert.Equal(t, "base.txt", file.FileName)
+	assert.Equal(t, "abc123", file.FileHash)
+	assert.Nil(t, file.FinishedAt)
+	assert.NotNil(t, file.HeartbeatAt)
+}
+
+func TestWatchedFileLifecycle(t *testing.T) {
+	file := NewWatchedFile("base.txt", "abc123")
+
+	file.Fail(errors.New("internal server error"))
+	assert.Equal(t, WatchedFileFailed, file.Status)
+	assert.Equal(t, "internal server error", file.Error)
+	assert.NotNil(t, file.FinishedAt)
+
+	file.Retry("base_retry.txt")
+	assert.Equal(t, WatchedFileProcessing, file.Status)
+	assert.Equal(t, "base_retry.txt", file.FileName)
+	assert.Equal(t, 2, file.Attempt)
+	assert.Empty(t, file.Error)
+	assert.Nil(t, file.FinishedAt)
+
+	file.Succeed(customerDto.OutputCreateCustomerBulkDto{Accepted: 2})
+	assert.Equal(t, WatchedFileImported, file.Status)
+	assert.Equal(t, 2, file.Report.Accepted)
+	assert.NotNil(t, file.FinishedAt)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"neoway_test/internal/domain/watchedfile/entity"
+	"time"
+)
+
+type WatchedFileRepository interface {
+	Create(file *entity.WatchedFile) error
+	Update(file *entity.WatchedFile) error
+	// GetByHash returns gorm.ErrRecordNotFound when the content was never seen.
+	GetByHash(fileHash string) (*entity.WatchedFile, error)
+	// Retry stores file, moved back to processing by its Retry, only if it is
+	// still failed in the previous attempt, and reports whether it did. It
+	// returns false when another instance retried the same content first.
+	Retry(file *entity.WatchedFile) (bool, error)
+	// Heartbeat records that attempt of the file is still being imported. It
+	// does nothing once the attempt is no longer the current one processing.
+	Heartbeat(id string, attempt int) error
+	// Finish stores the outcome of the current attempt at file. It returns
+	// false, storing nothing, when the attempt was failed as stale or replaced
+	// by a retry in the meantime.
+	Finish(file *entity.WatchedFile) (bool, error)
+	// FailStale marks as failed the files processing whose last heartbeat is
+	// older than staleBefore, left behind by a process that stopped (e.g. a
+	// crash).
+	FailStale(staleBefore time.Time, reason string) error
+}
@@ -1,54 +1,88 @@
 package handlers
 
 import (
+	"fmt"
+	"log"
+	"mime/multipart"
 	"neoway_test/internal/domain/customer/dto"
+	importJobDto "neoway_test/internal/domain/importjob/dto"
 	usecaseCreate "neoway_test/internal/usecase/customer/create"
 	usecaseDelete "neoway_test/internal/usecase/customer/delete"
+	usecaseExport "neoway_test/internal/usecase/customer/export"
 	usecaseFind "neoway_test/internal/usecase/customer/find"
+	usecaseHistory "neoway_test/internal/usecase/customer/history"
 	usecaseList "neoway_test/internal/usecase/customer/list"
+	usecaseRestore "neoway_test/internal/usecase/customer/restore"
+	usecaseRollback "neoway_test/internal/usecase/customer/rollback"
+	usecaseUpdate "neoway_test/internal/usecase/customer/update"
+	usecaseImportJobCreate "neoway_test/internal/usecase/importjob/create"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 )
 
 // CustomerHandler handles HTTP requests for customer operations.
 type CustomerHandler struct {
-	getCustomersListUsecase    *usecaseList.GetCustomersListUseCase
-	createCustomerUsecase      *usecaseCreate.CreateCustomerUseCase
-	createCustomersBulkUsecase *usecaseCreate.CreateCustomerBulkUseCase
-	getCustomerByCpfUsecase    *usecaseFind.GetCustomerByCpfUseCase
-	getCustomerByIdUsecase     *usecaseFind.GetCustomerByIdUseCase
-	deleteCustomersUsecase     *usecaseDelete.DeleteCustomerUseCase
+	getCustomersListUsecase *usecaseList.GetCustomersListUseCase
+	createCustomerUsecase   *usecaseCreate.CreateCustomerUseCase
+	createImportJobUsecase  *usecaseImportJobCreate.CreateImportJobUseCase
+	createBulkUsecase       *usecaseCreate.CreateCustomerBulkUseCase
+	getCustomerByCpfUsecase *usecaseFind.GetCustomerByCpfUseCase
+	getCustomerByIdUsecase  *usecaseFind.GetCustomerByIdUseCase
+	deleteCustomersUsecase  *usecaseDelete.DeleteCustomerUseCase
+	rollbackBatchUsecase    *usecaseRollback.RollbackImportBatchUseCase
+	exportCustomersUsecase  *usecaseExport.ExportCustomersUseCase
+	updateCustomerUsecase   *usecaseUpdate.UpdateCustomerUseCase
+	restoreCustomerUsecase  *usecaseRestore.RestoreCustomerUseCase
+	customerHistoryUsecase  *usecaseHistory.GetCustomerHistoryUseCase
 }
 
 // NewCustomerHandler creates a new CustomerHandler.
 func NewCustomerHandler(
 	getCustomersListUsecase *usecaseList.GetCustomersListUseCase,
 	createCustomerUsecase *usecaseCreate.CreateCustomerUseCase,
-	createCustomersBulkUsecase *usecaseCreate.CreateCustomerBulkUseCase,
+	createImportJobUsecase *usecaseImportJobCreate.CreateImportJobUseCase,
+	createBulkUsecase *usecaseCreate.CreateCustomerBulkUseCase,
 	getCustomerByCpfUsecase *usecaseFind.GetCustomerByCpfUseCase,
 	getCustomerByIdUsecase *usecaseFind.GetCustomerByIdUseCase,
 	deleteCustomersUsecase *usecaseDelete.DeleteCustomerUseCase,
+	rollbackBatchUsecase *usecaseRollback.RollbackImportBatchUseCase,
+	exportCustomersUsecase *usecaseExport.ExportCustomersUseCase,
+	updateCustomerUsecase *usecaseUpdate.UpdateCustomerUseCase,
+	restoreCustomerUsecase *usecaseRestore.RestoreCustomerUseCase,
+	customerHistoryUsecase *usecaseHistory.GetCustomerHistoryUseCase,
 ) *CustomerHandler {
 	return &CustomerHandler{
-		getCustomersListUsecase:    getCustomersListUsecase,
-		createCustomerUsecase:      createCustomerUsecase,
-		createCustomersBulkUsecase: createCustomersBulkUsecase,
-		getCustomerByCpfUsecase:    getCustomerByCpfUsecase,
-		getCustomerByIdUsecase:     getCustomerByIdUsecase,
-		deleteCustomersUsecase:     deleteCustomersUsecase,
+		getCustomersListUsecase: getCustomersListUsecase,
+		createCustomerUsecase:   createCustomerUsecase,
+		createImportJobUsecase:  createImportJobUsecase,
+		createBulkUsecase:       createBulkUsecase,
+		getCustomerByCpfUsecase: getCustomerByCpfUsecase,
+		getCustomerByIdUsecase:  getCustomerByIdUsecase,
+		deleteCustomersUsecase:  deleteCustomersUsecase,
+		rollbackBatchUsecase:    rollbackBatchUsecase,
+		exportCustomersUsecase:  exportCustomersUsecase,
+		updateCustomerUsecase:   updateCustomerUsecase,
+		restoreCustomerUsecase:  restoreCustomerUsecase,
+		customerHistoryUsecase:  customerHistoryUsecase,
 	}
 }
 
 // CustomerPost handles the request to create a new customer.
 // @Summary Create a new customer
-// @Description Create a new customer with the provided details
+// @Description Create a new customer with the provided details. When a customer with the same CPF exists, its ticket, store and date fields are updated instead
 // @Tags Customers
 // @Accept json
 // @Produce json
 // @Param input body dto.InputCreateCustomerDto true "Customer data"
+// @Param X-Actor header string false "Who makes the change, as claimed by the caller and not verified, recorded in the audit log" default(anonymous)
+// @Success 201 {object} map[string]string "Created"
+// @Success 200 {object} map[string]string "Updated"
 // @Failure 400 {object} string "Bad Request"
 // @Failure 500 {object} string "Internal Server Error"
 // @Router /api/v1/customer [post]
@@ -58,6 +92,7 @@ func (h *CustomerHandler) CustomerPost(w http.ResponseWriter, r *http.Request) (
 	if err := render.DecodeJSON(r.Body, &request); err != nil {
 		return nil, http.StatusBadRequest, err // handle JSON decode error
 	}
+	request.Actor, request.RequestID = requestAudit(r)
 
 	output, err := h.createCustomerUsecase.Execute(request)
 
@@ -65,34 +100,142 @@ func (h *CustomerHandler) CustomerPost(w http.ResponseWriter, r *http.Request) (
 		return map[string]string{"id": output.ID}, http.StatusInternalServerError, err
 	}
 
+	if output.Updated {
+		return map[string]string{"id": output.ID}, http.StatusOK, nil
+	}
+
 	return map[string]string{"id": output.ID}, http.StatusCreated, err
 }
 
 // CustomerPostBulk handles the request to create customers in bulk.
 // @Summary Create multiple customers in bulk
-// @Description Create multiple customers from a provided file
+// @Description Queue an import job for the provided file. Follow its progress at /api/v1/importJob/{id}. With dry_run=true the file is only parsed and validated, synchronously, and nothing is written
 // @Tags Customers
 // @Accept multipart/form-data
 // @Produce json
-// @Param file formData file true "CSV file with customer data"
+// @Param file formData file true "Customer file: fixed-width TXT, CSV, TSV or NDJSON, optionally gzip or zip compressed"
+// @Param format query string false "File format (txt, csv, tsv or ndjson); detected from the content type or extension when omitted"
+// @Param layout query string false "Fixed-width layout name" default(neoway)
+// @Param encoding query string false "Character encoding (utf-8, windows-1252, iso-8859-1 or iso-8859-15); taken from the file's charset or detected when omitted"
+// @Param strict query bool false "Reject lines with malformed dates or amounts instead of storing them as NULL or 0 with a warning" default(false)
+// @Param duplicate_policy query string false "Policy for a CPF repeated in the file: keep_first, keep_last, reject or merge"
+// @Param dry_run query bool false "Validate the file without importing it" default(false)
+// @Param X-Actor header string false "Who makes the change, as claimed by the caller and not verified, recorded in the audit log" default(anonymous)
+// @Success 202 {object} importJobDto.OutputImportJobDto
+// @Success 200 {object} dto.OutputCreateCustomerBulkDto "Dry run report"
 // @Failure 400 {object} string "Bad Request"
 // @Failure 500 {object} string "Internal Server Error"
 // @Router /api/v1/customer/bulkCreation [post]
 func (h *CustomerHandler) CustomerPostBulk(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
-	file, _, err := r.FormFile("file")
+	file, err := formFileStream(r, "file")
 
 	if err != nil {
 		return nil, http.StatusBadRequest, err
 	}
 	defer file.Close()
 
-	message, err := h.createCustomersBulkUsecase.Execute(file)
+	strict, err := queryBool(r, "strict")
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	dryRun, err := queryBool(r, "dry_run")
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if dryRun {
+		return h.customerValidateBulk(file, r, strict)
+	}
+
+	input := importJobDto.InputCreateImportJobDto{
+		FileName:        file.FileName(),
+		ContentType:     file.Header.Get("Content-Type"),
+		File:            file,
+		Format:          r.URL.Query().Get("format"),
+		Layout:          r.URL.Query().Get("layout"),
+		Encoding:        r.URL.Query().Get("encoding"),
+		Strict:          strict,
+		DuplicatePolicy: r.URL.Query().Get("duplicate_policy"),
+	}
+	input.Actor, input.RequestID = requestAudit(r)
+
+	output, err := h.createImportJobUsecase.Execute(input)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return output, http.StatusAccepted, err
+}
+
+func (h *CustomerHandler) customerValidateBulk(file *multipart.Part, r *http.Request, strict bool) (interface{}, int, error) {
+	output, err := h.createBulkUsecase.Execute(dto.InputCreateCustomerBulkDto{
+		File:            file,
+		FileName:        file.FileName(),
+		ContentType:     file.Header.Get("Content-Type"),
+		Format:          r.URL.Query().Get("format"),
+		Layout:          r.URL.Query().Get("layout"),
+		Encoding:        r.URL.Query().Get("encoding"),
+		Strict:          strict,
+		DuplicatePolicy: r.URL.Query().Get("duplicate_policy"),
+		DryRun:          true,
+	})
 
 	if err != nil {
 		return nil, http.StatusBadRequest, err
 	}
 
-	return map[string]string{"message": message}, http.StatusCreated, err
+	return output, http.StatusOK, nil
+}
+
+// maxAuditFieldLength is how many characters of the actor and request ID the
+// audit log keeps.
+const maxAuditFieldLength = 200
+
+// requestAudit returns who made the request, from the X-Actor header, and the
+// request ID set by middleware.RequestID, for the audit log. Requests without
+// X-Actor are logged as anonymous. The API does not authenticate its callers,
+// so the actor is whatever the caller claims to be.
+func requestAudit(r *http.Request) (string, string) {
+	actor := strings.TrimSpace(r.Header.Get("X-Actor"))
+	if actor == "" {
+		actor = "anonymous"
+	}
+	return truncate(actor, maxAuditFieldLength), truncate(middleware.GetReqID(r.Context()), maxAuditFieldLength)
+}
+
+func truncate(value string, length int) string {
+	if runes := []rune(value); len(runes) > length {
+		return string(runes[:length])
+	}
+	return value
+}
+
+// queryBool reads an optional boolean query parameter; a missing one is false.
+func queryBool(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// queryDate reads an optional date query parameter, either a plain date
+// (2006-01-02) or an RFC 3339 timestamp; a missing one is nil.
+func queryDate(r *http.Request, name string) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		date, err = time.Parse(time.RFC3339, value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %q", name, value)
+	}
+	return &date, nil
 }
 
 // CustomerGet handles the request to list customers.
@@ -102,6 +245,8 @@ func (h *CustomerHandler) CustomerPostBulk(w http.ResponseWriter, r *http.Reques
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number" default(1)
+// @Param cpf_format query string false "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)" default(digits)
+// @Param include_deleted query bool false "Also list deleted customers" default(false)
 // @Success 200 {array} dto.OutputGetCustomersListDto
 // @Failure 400 {object} string "Bad Request"
 // @Failure 500 {object} string "Internal Server Error"
@@ -113,7 +258,12 @@ func (h *CustomerHandler) CustomerGet(w http.ResponseWriter, r *http.Request) (i
 		page = 1
 	}
 
-	input := dto.InputGetCustomersListDto{Page: page}
+	includeDeleted, err := queryBool(r, "include_deleted")
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	input := dto.InputGetCustomersListDto{Page: page, CpfFormat: r.URL.Query().Get("cpf_format"), IncludeDeleted: includeDeleted}
 
 	customers, err := h.getCustomersListUsecase.Execute(input)
 
@@ -130,14 +280,22 @@ func (h *CustomerHandler) CustomerGet(w http.ResponseWriter, r *http.Request) (i
 // @Accept json
 // @Produce json
 // @Param id path string true "Customer ID"
+// @Param cpf_format query string false "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)" default(digits)
+// @Param include_deleted query bool false "Also find a deleted customer" default(false)
 // @Success 200 {object} dto.OutputGetCustomerDto
+// @Failure 400 {object} string "Bad Request"
 // @Failure 404 {object} string "Customer not found"
 // @Failure 500 {object} string "Internal Server Error"
 // @Router /api/v1/customer/getById/{id} [get]
 func (h *CustomerHandler) CustomerGetById(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
 	id := chi.URLParam(r, "id")
 
-	input := dto.InputGetCustomerByIdDto{ID: id}
+	includeDeleted, err := queryBool(r, "include_deleted")
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	input := dto.InputGetCustomerByIdDto{ID: id, CpfFormat: r.URL.Query().Get("cpf_format"), IncludeDeleted: includeDeleted}
 
 	customer, err := h.getCustomerByIdUsecase.Execute(input)
 	if err == nil && customer == nil {
@@ -148,19 +306,27 @@ func (h *CustomerHandler) CustomerGetById(w http.ResponseWriter, r *http.Request
 
 // CustomerGetByCpf handles the request to get a customer by CPF.
 // @Summary Get customer details by CPF
-// @Description Get details of a customer by CPF
+// @Description Get details of a customer by CPF, sent with or without punctuation
 // @Tags Customers
 // @Accept json
 // @Produce json
 // @Param cpf path string true "Customer CPF"
+// @Param cpf_format query string false "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)" default(digits)
+// @Param include_deleted query bool false "Also find a deleted customer" default(false)
 // @Success 200 {object} dto.OutputGetCustomerDto
+// @Failure 400 {object} string "Bad Request"
 // @Failure 404 {object} string "Customer not found"
 // @Failure 500 {object} string "Internal Server Error"
 // @Router /api/v1/customer/getByCpf/{cpf} [get]
 func (h *CustomerHandler) CustomerGetByCpf(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
 	cpf := chi.URLParam(r, "cpf")
 
-	input := dto.InputGetCustomerByCpfDto{Cpf: cpf}
+	includeDeleted, err := queryBool(r, "include_deleted")
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	input := dto.InputGetCustomerByCpfDto{Cpf: cpf, CpfFormat: r.URL.Query().Get("cpf_format"), IncludeDeleted: includeDeleted}
 
 	customer, err := h.getCustomerByCpfUsecase.Execute(input)
 	if err == nil && customer == nil {
@@ -169,13 +335,81 @@ func (h *CustomerHandler) CustomerGetByCpf(w http.ResponseWriter, r *http.Reques
 	return customer, http.StatusOK, err
 }
 
+// CustomerPut handles the request to replace a customer by ID.
+// @Summary Replace a customer
+// @Description Replace every field of a customer by ID, keeping its ID and creation time. Fields left out are stored as empty. The values are sanitized and validated like a new customer. The customer no longer counts as written by its import, so rolling that import back leaves it alone. For a customer with purchases, the ticket, last purchase and store fields keep the values derived from them
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param cpf_format query string false "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)" default(digits)
+// @Param input body dto.InputCreateCustomerDto true "Customer data"
+// @Param X-Actor header string false "Who makes the change, as claimed by the caller and not verified, recorded in the audit log" default(anonymous)
+// @Success 200 {object} dto.OutputGetCustomerDto
+// @Failure 400 {object} string "Bad Request"
+// @Failure 404 {object} string "Customer not found"
+// @Failure 409 {object} string "CPF belongs to another customer"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/customer/{id} [put]
+func (h *CustomerHandler) CustomerPut(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	var request dto.InputCreateCustomerDto
+
+	if err := render.DecodeJSON(r.Body, &request); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	request.Actor, request.RequestID = requestAudit(r)
+	input := dto.NewInputReplaceCustomerDto(chi.URLParam(r, "id"), request)
+	input.CpfFormat = r.URL.Query().Get("cpf_format")
+
+	customer, err := h.updateCustomerUsecase.Execute(input)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	return customer, http.StatusOK, nil
+}
+
+// CustomerPatch handles the request to change some fields of a customer by ID.
+// @Summary Update a customer
+// @Description Change the given fields of a customer by ID and keep the others. The result is sanitized and validated like a new customer. The customer no longer counts as written by its import, so rolling that import back leaves it alone. For a customer with purchases, the ticket, last purchase and store fields keep the values derived from them
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param cpf_format query string false "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)" default(digits)
+// @Param input body dto.InputUpdateCustomerDto true "Fields to change"
+// @Param X-Actor header string false "Who makes the change, as claimed by the caller and not verified, recorded in the audit log" default(anonymous)
+// @Success 200 {object} dto.OutputGetCustomerDto
+// @Failure 400 {object} string "Bad Request"
+// @Failure 404 {object} string "Customer not found"
+// @Failure 409 {object} string "CPF belongs to another customer"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/customer/{id} [patch]
+func (h *CustomerHandler) CustomerPatch(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	var input dto.InputUpdateCustomerDto
+
+	if err := render.DecodeJSON(r.Body, &input); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	input.ID = chi.URLParam(r, "id")
+	input.CpfFormat = r.URL.Query().Get("cpf_format")
+	input.Actor, input.RequestID = requestAudit(r)
+
+	customer, err := h.updateCustomerUsecase.Execute(input)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	return customer, http.StatusOK, nil
+}
+
 // CustomerDelete handles the request to delete a customer by ID.
 // @Summary Delete a customer
-// @Description Delete a customer by ID
+// @Description Delete a customer by ID. The customer is only marked as deleted and hidden from queries; it can be restored until it is purged
 // @Tags Customers
 // @Accept json
 // @Produce json
 // @Param id path string true "Customer ID"
+// @Param X-Actor header string false "Who makes the change, as claimed by the caller and not verified, recorded in the audit log" default(anonymous)
 // @Success 200 {object} string "Customer successfully deleted"
 // @Failure 404 {object} string "Customer not found"
 // @Failure 500 {object} string "Internal Server Error"
@@ -184,6 +418,7 @@ func (h *CustomerHandler) CustomerDelete(w http.ResponseWriter, r *http.Request)
 	id := chi.URLParam(r, "id")
 
 	input := dto.InputDeleteCustomerDto{ID: id}
+	input.Actor, input.RequestID = requestAudit(r)
 
 	err := h.deleteCustomersUsecase.Execute(input)
 	if err != nil {
@@ -191,3 +426,152 @@ func (h *CustomerHandler) CustomerDelete(w http.ResponseWriter, r *http.Request)
 	}
 	return nil, http.StatusOK, err
 }
+
+// CustomerRestore handles the request to restore a deleted customer by ID.
+// @Summary Restore a deleted customer
+// @Description Bring back a deleted customer that was not purged yet
+// @Tags Customers
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param cpf_format query string false "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)" default(digits)
+// @Param X-Actor header string false "Who makes the change, as claimed by the caller and not verified, recorded in the audit log" default(anonymous)
+// @Success 200 {object} dto.OutputGetCustomerDto
+// @Failure 400 {object} string "Bad Request"
+// @Failure 404 {object} string "Customer not found"
+// @Failure 409 {object} string "Customer is not deleted"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/customer/{id}/restore [post]
+func (h *CustomerHandler) CustomerRestore(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	input := dto.InputRestoreCustomerDto{ID: chi.URLParam(r, "id"), CpfFormat: r.URL.Query().Get("cpf_format")}
+	input.Actor, input.RequestID = requestAudit(r)
+
+	customer, err := h.restoreCustomerUsecase.Execute(input)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	return customer, http.StatusOK, nil
+}
+
+// CustomerHistory handles the request to list the changes to a customer.
+// @Summary List the changes to a customer
+// @Description Get the audit log of a customer by ID, oldest first, 100 entries per page. Each entry has the operation, who made it, the request ID and the fields it changed with their values before and after. The log is kept after the customer is purged
+// @Tags Customers
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param page query int false "Page number" default(1)
+// @Param cpf_format query string false "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)" default(digits)
+// @Success 200 {array} dto.OutputCustomerAuditDto
+// @Failure 400 {object} string "Bad Request"
+// @Failure 404 {object} string "No changes found"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/customer/{id}/history [get]
+func (h *CustomerHandler) CustomerHistory(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	input := dto.InputGetCustomerHistoryDto{
+		ID:        chi.URLParam(r, "id"),
+		Page:      queryPage(r),
+		CpfFormat: r.URL.Query().Get("cpf_format"),
+	}
+
+	history, err := h.customerHistoryUsecase.Execute(input)
+
+	if err == nil && history == nil {
+		return nil, http.StatusNotFound, err
+	}
+	return history, http.StatusOK, err
+}
+
+// CustomerRollbackBatch handles the request to undo an import batch.
+// @Summary Roll back an import batch
+// @Description Delete the customers an import batch created and restore the ones it updated to their previous values, in a single transaction. The batch ID of an import job is the job ID. Customers changed again by a later import or edit are skipped
+// @Tags Customers
+// @Produce json
+// @Param id path string true "Import batch ID"
+// @Param X-Actor header string false "Who makes the change, as claimed by the caller and not verified, recorded in the audit log" default(anonymous)
+// @Success 200 {object} dto.OutputRollbackImportBatchDto
+// @Failure 404 {object} string "Import batch not found"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/customer/importBatch/{id}/rollback [post]
+func (h *CustomerHandler) CustomerRollbackBatch(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	input := dto.InputRollbackImportBatchDto{BatchID: chi.URLParam(r, "id")}
+	input.Actor, input.RequestID = requestAudit(r)
+
+	output, err := h.rollbackBatchUsecase.Execute(input)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return output, http.StatusOK, nil
+}
+
+// CustomerExport handles the request to export customers.
+// @Summary Export customers
+// @Description Stream every customer matching the filters as CSV, TSV, NDJSON or fixed-width TXT. The file uses the columns the bulk import reads, so it can be sent back to /api/v1/customer/bulkCreation unchanged. Rows are read through a database cursor and written as they arrive
+// @Tags Customers
+// @Produce text/csv,text/tab-separated-values,application/x-ndjson,text/plain
+// @Param format query string false "Export format (txt, csv, tsv or ndjson)" default(csv)
+// @Param layout query string false "Fixed-width layout name for txt exports" default(neoway)
+// @Param import_batch_id query string false "Only customers last written by this import batch"
+// @Param cpf_valido query bool false "Only customers whose CPF is (true) or is not (false) valid"
+// @Param created_from query string false "Only customers created on or after this date (2006-01-02 or RFC 3339)"
+// @Param created_to query string false "Only customers created before this date (2006-01-02 or RFC 3339)"
+// @Success 200 {file} file "Customer file"
+// @Failure 400 {object} string "Bad Request"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/customer/export [get]
+func (h *CustomerHandler) CustomerExport(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	input := dto.InputExportCustomersDto{
+		Format:        r.URL.Query().Get("format"),
+		Layout:        r.URL.Query().Get("layout"),
+		ImportBatchID: r.URL.Query().Get("import_batch_id"),
+	}
+
+	if value := r.URL.Query().Get("cpf_valido"); value != "" {
+		cpfValido, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		input.CpfValido = &cpfValido
+	}
+
+	var err error
+	if input.CreatedFrom, err = queryDate(r, "created_from"); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if input.CreatedTo, err = queryDate(r, "created_to"); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	output, err := h.exportCustomersUsecase.Prepare(input)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	w.Header().Set("Content-Type", output.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", output.FileName))
+
+	body := &startedWriter{writer: w}
+	written, err := h.exportCustomersUsecase.Execute(input, body)
+	if err != nil && !body.started {
+		w.Header().Del("Content-Disposition")
+		return nil, http.StatusInternalServerError, err
+	}
+	if err != nil {
+		// Part of the file is already out; dropping the connection keeps the
+		// client from taking it for a complete export.
+		log.Printf("customer export: aborted after %d customers: %v", written, err)
+		panic(http.ErrAbortHandler)
+	}
+
+	return nil, http.StatusOK, nil
+}
+
+// startedWriter records whether anything reached the response, after which
+// the status can no longer change.
+type startedWriter struct {
+	writer  http.ResponseWriter
+	started bool
+}
+
+func (s *startedWriter) Write(p []byte) (int, error) {
+	s.started = true
+	return s.writer.Write(p)
+}
@@ -53,6 +53,8 @@ func handleError(w http.ResponseWriter, r *http.Request, err error) {
 		status = 500
 	case errors.Is(err, gorm.ErrRecordNotFound):
 		status = 404
+	case errors.Is(err, internalerrors.ErrConflict):
+		status = 409
 	default:
 		status = 400
 	}
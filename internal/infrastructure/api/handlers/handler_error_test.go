@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	internalerrors "neoway_test/internal/internal-errors"
 	"net/http"
 	"net/http/httptest"
@@ -41,6 +42,21 @@ func Test_HandlerError_when_endpoint_returns_domain_error(t *testing.T) {
 	assert.Contains(res.Body.String(), "domain error")
 }
 
+func Test_HandlerError_when_endpoint_returns_conflict_error(t *testing.T) {
+	assert := assert.New(t)
+	endpoint := func(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+		return nil, 0, fmt.Errorf("%w: expected offset 10", internalerrors.ErrConflict)
+	}
+	handlerFunc := HandlerError(endpoint)
+	req, _ := http.NewRequest("GET", "/", nil)
+	res := httptest.NewRecorder()
+
+	handlerFunc.ServeHTTP(res, req)
+
+	assert.Equal(http.StatusConflict, res.Code)
+	assert.Contains(res.Body.String(), "expected offset 10")
+}
+
 func Test_HandlerError_when_endpoint_returns_obj_and_status(t *testing.T) {
 	assert := assert.New(t)
 	type bodyForTest struct {
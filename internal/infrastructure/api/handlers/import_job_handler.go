@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"neoway_test/internal/domain/importjob/dto"
+	usecaseImportJobFind "neoway_test/internal/usecase/importjob/find"
+	usecaseImportJobList "neoway_test/internal/usecase/importjob/list"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ImportJobHandler handles HTTP requests for bulk import jobs.
+type ImportJobHandler struct {
+	getImportJobByIdUsecase  *usecaseImportJobFind.GetImportJobByIdUseCase
+	getImportJobsListUsecase *usecaseImportJobList.GetImportJobsListUseCase
+}
+
+// NewImportJobHandler creates a new ImportJobHandler.
+func NewImportJobHandler(
+	getImportJobByIdUsecase *usecaseImportJobFind.GetImportJobByIdUseCase,
+	getImportJobsListUsecase *usecaseImportJobList.GetImportJobsListUseCase,
+) *ImportJobHandler {
+	return &ImportJobHandler{
+		getImportJobByIdUsecase:  getImportJobByIdUsecase,
+		getImportJobsListUsecase: getImportJobsListUsecase,
+	}
+}
+
+// ImportJobGet handles the request to list import jobs.
+// @Summary List import jobs
+// @Description Get a paginated list of bulk import jobs, most recent first
+// @Tags ImportJobs
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Success 200 {array} dto.OutputImportJobDto
+// @Failure 404 {object} string "No import jobs found"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/importJob [get]
+func (h *ImportJobHandler) ImportJobGet(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	input := dto.InputGetImportJobsListDto{Page: page}
+
+	jobs, err := h.getImportJobsListUsecase.Execute(input)
+
+	if err == nil && jobs == nil {
+		return nil, http.StatusNotFound, err
+	}
+	return jobs, http.StatusOK, err
+}
+
+// ImportJobGetById handles the request to get an import job by ID.
+// @Summary Get import job status
+// @Description Get the state, progress and report of a bulk import job
+// @Tags ImportJobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Import job ID"
+// @Success 200 {object} dto.OutputImportJobDto
+// @Failure 404 {object} string "Import job not found"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/importJob/{id} [get]
+func (h *ImportJobHandler) ImportJobGetById(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	id := chi.URLParam(r, "id")
+
+	input := dto.InputGetImportJobByIdDto{ID: id}
+
+	job, err := h.getImportJobByIdUsecase.Execute(input)
+	if err == nil && job == nil {
+		return nil, http.StatusNotFound, err
+	}
+	return job, http.StatusOK, err
+}
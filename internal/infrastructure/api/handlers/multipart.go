@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// formFileStream returns the named file part of a multipart request without
+// buffering the whole body, so large uploads are streamed straight through.
+// The part must be consumed before the request handler returns.
+func formFileStream(r *http.Request, field string) (*multipart.Part, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, http.ErrMissingFile
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == field && part.FileName() != "" {
+			return part, nil
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"neoway_test/internal/domain/purchase/dto"
+	usecasePurchaseCreate "neoway_test/internal/usecase/purchase/create"
+	usecasePurchaseList "neoway_test/internal/usecase/purchase/list"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// PurchaseHandler handles HTTP requests for purchases.
+type PurchaseHandler struct {
+	createPurchasesUsecase  *usecasePurchaseCreate.CreatePurchasesUseCase
+	getPurchasesListUsecase *usecasePurchaseList.GetPurchasesListUseCase
+}
+
+// NewPurchaseHandler creates a new PurchaseHandler.
+func NewPurchaseHandler(
+	createPurchasesUsecase *usecasePurchaseCreate.CreatePurchasesUseCase,
+	getPurchasesListUsecase *usecasePurchaseList.GetPurchasesListUseCase,
+) *PurchaseHandler {
+	return &PurchaseHandler{
+		createPurchasesUsecase:  createPurchasesUsecase,
+		getPurchasesListUsecase: getPurchasesListUsecase,
+	}
+}
+
+// PurchasePost handles the request to record purchases.
+// @Summary Record purchases
+// @Description Record purchases of existing customers, identified by CPF. The ticket, last purchase and store fields of those customers are derived again from all their purchases, and the change is recorded in their audit log. Purchases that cannot be recorded are reported by their position in the request
+// @Tags Purchases
+// @Accept json
+// @Produce json
+// @Param input body []dto.InputCreatePurchaseDto true "Purchases"
+// @Param X-Actor header string false "Who makes the change, as claimed by the caller and not verified, recorded in the audit log" default(anonymous)
+// @Success 201 {object} dto.OutputCreatePurchasesDto
+// @Failure 400 {object} dto.OutputCreatePurchasesDto "No purchase was recorded"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/purchase [post]
+func (h *PurchaseHandler) PurchasePost(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	var request []dto.InputCreatePurchaseDto
+
+	if err := render.DecodeJSON(r.Body, &request); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	input := dto.InputCreatePurchasesDto{Purchases: request}
+	input.Actor, input.RequestID = requestAudit(r)
+	output, err := h.createPurchasesUsecase.Execute(input)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	if output.Accepted == 0 {
+		return output, http.StatusBadRequest, nil
+	}
+	return output, http.StatusCreated, nil
+}
+
+// PurchaseGet handles the request to list purchases.
+// @Summary List purchases
+// @Description Get a paginated list of purchases, most recent first, optionally of one customer, at one store or within a date range
+// @Tags Purchases
+// @Accept json
+// @Produce json
+// @Param cpf query string false "Customer CPF"
+// @Param cnpj query string false "Store CNPJ"
+// @Param from query string false "First day, as YYYY-MM-DD"
+// @Param to query string false "Last day, as YYYY-MM-DD"
+// @Param page query int false "Page number" default(1)
+// @Success 200 {array} dto.OutputGetPurchaseDto
+// @Failure 400 {object} string "Bad Request"
+// @Failure 404 {object} string "Customer or purchases not found"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/purchase [get]
+func (h *PurchaseHandler) PurchaseGet(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	query := r.URL.Query()
+	input := dto.InputGetPurchasesListDto{
+		Cpf:  query.Get("cpf"),
+		Cnpj: query.Get("cnpj"),
+		From: query.Get("from"),
+		To:   query.Get("to"),
+		Page: queryPage(r),
+	}
+
+	purchases, err := h.getPurchasesListUsecase.Execute(input)
+
+	if err == nil && purchases == nil {
+		return nil, http.StatusNotFound, err
+	}
+	return purchases, http.StatusOK, err
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	customerDto "neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/store/dto"
+	usecaseStoreCustomers "neoway_test/internal/usecase/store/customers"
+	usecaseStoreFind "neoway_test/internal/usecase/store/find"
+	usecaseStoreList "neoway_test/internal/usecase/store/list"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// StoreHandler handles HTTP requests for stores.
+type StoreHandler struct {
+	getStoresListUsecase     *usecaseStoreList.GetStoresListUseCase
+	getStoreByCnpjUsecase    *usecaseStoreFind.GetStoreByCnpjUseCase
+	getStoreCustomersUsecase *usecaseStoreCustomers.GetStoreCustomersUseCase
+}
+
+// NewStoreHandler creates a new StoreHandler.
+func NewStoreHandler(
+	getStoresListUsecase *usecaseStoreList.GetStoresListUseCase,
+	getStoreByCnpjUsecase *usecaseStoreFind.GetStoreByCnpjUseCase,
+	getStoreCustomersUsecase *usecaseStoreCustomers.GetStoreCustomersUseCase,
+) *StoreHandler {
+	return &StoreHandler{
+		getStoresListUsecase:     getStoresListUsecase,
+		getStoreByCnpjUsecase:    getStoreByCnpjUsecase,
+		getStoreCustomersUsecase: getStoreCustomersUsecase,
+	}
+}
+
+// StoreGet handles the request to list stores.
+// @Summary List stores
+// @Description Get a paginated list of the stores customers bought from, ordered by CNPJ
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Success 200 {array} dto.OutputGetStoreDto
+// @Failure 404 {object} string "No stores found"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/store [get]
+func (h *StoreHandler) StoreGet(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	input := dto.InputGetStoresListDto{Page: queryPage(r)}
+
+	stores, err := h.getStoresListUsecase.Execute(input)
+
+	if err == nil && stores == nil {
+		return nil, http.StatusNotFound, err
+	}
+	return stores, http.StatusOK, err
+}
+
+// StoreGetByCnpj handles the request to get a store by CNPJ.
+// @Summary Get store details by CNPJ
+// @Description Get a store by CNPJ, sent as digits or with its punctuation URL-encoded
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Param cnpj path string true "Store CNPJ"
+// @Success 200 {object} dto.OutputGetStoreDto
+// @Failure 404 {object} string "Store not found"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/store/{cnpj} [get]
+func (h *StoreHandler) StoreGetByCnpj(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	input := dto.InputGetStoreByCnpjDto{Cnpj: cnpjParam(r)}
+
+	store, err := h.getStoreByCnpjUsecase.Execute(input)
+	if err == nil && store == nil {
+		return nil, http.StatusNotFound, err
+	}
+	return store, http.StatusOK, err
+}
+
+// StoreGetCustomers handles the request to list the customers of a store.
+// @Summary List the customers of a store
+// @Description Get a paginated list of the customers whose most frequent or last purchase store is the given one, oldest first
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Param cnpj path string true "Store CNPJ"
+// @Param relation query string false "Only customers whose most frequent (most_frequent) or last purchase (last_purchase) store it is"
+// @Param page query int false "Page number" default(1)
+// @Param cpf_format query string false "CPF rendering: digits, formatted (000.000.000-00) or masked (***.000.000-**)" default(digits)
+// @Success 200 {array} customerDto.OutputGetCustomersListDto
+// @Failure 400 {object} string "Bad Request"
+// @Failure 404 {object} string "Store or customers not found"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/store/{cnpj}/customers [get]
+func (h *StoreHandler) StoreGetCustomers(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	input := dto.InputGetStoreCustomersDto{
+		Cnpj:      cnpjParam(r),
+		Relation:  r.URL.Query().Get("relation"),
+		Page:      queryPage(r),
+		CpfFormat: r.URL.Query().Get("cpf_format"),
+	}
+
+	var customers []*customerDto.OutputGetCustomersListDto
+	customers, err := h.getStoreCustomersUsecase.Execute(input)
+
+	if err == nil && customers == nil {
+		return nil, http.StatusNotFound, err
+	}
+	return customers, http.StatusOK, err
+}
+
+// cnpjParam reads the CNPJ path parameter. The slash of a punctuated CNPJ
+// arrives encoded as %2F, which would otherwise be read as digits.
+func cnpjParam(r *http.Request) string {
+	cnpj := chi.URLParam(r, "cnpj")
+	if unescaped, err := url.PathUnescape(cnpj); err == nil {
+		return unescaped
+	}
+	return cnpj
+}
+
+// queryPage reads the page query parameter, defaulting to the first page.
+func queryPage(r *http.Request) int {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	return page
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	importJobDto "neoway_test/internal/domain/importjob/dto"
+	"neoway_test/internal/domain/uploadsession/dto"
+	usecaseUploadSessionAppend "neoway_test/internal/usecase/uploadsession/append"
+	usecaseUploadSessionCreate "neoway_test/internal/usecase/uploadsession/create"
+	usecaseUploadSessionFinalize "neoway_test/internal/usecase/uploadsession/finalize"
+	usecaseUploadSessionFind "neoway_test/internal/usecase/uploadsession/find"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// UploadSessionHandler handles HTTP requests for resumable chunked uploads.
+type UploadSessionHandler struct {
+	createUploadSessionUsecase   *usecaseUploadSessionCreate.CreateUploadSessionUseCase
+	appendUploadChunkUsecase     *usecaseUploadSessionAppend.AppendUploadChunkUseCase
+	getUploadSessionByIdUsecase  *usecaseUploadSessionFind.GetUploadSessionByIdUseCase
+	finalizeUploadSessionUsecase *usecaseUploadSessionFinalize.FinalizeUploadSessionUseCase
+}
+
+// NewUploadSessionHandler creates a new UploadSessionHandler.
+func NewUploadSessionHandler(
+	createUploadSessionUsecase *usecaseUploadSessionCreate.CreateUploadSessionUseCase,
+	appendUploadChunkUsecase *usecaseUploadSessionAppend.AppendUploadChunkUseCase,
+	getUploadSessionByIdUsecase *usecaseUploadSessionFind.GetUploadSessionByIdUseCase,
+	finalizeUploadSessionUsecase *usecaseUploadSessionFinalize.FinalizeUploadSessionUseCase,
+) *UploadSessionHandler {
+	return &UploadSessionHandler{
+		createUploadSessionUsecase:   createUploadSessionUsecase,
+		appendUploadChunkUsecase:     appendUploadChunkUsecase,
+		getUploadSessionByIdUsecase:  getUploadSessionByIdUsecase,
+		finalizeUploadSessionUsecase: finalizeUploadSessionUsecase,
+	}
+}
+
+// UploadSessionPost handles the request to open an upload session.
+// @Summary Open a resumable upload
+// @Description Open an upload session for a customer file sent in chunks. The format and layout are the same options accepted by /api/v1/customer/bulkCreation
+// @Tags Uploads
+// @Accept json
+// @Produce json
+// @Param input body dto.InputCreateUploadSessionDto true "Upload data"
+// @Success 201 {object} dto.OutputUploadSessionDto
+// @Failure 400 {object} string "Bad Request"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/upload [post]
+func (h *UploadSessionHandler) UploadSessionPost(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	var request dto.InputCreateUploadSessionDto
+
+	if err := render.DecodeJSON(r.Body, &request); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	output, err := h.createUploadSessionUsecase.Execute(request)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return output, http.StatusCreated, nil
+}
+
+// UploadSessionPut handles the request to append a chunk to an upload.
+// @Summary Send a chunk of a resumable upload
+// @Description Append the request body to the upload. offset must equal the bytes already received; on a mismatch the request fails with 409 and the current offset can be read from GET /api/v1/upload/{id}
+// @Tags Uploads
+// @Accept application/octet-stream
+// @Produce json
+// @Param id path string true "Upload session ID"
+// @Param offset query int true "Position of the chunk in the file"
+// @Success 200 {object} dto.OutputUploadSessionDto
+// @Failure 400 {object} string "Bad Request"
+// @Failure 404 {object} string "Upload session not found"
+// @Failure 409 {object} string "Offset does not match the bytes received, or another chunk is being written"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/upload/{id} [put]
+func (h *UploadSessionHandler) UploadSessionPut(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	offset, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	input := dto.InputAppendUploadChunkDto{
+		ID:     chi.URLParam(r, "id"),
+		Offset: offset,
+		Chunk:  r.Body,
+	}
+
+	output, err := h.appendUploadChunkUsecase.Execute(input)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return output, http.StatusOK, nil
+}
+
+// UploadSessionGetById handles the request to get an upload session.
+// @Summary Get upload progress
+// @Description Get how many bytes of the upload were received, which is the offset of the next chunk
+// @Tags Uploads
+// @Accept json
+// @Produce json
+// @Param id path string true "Upload session ID"
+// @Success 200 {object} dto.OutputUploadSessionDto
+// @Failure 404 {object} string "Upload session not found"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/upload/{id} [get]
+func (h *UploadSessionHandler) UploadSessionGetById(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	input := dto.InputGetUploadSessionByIdDto{ID: chi.URLParam(r, "id")}
+
+	session, err := h.getUploadSessionByIdUsecase.Execute(input)
+	if err == nil && session == nil {
+		return nil, http.StatusNotFound, err
+	}
+	return session, http.StatusOK, err
+}
+
+// UploadSessionFinalize handles the request to finish an upload.
+// @Summary Finish a resumable upload
+// @Description Close a complete upload and queue its import job. Follow its progress at /api/v1/importJob/{id}
+// @Tags Uploads
+// @Accept json
+// @Produce json
+// @Param id path string true "Upload session ID"
+// @Param X-Actor header string false "Who queues the import, as claimed by the caller and not verified, recorded in the audit log of the customers it writes" default(anonymous)
+// @Success 202 {object} importJobDto.OutputImportJobDto
+// @Failure 400 {object} string "Bad Request"
+// @Failure 404 {object} string "Upload session not found"
+// @Failure 409 {object} string "Upload already finalized, or being finalized or written by another request"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /api/v1/upload/{id}/finalize [post]
+func (h *UploadSessionHandler) UploadSessionFinalize(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	input := dto.InputFinalizeUploadSessionDto{ID: chi.URLParam(r, "id")}
+	input.Actor, input.RequestID = requestAudit(r)
+
+	var job importJobDto.OutputImportJobDto
+	job, err := h.finalizeUploadSessionUsecase.Execute(input)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return job, http.StatusAccepted, nil
+}
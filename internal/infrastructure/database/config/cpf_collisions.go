@@ -0,0 +1,262 @@
+package databaseConfig
+
+import (
+	"fmt"
+	"neoway_test/internal/domain/customer/entity"
+	purchaseEntity "neoway_test/internal/domain/purchase/entity"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxListedCollisions bounds how many collisions a CpfCollisionError lists;
+// cmd/dedupe lists them all.
+const maxListedCollisions = 20
+
+// CpfCollision is a CPF held by more than one customer, which the unique index
+// on cpf_normalizado cannot take. IDs are ordered newest first, and the first
+// one is the customer DedupeCpfCollisions keeps.
+type CpfCollision struct {
+	Cpf string
+	IDs []string
+}
+
+// CpfCollisionError stops a migration that would otherwise have to remove
+// customers to create the unique index.
+type CpfCollisionError struct {
+	Collisions []CpfCollision
+}
+
+func (e *CpfCollisionError) Error() string {
+	listed := e.Collisions
+	if len(listed) > maxListedCollisions {
+		listed = listed[:maxListedCollisions]
+	}
+
+	parts := make([]string, len(listed))
+	for i, collision := range listed {
+		parts[i] = fmt.Sprintf("%s (%s)", collision.Cpf, strings.Join(collision.IDs, ", "))
+	}
+	message := fmt.Sprintf("%d CPFs are held by more than one customer, run cmd/dedupe or fix them by hand: %s",
+		len(e.Collisions), strings.Join(parts, "; "))
+	if hidden := len(e.Collisions) - len(listed); hidden > 0 {
+		message += fmt.Sprintf("; and %d more", hidden)
+	}
+	return message
+}
+
+// cpfHolder is a customer holding a CPF, keyed by the CPF it collides on.
+type cpfHolder struct {
+	Cpf       string
+	ID        string
+	CreatedAt time.Time
+}
+
+// FindCpfCollisions lists the CPFs held by more than one customer, deleted or
+// not, ordered by CPF. A customer whose CPF lost its leading zeros counts as
+// holding the CPF normalizeCustomerCpf rewrites it to.
+func FindCpfCollisions(db *gorm.DB) ([]CpfCollision, error) {
+	var holders []cpfHolder
+	err := db.Raw(`SELECT cpf_normalizado AS cpf, id, created_at FROM customers
+		WHERE cpf_normalizado IN (SELECT cpf_normalizado FROM customers
+			WHERE cpf_normalizado <> '' GROUP BY cpf_normalizado HAVING count(*) > 1)`).Scan(&holders).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rewrites, err := findCpfRewrites(db)
+	if err != nil {
+		return nil, err
+	}
+	cpfs := make([]string, len(rewrites))
+	for i, rewrite := range rewrites {
+		cpfs[i] = rewrite.Cpf
+	}
+	holders = append(holders, rewrites...)
+
+	const chunkSize = 1000
+	for start := 0; start < len(cpfs); start += chunkSize {
+		end := min(start+chunkSize, len(cpfs))
+		var current []cpfHolder
+		err := db.Raw(`SELECT cpf_normalizado AS cpf, id, created_at FROM customers WHERE cpf_normalizado IN ?`, cpfs[start:end]).Scan(&current).Error
+		if err != nil {
+			return nil, err
+		}
+		holders = append(holders, current...)
+	}
+
+	return groupCpfHolders(holders), nil
+}
+
+// findCpfRewrites lists the customers whose stored CPF lost leading zeros
+// that entity.NewCpf puts back, keyed by the CPF they are rewritten to.
+func findCpfRewrites(db *gorm.DB) ([]cpfHolder, error) {
+	var short []struct {
+		ID             string
+		Cpf            string
+		CpfNormalizado string
+		CreatedAt      time.Time
+	}
+	err := db.Raw(`SELECT id, cpf, cpf_normalizado, created_at FROM customers
+		WHERE cpf_normalizado <> '' AND length(cpf_normalizado) < 11`).Scan(&short).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var rewrites []cpfHolder
+	for _, customer := range short {
+		if digits := entity.NewCpf(customer.Cpf).Digits(); digits != customer.CpfNormalizado {
+			rewrites = append(rewrites, cpfHolder{Cpf: digits, ID: customer.ID, CreatedAt: customer.CreatedAt})
+		}
+	}
+	return rewrites, nil
+}
+
+// groupCpfHolders gathers the holders of each CPF, newest first, leaving out
+// CPFs with a single holder.
+func groupCpfHolders(holders []cpfHolder) []CpfCollision {
+	sort.Slice(holders, func(i, j int) bool {
+		a, b := holders[i], holders[j]
+		if a.Cpf != b.Cpf {
+			return a.Cpf < b.Cpf
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+
+	var collisions []CpfCollision
+	for start := 0; start < len(holders); {
+		end := start + 1
+		for end < len(holders) && holders[end].Cpf == holders[start].Cpf {
+			end++
+		}
+
+		ids := make([]string, 0, end-start)
+		for _, holder := range holders[start:end] {
+			if len(ids) == 0 || ids[len(ids)-1] != holder.ID {
+				ids = append(ids, holder.ID)
+			}
+		}
+		if len(ids) > 1 {
+			collisions = append(collisions, CpfCollision{Cpf: holders[start].Cpf, IDs: ids})
+		}
+		start = end
+	}
+	return collisions
+}
+
+// DedupeCpfCollisions resolves every collision in one transaction, keeping
+// the newest customer of each CPF and removing the others for good. Their
+// purchases move to the customer kept, whose purchase fields are derived
+// again, and, when the audit log exists, the changes are logged for actor.
+// It returns the collisions it resolved.
+func DedupeCpfCollisions(db *gorm.DB, actor string) ([]CpfCollision, error) {
+	var collisions []CpfCollision
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		collisions, err = FindCpfCollisions(tx)
+		if err != nil {
+			return err
+		}
+
+		migrator := tx.Migrator()
+		hasPurchases := migrator.HasTable(&purchaseEntity.Purchase{})
+		hasSnapshots := migrator.HasTable(&entity.CustomerSnapshot{})
+		hasAudits := migrator.HasTable(&entity.CustomerAudit{})
+
+		for _, collision := range collisions {
+			if err := dedupeCpfCollision(tx, collision, hasPurchases, hasSnapshots, hasAudits, actor); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return collisions, nil
+}
+
+func dedupeCpfCollision(tx *gorm.DB, collision CpfCollision, hasPurchases bool, hasSnapshots bool, hasAudits bool, actor string) error {
+	kept, removed := collision.IDs[0], collision.IDs[1:]
+
+	var before []*entity.Customer
+	if err := tx.Unscoped().Where("id IN ?", collision.IDs).Find(&before).Error; err != nil {
+		return err
+	}
+
+	if hasPurchases {
+		moved := tx.Model(&purchaseEntity.Purchase{}).Where("customer_id IN ?", removed).Update("customer_id", kept)
+		if moved.Error != nil {
+			return moved.Error
+		}
+		if moved.RowsAffected > 0 {
+			if err := resummarizeCustomer(tx, kept); err != nil {
+				return err
+			}
+		}
+	}
+	if hasSnapshots {
+		if err := tx.Where("customer_id IN ?", removed).Delete(&entity.CustomerSnapshot{}).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Unscoped().Where("id IN ?", removed).Delete(&entity.Customer{}).Error; err != nil {
+		return err
+	}
+
+	if !hasAudits {
+		return nil
+	}
+
+	var after []*entity.Customer
+	if err := tx.Unscoped().Where("id = ?", kept).Find(&after).Error; err != nil {
+		return err
+	}
+	info := entity.AuditInfo{Actor: actor}
+	var audits []*entity.CustomerAudit
+	for _, customer := range before {
+		var audit *entity.CustomerAudit
+		if customer.ID == kept && len(after) > 0 {
+			audit = entity.NewCustomerAudit(info, entity.AuditOperationUpdate, "", customer, after[0])
+		} else if customer.ID != kept {
+			audit = entity.NewCustomerAudit(info, entity.AuditOperationPurge, "", customer, nil)
+		}
+		if audit != nil {
+			audits = append(audits, audit)
+		}
+	}
+	if len(audits) == 0 {
+		return nil
+	}
+	return tx.Create(audits).Error
+}
+
+// resummarizeCustomer derives the purchase fields of the customer again, now
+// that it holds the purchases of the customers it was deduplicated from.
+func resummarizeCustomer(tx *gorm.DB, customerID string) error {
+	var purchases []*purchaseEntity.Purchase
+	if err := tx.Where("customer_id = ?", customerID).Find(&purchases).Error; err != nil {
+		return err
+	}
+
+	summary, _ := purchaseEntity.Summarize(purchases)
+	customer := &entity.Customer{}
+	customer.ID = customerID
+	customer.ApplyPurchases(summary)
+	return tx.Unscoped().Model(customer).Select(
+		"data_ultima_compra",
+		"ticket_medio",
+		"ticket_ultima_compra",
+		"loja_mais_frequente",
+		"cnpj_loja_mais_frequente_valido",
+		"loja_mais_frequente_cnpj",
+		"loja_ultima_compra",
+		"cnpj_loja_ultima_compra_valido",
+		"loja_ultima_compra_cnpj",
+	).Updates(customer).Error
+}
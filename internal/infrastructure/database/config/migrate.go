@@ -0,0 +1,280 @@
+package databaseConfig
+
+import (
+	"fmt"
+	"neoway_test/internal/domain/customer/entity"
+	importJobEntity "neoway_test/internal/domain/importjob/entity"
+	purchaseEntity "neoway_test/internal/domain/purchase/entity"
+	storeEntity "neoway_test/internal/domain/store/entity"
+	uploadSessionEntity "neoway_test/internal/domain/uploadsession/entity"
+	watchedFileEntity "neoway_test/internal/domain/watchedfile/entity"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Migrate brings the schema up to date. Data fixes that AutoMigrate cannot
+// express run first, so the indexes it creates find the rows already in shape.
+func Migrate(db *gorm.DB) error {
+	if err := backfillCustomerCpf(db); err != nil {
+		return err
+	}
+	if err := checkCpfCollisions(db); err != nil {
+		return err
+	}
+	if err := normalizeCustomerCpf(db); err != nil {
+		return err
+	}
+	if err := convertCustomerFlags(db); err != nil {
+		return err
+	}
+	if err := backfillCustomerStores(db); err != nil {
+		return err
+	}
+
+	return db.AutoMigrate(&storeEntity.Store{}, &entity.Customer{}, &entity.CustomerSnapshot{}, &entity.CustomerAudit{}, &purchaseEntity.Purchase{}, &importJobEntity.ImportJob{}, &uploadSessionEntity.UploadSession{}, &watchedFileEntity.WatchedFile{})
+}
+
+// backfillCustomerCpf fills the normalized CPF of customers created before the
+// column existed.
+func backfillCustomerCpf(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&entity.Customer{}) || migrator.HasColumn(&entity.Customer{}, "CpfNormalizado") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&entity.Customer{}, "CpfNormalizado"); err != nil {
+			return err
+		}
+
+		return tx.Exec(`UPDATE customers SET cpf_normalizado = regexp_replace(cpf, '[^0-9]', '', 'g')`).Error
+	})
+}
+
+// checkCpfCollisions fails the migration while customers share a CPF, since
+// the unique index on cpf_normalizado cannot be created over them. Nothing is
+// removed here: an operator resolves the collisions with cmd/dedupe.
+func checkCpfCollisions(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&entity.Customer{}) || migrator.HasIndex(&entity.Customer{}, "idx_customers_cpf_normalizado") {
+		return nil
+	}
+
+	collisions, err := FindCpfCollisions(db)
+	if err != nil {
+		return err
+	}
+	if len(collisions) > 0 {
+		return &CpfCollisionError{Collisions: collisions}
+	}
+	return nil
+}
+
+// normalizeCustomerCpf rewrites, once, the CPFs stored with punctuation, or
+// without leading zeros a spreadsheet dropped, as the digits entity.Cpf keeps.
+// It fails listing the customers that would end up sharing a CPF, for
+// cmd/dedupe to resolve, instead of removing any of them.
+func normalizeCustomerCpf(db *gorm.DB) error {
+	return runOnce(db, "normalize_customer_cpf", func(tx *gorm.DB) error {
+		if !tx.Migrator().HasTable(&entity.Customer{}) {
+			return nil
+		}
+
+		collisions, err := FindCpfCollisions(tx)
+		if err != nil {
+			return err
+		}
+		if len(collisions) > 0 {
+			return &CpfCollisionError{Collisions: collisions}
+		}
+
+		rewrites, err := findCpfRewrites(tx)
+		if err != nil {
+			return err
+		}
+		for _, rewrite := range rewrites {
+			err := tx.Exec(`UPDATE customers SET cpf = ?, cpf_normalizado = ?, cpf_valido = ? WHERE id = ?`,
+				rewrite.Cpf, rewrite.Cpf, entity.NewCpf(rewrite.Cpf).IsValid(), rewrite.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Exec(`UPDATE customers SET cpf = cpf_normalizado
+			WHERE cpf_normalizado <> '' AND cpf <> cpf_normalizado`).Error
+	})
+}
+
+// convertCustomerFlags turns private and incompleto, which used to hold the
+// text read from the file, into boolean columns. 1, true and S become true;
+// 0, false, N, blanks and NULL become false. Any other value fails the
+// migration, listing the values to fix, instead of being guessed.
+func convertCustomerFlags(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&entity.Customer{}) {
+		return nil
+	}
+
+	columnTypes, err := migrator.ColumnTypes(&entity.Customer{})
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, column := range columnTypes {
+		name := column.Name()
+		if (name == "private" || name == "incompleto") && !strings.EqualFold(column.DatabaseTypeName(), "bool") {
+			names = append(names, name)
+		}
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, name := range names {
+			var unknown []string
+			err := tx.Raw(fmt.Sprintf(`SELECT DISTINCT %[1]s FROM customers
+				WHERE lower(trim(%[1]s)) NOT IN ('', '1', 'true', 's', '0', 'false', 'n')
+				ORDER BY 1`, name)).Scan(&unknown).Error
+			if err != nil {
+				return err
+			}
+			if len(unknown) > 0 {
+				return fmt.Errorf("customers.%s holds values that are not a yes/no flag, fix them before migrating: %s",
+					name, quoteAll(unknown))
+			}
+
+			err = tx.Exec(fmt.Sprintf(`ALTER TABLE customers
+				ALTER COLUMN %[1]s TYPE boolean USING coalesce(lower(trim(%[1]s)) IN ('1', 'true', 's'), false),
+				ALTER COLUMN %[1]s SET DEFAULT false,
+				ALTER COLUMN %[1]s SET NOT NULL`, name)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// backfillCustomerStores creates the stores of customers written before stores
+// existed and points the customers, and the snapshots kept for rollbacks, at
+// them. AutoMigrate adds the foreign keys afterwards.
+func backfillCustomerStores(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&entity.Customer{}) || migrator.HasColumn(&entity.Customer{}, "LojaMaisFrequenteCnpj") {
+		return nil
+	}
+
+	models := []interface{}{&entity.Customer{}}
+	tables := []string{"customers"}
+	if migrator.HasTable(&entity.CustomerSnapshot{}) {
+		models = append(models, &entity.CustomerSnapshot{})
+		tables = append(tables, "customer_snapshots")
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&storeEntity.Store{}); err != nil {
+			return err
+		}
+		for _, model := range models {
+			for _, column := range []string{"LojaMaisFrequenteCnpj", "LojaUltimaCompraCnpj"} {
+				if err := tx.Migrator().AddColumn(model, column); err != nil {
+					return err
+				}
+			}
+		}
+
+		// The CNPJ check digits are validated in Go, so the distinct values are
+		// copied into a mapping table and completed in pages; stores and CNPJ
+		// columns are then filled from it with one statement each.
+		var selects []string
+		for _, table := range tables {
+			selects = append(selects, fmt.Sprintf("SELECT loja_mais_frequente FROM %[1]s UNION SELECT loja_ultima_compra FROM %[1]s", table))
+		}
+		err := tx.Exec(`CREATE TEMPORARY TABLE store_backfill (
+			loja text PRIMARY KEY, id varchar(50), cnpj varchar(20), cnpj_valido boolean, created_at timestamptz) ON COMMIT DROP`).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec("INSERT INTO store_backfill (loja) SELECT loja FROM (" + strings.Join(selects, " UNION ") + ") AS lojas (loja) WHERE loja IS NOT NULL").Error
+		if err != nil {
+			return err
+		}
+		if err := fillStoreBackfill(tx); err != nil {
+			return err
+		}
+
+		err = tx.Exec(`INSERT INTO stores (id, created_at, cnpj, cnpj_valido)
+			SELECT DISTINCT ON (cnpj) id, created_at, cnpj, cnpj_valido FROM store_backfill
+			WHERE cnpj IS NOT NULL ORDER BY cnpj, loja
+			ON CONFLICT (cnpj) DO NOTHING`).Error
+		if err != nil {
+			return err
+		}
+
+		for _, table := range tables {
+			for _, column := range []string{"loja_mais_frequente", "loja_ultima_compra"} {
+				err := tx.Exec(fmt.Sprintf("UPDATE %[1]s SET %[2]s_cnpj = b.cnpj FROM store_backfill b WHERE %[1]s.%[2]s = b.loja AND b.cnpj IS NOT NULL", table, column)).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// storeBackfillPage is how many store values fillStoreBackfill reads at once.
+const storeBackfillPage = 1000
+
+type storeBackfill struct {
+	Loja       string
+	ID         string
+	Cnpj       string
+	CnpjValido bool
+	CreatedAt  time.Time
+}
+
+// fillStoreBackfill completes each store value of store_backfill with the
+// store NewStore makes of it. Values without digits keep a NULL cnpj.
+func fillStoreBackfill(tx *gorm.DB) error {
+	last := ""
+	for {
+		var lojas []string
+		err := tx.Raw("SELECT loja FROM store_backfill WHERE loja > ? ORDER BY loja LIMIT ?", last, storeBackfillPage).Scan(&lojas).Error
+		if err != nil {
+			return err
+		}
+		if len(lojas) == 0 {
+			return nil
+		}
+		last = lojas[len(lojas)-1]
+
+		var page []storeBackfill
+		for _, loja := range lojas {
+			if store := storeEntity.NewStore(loja); store != nil {
+				page = append(page, storeBackfill{Loja: loja, ID: store.ID, Cnpj: store.Cnpj, CnpjValido: store.CnpjValido, CreatedAt: store.CreatedAt})
+			}
+		}
+		if len(page) == 0 {
+			continue
+		}
+
+		err = tx.Table("store_backfill").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "loja"}},
+			DoUpdates: clause.AssignmentColumns([]string{"id", "cnpj", "cnpj_valido", "created_at"}),
+		}).Create(&page).Error
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package databaseConfig
 
 import (
-	"neoway_test/internal/domain/customer/entity"
 	"os"
 
 	"gorm.io/driver/postgres"
@@ -9,14 +8,22 @@ import (
 )
 
 func NewDb() *gorm.DB {
-	dsn := os.Getenv("POSTGRES_FULL_URL")
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := Open()
 
 	if err != nil {
 		panic("fail to connect to database")
 	}
 
-	db.AutoMigrate(&entity.Customer{})
+	if err := Migrate(db); err != nil {
+		panic("fail to migrate database: " + err.Error())
+	}
 
 	return db
 }
+
+// Open connects to POSTGRES_FULL_URL without migrating, for maintenance
+// commands that must run on a schema Migrate refuses to bring up to date.
+func Open() (*gorm.DB, error) {
+	dsn := os.Getenv("POSTGRES_FULL_URL")
+	return gorm.Open(postgres.Open(dsn), &gorm.Config{})
+}
@@ -0,0 +1,46 @@
+package databaseConfig
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// schemaMigration records a one-shot data migration that already ran, so it
+// is not repeated on every startup.
+type schemaMigration struct {
+	Version   string    `gorm:"primaryKey;size:100"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// runOnce runs migrate in a transaction and records version, unless version
+// was recorded before. The table lock keeps two instances starting together
+// from running the same migration twice.
+func runOnce(db *gorm.DB, version string, migrate func(tx *gorm.DB) error) error {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE schema_migrations IN EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		var applied int64
+		if err := tx.Model(&schemaMigration{}).Where("version = ?", version).Count(&applied).Error; err != nil {
+			return err
+		}
+		if applied > 0 {
+			return nil
+		}
+
+		if err := migrate(tx); err != nil {
+			return err
+		}
+		return tx.Create(&schemaMigration{Version: version, AppliedAt: time.Now()}).Error
+	})
+}
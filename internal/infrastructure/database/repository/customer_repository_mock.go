@@ -2,6 +2,8 @@ package databaseRepository
 
 import (
 	"neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/repository"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -20,6 +22,66 @@ func (r *CustomerRepositoryMock) CreateBulk(customers []*entity.Customer) error
 	return args.Error(0)
 }
 
+func (r *CustomerRepositoryMock) WithDeleted() repository.CustomerRepository {
+	args := r.Called()
+	return args.Get(0).(repository.CustomerRepository)
+}
+
+func (r *CustomerRepositoryMock) WithAudit(info entity.AuditInfo) repository.CustomerRepository {
+	args := r.Called(info)
+	return args.Get(0).(repository.CustomerRepository)
+}
+
+func (r *CustomerRepositoryMock) GetHistory(customerID string, page int) ([]*entity.CustomerAudit, error) {
+	args := r.Called(customerID, page)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.CustomerAudit), nil
+}
+
+func (r *CustomerRepositoryMock) Restore(customer *entity.Customer) error {
+	args := r.Called(customer)
+	return args.Error(0)
+}
+
+func (r *CustomerRepositoryMock) Purge(deletedBefore time.Time) (int, error) {
+	args := r.Called(deletedBefore)
+	return args.Int(0), args.Error(1)
+}
+
+func (r *CustomerRepositoryMock) Update(customer *entity.Customer) error {
+	args := r.Called(customer)
+	return args.Error(0)
+}
+
+func (r *CustomerRepositoryMock) Upsert(customer *entity.Customer) (bool, error) {
+	args := r.Called(customer)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *CustomerRepositoryMock) UpsertBulk(customers []*entity.Customer) (int, int, int, error) {
+	args := r.Called(customers)
+	return args.Int(0), args.Int(1), args.Int(2), args.Error(3)
+}
+
+func (r *CustomerRepositoryMock) RollbackBatch(batchID string) (int, int, int, error) {
+	args := r.Called(batchID)
+	return args.Int(0), args.Int(1), args.Int(2), args.Error(3)
+}
+
+// Stream yields the customers given to Return before returning its error.
+func (r *CustomerRepositoryMock) Stream(filter repository.CustomerFilter, yield func(*entity.Customer) error) error {
+	args := r.Called(filter)
+	customers, _ := args.Get(0).([]*entity.Customer)
+	for _, customer := range customers {
+		if err := yield(customer); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
 func (r *CustomerRepositoryMock) Get(page int) ([]*entity.Customer, error) {
 	args := r.Called(page)
 	if args.Error(1) != nil {
@@ -44,6 +106,14 @@ func (r *CustomerRepositoryMock) GetByCpf(cpf string) (*entity.Customer, error)
 	return args.Get(0).(*entity.Customer), nil
 }
 
+func (r *CustomerRepositoryMock) GetByStore(cnpj string, relation string, page int) ([]*entity.Customer, error) {
+	args := r.Called(cnpj, relation, page)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Customer), nil
+}
+
 func (r *CustomerRepositoryMock) Delete(customer *entity.Customer) error {
 	args := r.Called(customer)
 	return args.Error(0)
@@ -1,14 +1,96 @@
 package databaseRepository
 
 import (
+	"errors"
+	"fmt"
 	"neoway_test/internal/domain/customer/entity"
 	"neoway_test/internal/domain/customer/repository"
+	shared "neoway_test/internal/domain/shared/entity"
+	storeEntity "neoway_test/internal/domain/store/entity"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// customerUpsertColumns are refreshed when a customer with the same normalized
+// CPF already exists. Clearing deleted_at brings back a deleted customer.
+var customerUpsertColumns = []string{
+	"data_ultima_compra",
+	"ticket_medio",
+	"ticket_ultima_compra",
+	"loja_mais_frequente",
+	"cnpj_loja_mais_frequente_valido",
+	"loja_ultima_compra",
+	"cnpj_loja_ultima_compra_valido",
+	"loja_mais_frequente_cnpj",
+	"loja_ultima_compra_cnpj",
+	"import_batch_id",
+	"source_file_name",
+	"source_file_hash",
+	"source_line_number",
+	"deleted_at",
+}
+
+// customerUpdateColumns are written by Update; the ID and creation time stay
+// as they were.
+var customerUpdateColumns = []string{
+	"cpf",
+	"cpf_normalizado",
+	"cpf_valido",
+	"private",
+	"incompleto",
+	"data_ultima_compra",
+	"ticket_medio",
+	"ticket_ultima_compra",
+	"loja_mais_frequente",
+	"cnpj_loja_mais_frequente_valido",
+	"loja_ultima_compra",
+	"cnpj_loja_ultima_compra_valido",
+	"loja_mais_frequente_cnpj",
+	"loja_ultima_compra_cnpj",
+	"import_batch_id",
+	"source_file_name",
+	"source_file_hash",
+	"source_line_number",
+}
+
+// snapshotColumn is the customer_snapshots column that keeps the previous value
+// of an upsert column.
+func snapshotColumn(column string) string {
+	if column == "import_batch_id" {
+		return "previous_import_batch_id"
+	}
+	return column
+}
+
+// snapshotStatement saves, for the @batch import batch, the current upsert
+// columns of the customers whose cpf_normalizado matches cpfs. Customers the
+// batch already owns are left out: the batch either created them or has
+// already saved their previous state.
+func snapshotStatement(cpfs string) string {
+	columns := make([]string, len(customerUpsertColumns))
+	values := make([]string, len(customerUpsertColumns))
+	for i, column := range customerUpsertColumns {
+		columns[i] = pgx.Identifier{snapshotColumn(column)}.Sanitize()
+		values[i] = "c." + pgx.Identifier{column}.Sanitize()
+	}
+
+	return fmt.Sprintf(`INSERT INTO customer_snapshots (import_batch_id, customer_id, %s, created_at)
+		SELECT @batch, c.id, %s, now() FROM customers c
+		WHERE c.cpf_normalizado IN %s AND c.cpf_normalizado <> '' AND c.import_batch_id <> @batch
+		ON CONFLICT DO NOTHING`, strings.Join(columns, ", "), strings.Join(values, ", "), cpfs)
+}
+
+// customerConflictTarget matches the partial unique index on cpf_normalizado.
+const customerConflictTarget = "(cpf_normalizado) WHERE cpf_normalizado <> ''"
+
 type CustomerRepositoryPostgres struct {
 	Db *gorm.DB
+	// audit attributes the changes written to the audit log.
+	audit entity.AuditInfo
 }
 
 func NewPostgresCustomerRepository(db *gorm.DB) (repository.CustomerRepository, error) {
@@ -16,13 +98,552 @@ func NewPostgresCustomerRepository(db *gorm.DB) (repository.CustomerRepository,
 }
 
 func (c *CustomerRepositoryPostgres) Create(customer *entity.Customer) error {
-	tx := c.Db.Create(customer)
-	return tx.Error
+	return c.Db.Transaction(func(tx *gorm.DB) error {
+		if err := saveCustomerStores(tx, []*entity.Customer{customer}); err != nil {
+			return err
+		}
+		if err := tx.Create(customer).Error; err != nil {
+			return err
+		}
+		return c.auditWrites(tx, []*entity.Customer{customer}, nil)
+	})
 }
 
+// saveCustomerStores creates the stores customers refer to that are not
+// stored yet, so the store foreign keys of the customers hold.
+func saveCustomerStores(tx *gorm.DB, customers []*entity.Customer) error {
+	var cnpjs []string
+	for _, customer := range customers {
+		for _, cnpj := range []*string{customer.LojaMaisFrequenteCnpj, customer.LojaUltimaCompraCnpj} {
+			if cnpj != nil {
+				cnpjs = append(cnpjs, *cnpj)
+			}
+		}
+	}
+	return saveStores(tx, cnpjs)
+}
+
+// CreateBulk loads customers with COPY and falls back to batched INSERTs when
+// COPY is not available, which is known before anything is written. Any other
+// error is returned as is.
 func (c *CustomerRepositoryPostgres) CreateBulk(customers []*entity.Customer) error {
-	tx := c.Db.CreateInBatches(customers, 1000) // Insert in batches of 1000
-	return tx.Error
+	err := c.CreateBulkCopy(customers)
+	if errors.Is(err, errCopyUnsupported) {
+		return c.CreateBulkInsert(customers)
+	}
+	return err
+}
+
+// CreateBulkCopy loads customers with COPY FROM STDIN. The stores, the copy
+// and the audit log are written in one transaction.
+func (c *CustomerRepositoryPostgres) CreateBulkCopy(customers []*entity.Customer) error {
+	if len(customers) == 0 {
+		return nil
+	}
+
+	source, err := newCopySource(c.Db, customers)
+	if err != nil {
+		return err
+	}
+
+	return withCopyTx(c.Db, func(tx *gorm.DB, copyRows copyInto) error {
+		if err := saveCustomerStores(tx, customers); err != nil {
+			return err
+		}
+		if err := copyRows(source.table, source); err != nil {
+			return err
+		}
+		return c.auditWrites(tx, customers, nil)
+	})
+}
+
+// CreateBulkInsert loads customers with multi-row INSERTs.
+func (c *CustomerRepositoryPostgres) CreateBulkInsert(customers []*entity.Customer) error {
+	return c.Db.Transaction(func(tx *gorm.DB) error {
+		if err := saveCustomerStores(tx, customers); err != nil {
+			return err
+		}
+		if err := tx.CreateInBatches(customers, 1000).Error; err != nil { // Insert in batches of 1000
+			return err
+		}
+		return c.auditWrites(tx, customers, nil)
+	})
+}
+
+// Upsert creates the customer or, when its CPF is already stored, updates the
+// ticket, store and date fields of the stored row. customer.ID is set to the ID
+// of the stored row.
+func (c *CustomerRepositoryPostgres) Upsert(customer *entity.Customer) (bool, error) {
+	inserted, _, err := c.upsertBulkInsert([]*entity.Customer{customer})
+	return inserted == 1, err
+}
+
+// UpsertBulk upserts customers like Upsert and reports how many were inserted,
+// how many updated an existing row and how many were superseded. Rows sharing
+// a CPF within the batch are applied in order, so the last one wins and the
+// others are superseded.
+func (c *CustomerRepositoryPostgres) UpsertBulk(customers []*entity.Customer) (int, int, int, error) {
+	customers, superseded := dedupeCustomersByCpf(customers)
+	if len(customers) == 0 {
+		return 0, 0, superseded, nil
+	}
+
+	inserted, updated, err := c.upsertBulkCopy(customers)
+	if errors.Is(err, errCopyUnsupported) {
+		inserted, updated, err = c.upsertBulkInsert(customers)
+	}
+	return inserted, updated, superseded, err
+}
+
+// upsertBulkCopy copies the batch into a temporary staging table and merges it
+// into customers with a single INSERT ... ON CONFLICT. The merge, the
+// purchase summaries and the audit log are written in one transaction.
+func (c *CustomerRepositoryPostgres) upsertBulkCopy(customers []*entity.Customer) (int, int, error) {
+	source, err := newCopySource(c.Db, customers)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	table := pgx.Identifier{source.table}.Sanitize()
+	stagingTable := source.table + "_staging"
+	staging := pgx.Identifier{stagingTable}.Sanitize()
+	columns := make([]string, len(source.columns))
+	for i, column := range source.columns {
+		columns[i] = pgx.Identifier{column}.Sanitize()
+	}
+	updates := make([]string, len(customerUpsertColumns))
+	for i, column := range customerUpsertColumns {
+		updates[i] = fmt.Sprintf("%[1]s = EXCLUDED.%[1]s", pgx.Identifier{column}.Sanitize())
+	}
+
+	batchID := customers[0].ImportBatchID
+	var inserted, updated int
+	err = withCopyTx(c.Db, func(tx *gorm.DB, copyRows copyInto) error {
+		if err := saveCustomerStores(tx, customers); err != nil {
+			return err
+		}
+		before, err := findCustomersByCpf(tx, customers)
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec(fmt.Sprintf("CREATE TEMP TABLE %s (LIKE %s INCLUDING DEFAULTS) ON COMMIT DROP", staging, table)).Error; err != nil {
+			return err
+		}
+		if err := copyRows(stagingTable, source); err != nil {
+			return err
+		}
+		if batchID != "" {
+			snapshot := snapshotStatement(fmt.Sprintf("(SELECT cpf_normalizado FROM %s)", staging))
+			if err := tx.Exec(snapshot, map[string]interface{}{"batch": batchID}).Error; err != nil {
+				return err
+			}
+		}
+
+		// xmax is zero only for rows created by this statement.
+		rows, err := tx.Raw(fmt.Sprintf("INSERT INTO %[1]s (%[2]s) SELECT %[2]s FROM %[3]s ON CONFLICT %[4]s DO UPDATE SET %[5]s RETURNING id, created_at, cpf_normalizado, xmax = 0",
+			table, strings.Join(columns, ", "), staging, customerConflictTarget, strings.Join(updates, ", "))).Rows()
+		if err != nil {
+			return err
+		}
+		stored := make(map[string]shared.BaseEntity, len(customers))
+		for rows.Next() {
+			var base shared.BaseEntity
+			var cpf string
+			var isInsert bool
+			if err := rows.Scan(&base.ID, &base.CreatedAt, &cpf, &isInsert); err != nil {
+				rows.Close()
+				return err
+			}
+			stored[cpf] = base
+			if isInsert {
+				inserted++
+			} else {
+				updated++
+			}
+		}
+		if err := rows.Close(); err != nil {
+			return err
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		// Like upsertBulkInsert, point the customers at the rows they updated.
+		for _, customer := range customers {
+			if base, ok := stored[customer.CpfNormalizado]; ok && customer.CpfNormalizado != "" {
+				customer.BaseEntity = base
+			}
+		}
+
+		if err := resummarizeCustomers(tx, customers); err != nil {
+			return err
+		}
+		return c.auditWrites(tx, customers, before)
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return inserted, updated, nil
+}
+
+// upsertBulkInsert upserts with gorm, which also works inside a transaction.
+// Updated rows are told apart by the stored ID returned in place of the new one.
+func (c *CustomerRepositoryPostgres) upsertBulkInsert(customers []*entity.Customer) (int, int, error) {
+	ids := make([]string, len(customers))
+	for i, customer := range customers {
+		ids[i] = customer.ID
+	}
+
+	err := c.Db.Transaction(func(tx *gorm.DB) error {
+		if err := saveCustomerStores(tx, customers); err != nil {
+			return err
+		}
+		before, err := findCustomersByCpf(tx, customers)
+		if err != nil {
+			return err
+		}
+		if batchID := customers[0].ImportBatchID; batchID != "" {
+			if err := snapshotCustomers(tx, batchID, customers); err != nil {
+				return err
+			}
+		}
+
+		err = tx.Clauses(
+			clause.OnConflict{
+				Columns:     []clause.Column{{Name: "cpf_normalizado"}},
+				TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "cpf_normalizado <> ''"}}},
+				DoUpdates:   clause.AssignmentColumns(customerUpsertColumns),
+			},
+			clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "created_at"}}},
+		).CreateInBatches(customers, 1000).Error
+		if err != nil {
+			return err
+		}
+		if err := resummarizeCustomers(tx, customers); err != nil {
+			return err
+		}
+		return c.auditWrites(tx, customers, before)
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var inserted, updated int
+	for i, customer := range customers {
+		if customer.ID == ids[i] {
+			inserted++
+		} else {
+			updated++
+		}
+	}
+	return inserted, updated, nil
+}
+
+// resummarizeCustomers derives again, from their purchases, the fields of the
+// stored customers sharing a CPF with customers, so an import does not
+// overwrite what the purchases say. customers are updated to match, 1000 CPFs
+// at a time.
+func resummarizeCustomers(tx *gorm.DB, customers []*entity.Customer) error {
+	const chunkSize = 1000
+	for start := 0; start < len(customers); start += chunkSize {
+		end := min(start+chunkSize, len(customers))
+		byCpf := make(map[string]*entity.Customer, end-start)
+		cpfs := make([]string, 0, end-start)
+		for _, customer := range customers[start:end] {
+			if customer.CpfNormalizado != "" {
+				byCpf[customer.CpfNormalizado] = customer
+				cpfs = append(cpfs, customer.CpfNormalizado)
+			}
+		}
+		if len(cpfs) == 0 {
+			continue
+		}
+
+		var stored []*entity.Customer
+		err := tx.Select("id", "cpf_normalizado").
+			Where("cpf_normalizado IN ? AND EXISTS (SELECT 1 FROM purchases WHERE purchases.customer_id = customers.id)", cpfs).
+			Find(&stored).Error
+		if err != nil {
+			return err
+		}
+		if len(stored) == 0 {
+			continue
+		}
+
+		ids := make([]string, len(stored))
+		for i, customer := range stored {
+			ids[i] = customer.ID
+		}
+		summaries, err := summarizeCustomers(tx, ids)
+		if err != nil {
+			return err
+		}
+		for _, customer := range stored {
+			if summary, ok := summaries[customer.ID]; ok {
+				byCpf[customer.CpfNormalizado].ApplyPurchases(summary)
+			}
+		}
+	}
+	return nil
+}
+
+// snapshotCustomers saves the previous state of the stored customers that
+// share a CPF with customers, 1000 CPFs at a time.
+func snapshotCustomers(tx *gorm.DB, batchID string, customers []*entity.Customer) error {
+	const chunkSize = 1000
+	for start := 0; start < len(customers); start += chunkSize {
+		end := min(start+chunkSize, len(customers))
+		cpfs := make([]string, 0, end-start)
+		for _, customer := range customers[start:end] {
+			if customer.CpfNormalizado != "" {
+				cpfs = append(cpfs, customer.CpfNormalizado)
+			}
+		}
+		if len(cpfs) == 0 {
+			continue
+		}
+
+		err := tx.Exec(snapshotStatement("@cpfs"), map[string]interface{}{"batch": batchID, "cpfs": cpfs}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findCustomers reads, deleted or not, the customers whose column is one of
+// values, 1000 values at a time, keyed by key.
+func findCustomers(tx *gorm.DB, column string, values []string, key func(*entity.Customer) string) (map[string]*entity.Customer, error) {
+	const chunkSize = 1000
+	found := make(map[string]*entity.Customer, len(values))
+	for start := 0; start < len(values); start += chunkSize {
+		end := min(start+chunkSize, len(values))
+		var customers []*entity.Customer
+		if err := tx.Unscoped().Where(pgx.Identifier{column}.Sanitize()+" IN ?", values[start:end]).Find(&customers).Error; err != nil {
+			return nil, err
+		}
+		for _, customer := range customers {
+			found[key(customer)] = customer
+		}
+	}
+	return found, nil
+}
+
+// findCustomersByID reads the stored state of customers, keyed by ID.
+func findCustomersByID(tx *gorm.DB, customers []*entity.Customer) (map[string]*entity.Customer, error) {
+	ids := make([]string, len(customers))
+	for i, customer := range customers {
+		ids[i] = customer.ID
+	}
+	return findCustomers(tx, "id", ids, func(customer *entity.Customer) string { return customer.ID })
+}
+
+// findCustomersByCpf reads the stored customers sharing a CPF with customers,
+// keyed by normalized CPF.
+func findCustomersByCpf(tx *gorm.DB, customers []*entity.Customer) (map[string]*entity.Customer, error) {
+	cpfs := make([]string, 0, len(customers))
+	for _, customer := range customers {
+		if customer.CpfNormalizado != "" {
+			cpfs = append(cpfs, customer.CpfNormalizado)
+		}
+	}
+	return findCustomers(tx, "cpf_normalizado", cpfs, func(customer *entity.Customer) string { return customer.CpfNormalizado })
+}
+
+// writeOperation is the audit operation of writing customer over stored, which
+// is nil when the customer is new.
+func writeOperation(customer *entity.Customer, stored *entity.Customer) string {
+	switch {
+	case customer.ImportBatchID != "":
+		return entity.AuditOperationImport
+	case stored == nil:
+		return entity.AuditOperationCreate
+	default:
+		return entity.AuditOperationUpdate
+	}
+}
+
+// auditWrites logs the creation or update of customers, comparing the stored
+// customers they replaced, keyed by CPF, with what is stored now.
+func (c *CustomerRepositoryPostgres) auditWrites(tx *gorm.DB, customers []*entity.Customer, before map[string]*entity.Customer) error {
+	after, err := findCustomersByID(tx, customers)
+	if err != nil {
+		return err
+	}
+
+	audits := make([]*entity.CustomerAudit, len(customers))
+	for i, customer := range customers {
+		var stored *entity.Customer
+		if customer.CpfNormalizado != "" {
+			stored = before[customer.CpfNormalizado]
+		}
+		audits[i] = entity.NewCustomerAudit(c.audit, writeOperation(customer, stored), customer.ImportBatchID, stored, after[customer.ID])
+	}
+	return saveAudits(tx, audits)
+}
+
+// auditChange logs the change of a single customer from before to what is
+// stored now. A customer that is no longer stored was removed for good, which
+// is logged as a purge.
+func (c *CustomerRepositoryPostgres) auditChange(tx *gorm.DB, operation string, before *entity.Customer) error {
+	after, err := findCustomersByID(tx, []*entity.Customer{before})
+	if err != nil {
+		return err
+	}
+
+	stored := after[before.ID]
+	if stored == nil {
+		operation = entity.AuditOperationPurge
+	}
+	return saveAudits(tx, []*entity.CustomerAudit{entity.NewCustomerAudit(c.audit, operation, "", before, stored)})
+}
+
+// saveAudits appends audits to the log, skipping the nil entries of changes
+// that changed nothing.
+func saveAudits(tx *gorm.DB, audits []*entity.CustomerAudit) error {
+	entries := make([]*entity.CustomerAudit, 0, len(audits))
+	for _, audit := range audits {
+		if audit != nil {
+			entries = append(entries, audit)
+		}
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(entries, 1000).Error
+}
+
+// RollbackBatch undoes an import batch in one transaction. Customers the batch
+// updated get their snapshot back, including the provenance of the import that
+// wrote them before; the remaining customers it owns were created by it and
+// are soft-deleted, keeping the purchases recorded for them since and letting
+// Restore bring them back. Customers a later import changed again no longer
+// belong to the batch and are left alone.
+func (c *CustomerRepositoryPostgres) RollbackBatch(batchID string) (int, int, int, error) {
+	updates := make([]string, len(customerUpsertColumns))
+	for i, column := range customerUpsertColumns {
+		updates[i] = fmt.Sprintf("%s = s.%s", pgx.Identifier{column}.Sanitize(), pgx.Identifier{snapshotColumn(column)}.Sanitize())
+	}
+
+	var removed, reverted, skipped int
+	err := c.Db.Transaction(func(tx *gorm.DB) error {
+		var before []*entity.Customer
+		if err := tx.Unscoped().Where("import_batch_id = ?", batchID).Find(&before).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(fmt.Sprintf(`UPDATE customers AS c SET %s FROM customer_snapshots AS s
+			WHERE s.customer_id = c.id AND s.import_batch_id = @batch AND c.import_batch_id = @batch`, strings.Join(updates, ", ")),
+			map[string]interface{}{"batch": batchID})
+		if result.Error != nil {
+			return result.Error
+		}
+		reverted = int(result.RowsAffected)
+
+		result = tx.Where("import_batch_id = ?", batchID).Delete(&entity.Customer{})
+		if result.Error != nil {
+			return result.Error
+		}
+		removed = int(result.RowsAffected)
+
+		result = tx.Where("import_batch_id = ?", batchID).Delete(&entity.CustomerSnapshot{})
+		if result.Error != nil {
+			return result.Error
+		}
+		skipped = int(result.RowsAffected) - reverted
+
+		after, err := findCustomersByID(tx, before)
+		if err != nil {
+			return err
+		}
+		audits := make([]*entity.CustomerAudit, len(before))
+		for i, customer := range before {
+			audits[i] = entity.NewCustomerAudit(c.audit, entity.AuditOperationRollback, batchID, customer, after[customer.ID])
+		}
+		return saveAudits(tx, audits)
+	})
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	if removed+reverted+skipped == 0 {
+		return 0, 0, 0, gorm.ErrRecordNotFound
+	}
+	return removed, reverted, skipped, nil
+}
+
+// dedupeCustomersByCpf keeps the last customer of each CPF in the batch, since
+// a single INSERT ... ON CONFLICT cannot touch the same row twice. It returns
+// how many customers were dropped.
+func dedupeCustomersByCpf(customers []*entity.Customer) ([]*entity.Customer, int) {
+	last := make(map[string]int, len(customers))
+	for i, customer := range customers {
+		if customer.CpfNormalizado != "" {
+			last[customer.CpfNormalizado] = i
+		}
+	}
+
+	deduped := make([]*entity.Customer, 0, len(customers))
+	for i, customer := range customers {
+		if customer.CpfNormalizado == "" || last[customer.CpfNormalizado] == i {
+			deduped = append(deduped, customer)
+		}
+	}
+	return deduped, len(customers) - len(deduped)
+}
+
+// Stream reads the customers through a server-side cursor, 1000 rows per
+// FETCH, inside a read-only transaction that keeps the export consistent.
+func (c *CustomerRepositoryPostgres) Stream(filter repository.CustomerFilter, yield func(*entity.Customer) error) error {
+	const fetchSize = 1000
+
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	if filter.ImportBatchID != "" {
+		conditions = append(conditions, "import_batch_id = ?")
+		args = append(args, filter.ImportBatchID)
+	}
+	if filter.CpfValido != nil {
+		conditions = append(conditions, "cpf_valido = ?")
+		args = append(args, *filter.CpfValido)
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.CreatedTo)
+	}
+
+	return c.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION READ ONLY").Error; err != nil {
+			return err
+		}
+
+		declare := fmt.Sprintf("DECLARE customer_export NO SCROLL CURSOR FOR SELECT * FROM customers WHERE %s ORDER BY created_at, id",
+			strings.Join(conditions, " AND "))
+		if err := tx.Exec(declare, args...).Error; err != nil {
+			return err
+		}
+
+		for {
+			var customers []*entity.Customer
+			if err := tx.Raw(fmt.Sprintf("FETCH %d FROM customer_export", fetchSize)).Scan(&customers).Error; err != nil {
+				return err
+			}
+
+			for _, customer := range customers {
+				if err := yield(customer); err != nil {
+					return err
+				}
+			}
+
+			if len(customers) < fetchSize {
+				return tx.Exec("CLOSE customer_export").Error
+			}
+		}
+	})
 }
 
 func (c *CustomerRepositoryPostgres) Get(page int) ([]*entity.Customer, error) {
@@ -40,13 +661,163 @@ func (c *CustomerRepositoryPostgres) GetById(id string) (*entity.Customer, error
 	return &customer, tx.Error
 }
 
+func (c *CustomerRepositoryPostgres) GetHistory(customerID string, page int) ([]*entity.CustomerAudit, error) {
+	const pageSize = 100
+	offset := (page - 1) * pageSize
+
+	var audits []*entity.CustomerAudit
+	tx := c.Db.Where("customer_id = ?", customerID).Order("created_at, id").Limit(pageSize).Offset(offset).Find(&audits)
+	return audits, tx.Error
+}
+
 func (c *CustomerRepositoryPostgres) GetByCpf(cpf string) (*entity.Customer, error) {
 	var customer entity.Customer
-	tx := c.Db.First(&customer, "cpf = ?", cpf)
+	normalized := entity.NewCpf(cpf).Digits()
+	if normalized == "" {
+		return &customer, gorm.ErrRecordNotFound
+	}
+	tx := c.Db.First(&customer, "cpf_normalizado = ?", normalized)
 	return &customer, tx.Error
 }
 
+func (c *CustomerRepositoryPostgres) GetByStore(cnpj string, relation string, page int) ([]*entity.Customer, error) {
+	const pageSize = 100
+	offset := (page - 1) * pageSize
+
+	var customers []*entity.Customer
+	cnpj = storeEntity.NormalizeCnpj(cnpj)
+	if cnpj == "" {
+		return customers, nil
+	}
+
+	query := c.Db.Order("created_at, id").Limit(pageSize).Offset(offset)
+	switch relation {
+	case repository.StoreRelationMostFrequent:
+		query = query.Where("loja_mais_frequente_cnpj = ?", cnpj)
+	case repository.StoreRelationLastPurchase:
+		query = query.Where("loja_ultima_compra_cnpj = ?", cnpj)
+	default:
+		query = query.Where("loja_mais_frequente_cnpj = ? OR loja_ultima_compra_cnpj = ?", cnpj, cnpj)
+	}
+
+	tx := query.Find(&customers)
+	return customers, tx.Error
+}
+
+func (c *CustomerRepositoryPostgres) Update(customer *entity.Customer) error {
+	return c.Db.Transaction(func(tx *gorm.DB) error {
+		if err := saveCustomerStores(tx, []*entity.Customer{customer}); err != nil {
+			return err
+		}
+		before, err := findCustomersByID(tx, []*entity.Customer{customer})
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&entity.Customer{}).Where("id = ?", customer.ID).Select(customerUpdateColumns).Updates(customer)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		summaries, err := summarizeCustomers(tx, []string{customer.ID})
+		if err != nil {
+			return err
+		}
+		if summary, ok := summaries[customer.ID]; ok {
+			customer.ApplyPurchases(summary)
+		}
+		return c.auditChange(tx, entity.AuditOperationUpdate, before[customer.ID])
+	})
+}
+
+func (c *CustomerRepositoryPostgres) WithDeleted() repository.CustomerRepository {
+	return &CustomerRepositoryPostgres{Db: c.Db.Unscoped(), audit: c.audit}
+}
+
+func (c *CustomerRepositoryPostgres) WithAudit(info entity.AuditInfo) repository.CustomerRepository {
+	return &CustomerRepositoryPostgres{Db: c.Db, audit: info}
+}
+
 func (c *CustomerRepositoryPostgres) Delete(customer *entity.Customer) error {
-	tx := c.Db.Delete(customer)
-	return tx.Error
+	return c.Db.Transaction(func(tx *gorm.DB) error {
+		before, err := findCustomersByID(tx, []*entity.Customer{customer})
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(customer).Error; err != nil {
+			return err
+		}
+		if before[customer.ID] == nil {
+			return nil
+		}
+		return c.auditChange(tx, entity.AuditOperationDelete, before[customer.ID])
+	})
+}
+
+func (c *CustomerRepositoryPostgres) Restore(customer *entity.Customer) error {
+	return c.Db.Transaction(func(tx *gorm.DB) error {
+		before, err := findCustomersByID(tx, []*entity.Customer{customer})
+		if err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Model(customer).Where("deleted_at IS NOT NULL").Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return c.auditChange(tx, entity.AuditOperationRestore, before[customer.ID])
+	})
+}
+
+// Purge removes for good the customers deleted before deletedBefore, 1000 at
+// a time, each batch in its own transaction so a large backlog never loads at
+// once or holds its locks for long. It returns how many were purged, including
+// the batches committed before an error.
+func (c *CustomerRepositoryPostgres) Purge(deletedBefore time.Time) (int, error) {
+	const batchSize = 1000
+
+	var purged int
+	for {
+		var ids []string
+		var deleted []*entity.Customer
+		err := c.Db.Transaction(func(tx *gorm.DB) error {
+			err := tx.Unscoped().Model(&entity.Customer{}).
+				Where("deleted_at < ?", deletedBefore).
+				Order("id").Limit(batchSize).
+				Pluck("id", &ids).Error
+			if err != nil || len(ids) == 0 {
+				return err
+			}
+
+			if err := tx.Where("customer_id IN ?", ids).Delete(&entity.CustomerSnapshot{}).Error; err != nil {
+				return err
+			}
+
+			result := tx.Unscoped().Clauses(clause.Returning{}).
+				Where("id IN ? AND deleted_at < ?", ids, deletedBefore).
+				Delete(&deleted)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			audits := make([]*entity.CustomerAudit, len(deleted))
+			for i, customer := range deleted {
+				audits[i] = entity.NewCustomerAudit(c.audit, entity.AuditOperationPurge, "", customer, nil)
+			}
+			return saveAudits(tx, audits)
+		})
+		if err != nil {
+			return purged, err
+		}
+		purged += len(deleted)
+		if len(ids) < batchSize {
+			return purged, nil
+		}
+	}
 }
@@ -1,8 +1,11 @@
 package databaseRepository_test
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,8 +13,17 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	"neoway_test/internal/domain/customer/dto"
 	"neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/repository"
+	"neoway_test/internal/domain/customer/service"
+	importJobEntity "neoway_test/internal/domain/importjob/entity"
+	purchaseEntity "neoway_test/internal/domain/purchase/entity"
 	shared "neoway_test/internal/domain/shared/entity"
+	storeEntity "neoway_test/internal/domain/store/entity"
+	uploadSessionEntity "neoway_test/internal/domain/uploadsession/entity"
+	watchedFileEntity "neoway_test/internal/domain/watchedfile/entity"
+	databaseConfig "neoway_test/internal/infrastructure/database/config"
 	databaseRepository "neoway_test/internal/infrastructure/database/repository"
 )
 
@@ -25,15 +37,15 @@ func TestMain(m *testing.M) {
 		log.Fatalf("Error connecting to database: %v", err)
 	}
 
-	db.AutoMigrate(&entity.Customer{})
+	db.AutoMigrate(&storeEntity.Store{}, &entity.Customer{}, &entity.CustomerSnapshot{}, &entity.CustomerAudit{}, &purchaseEntity.Purchase{}, &importJobEntity.ImportJob{}, &uploadSessionEntity.UploadSession{}, &watchedFileEntity.WatchedFile{})
 
 	code := m.Run()
 	os.Exit(code)
 }
 
 func setupTestDB() {
-	db.Exec("DROP TABLE IF EXISTS customers")
-	db.AutoMigrate(&entity.Customer{})
+	db.Exec("DROP TABLE IF EXISTS purchases, customers, customer_snapshots, customer_audits, stores, schema_migrations")
+	db.AutoMigrate(&storeEntity.Store{}, &entity.Customer{}, &entity.CustomerSnapshot{}, &entity.CustomerAudit{}, &purchaseEntity.Purchase{})
 }
 
 func TestPostgresCustomerRepository(t *testing.T) {
@@ -47,8 +59,8 @@ func TestPostgresCustomerRepository(t *testing.T) {
 			BaseEntity:                  shared.NewBaseEntity(),
 			Cpf:                         "922.488.109-20",
 			CpfValido:                   true,
-			Private:                     "1",
-			Incompleto:                  "0",
+			Private:                     true,
+			Incompleto:                  false,
 			DataUltimaCompra:            &dataUltimaCompra,
 			TicketMedio:                 130.54,
 			TicketUltimaCompra:          130.54,
@@ -86,8 +98,8 @@ func TestPostgresCustomerRepository(t *testing.T) {
 				BaseEntity:                  shared.NewBaseEntity(),
 				Cpf:                         "891.098.302-78",
 				CpfValido:                   true,
-				Private:                     "1",
-				Incompleto:                  "0",
+				Private:                     true,
+				Incompleto:                  false,
 				DataUltimaCompra:            &dataUltimaCompra,
 				TicketMedio:                 130.54,
 				TicketUltimaCompra:          130.54,
@@ -100,8 +112,8 @@ func TestPostgresCustomerRepository(t *testing.T) {
 				BaseEntity:                  shared.NewBaseEntity(),
 				Cpf:                         "046.857.249-09",
 				CpfValido:                   true,
-				Private:                     "0",
-				Incompleto:                  "1",
+				Private:                     false,
+				Incompleto:                  true,
 				DataUltimaCompra:            &dataUltimaCompra,
 				TicketMedio:                 130.54,
 				TicketUltimaCompra:          130.54,
@@ -157,10 +169,11 @@ func TestPostgresCustomerRepository(t *testing.T) {
 
 		customer := &entity.Customer{
 			BaseEntity:                  shared.NewBaseEntity(),
-			Cpf:                         "922.488.109-20",
+			Cpf:                         "92248810920",
+			CpfNormalizado:              "92248810920",
 			CpfValido:                   true,
-			Private:                     "1",
-			Incompleto:                  "0",
+			Private:                     true,
+			Incompleto:                  false,
 			DataUltimaCompra:            &dataUltimaCompra,
 			TicketMedio:                 130.54,
 			TicketUltimaCompra:          130.54,
@@ -171,9 +184,11 @@ func TestPostgresCustomerRepository(t *testing.T) {
 		}
 		repo.Create(customer)
 
-		storedCustomer, err := repo.GetByCpf(customer.Cpf)
-		assert.Nil(t, err)
-		assert.Equal(t, customer.ID, storedCustomer.ID)
+		for _, cpf := range []string{"92248810920", "922.488.109-20"} {
+			storedCustomer, err := repo.GetByCpf(cpf)
+			assert.Nil(t, err)
+			assert.Equal(t, customer.ID, storedCustomer.ID)
+		}
 	})
 
 	t.Run("GetById", func(t *testing.T) {
@@ -185,8 +200,8 @@ func TestPostgresCustomerRepository(t *testing.T) {
 			BaseEntity:                  shared.NewBaseEntity(),
 			Cpf:                         "922.488.109-20",
 			CpfValido:                   true,
-			Private:                     "1",
-			Incompleto:                  "0",
+			Private:                     true,
+			Incompleto:                  false,
 			DataUltimaCompra:            &dataUltimaCompra,
 			TicketMedio:                 130.54,
 			TicketUltimaCompra:          130.54,
@@ -211,8 +226,8 @@ func TestPostgresCustomerRepository(t *testing.T) {
 			BaseEntity:                  shared.NewBaseEntity(),
 			Cpf:                         "922.488.109-20",
 			CpfValido:                   true,
-			Private:                     "1",
-			Incompleto:                  "0",
+			Private:                     true,
+			Incompleto:                  false,
 			DataUltimaCompra:            &dataUltimaCompra,
 			TicketMedio:                 130.54,
 			TicketUltimaCompra:          130.54,
@@ -232,4 +247,739 @@ func TestPostgresCustomerRepository(t *testing.T) {
 		assert.NotNil(t, storedCustomer)
 		assert.Equal(t, gorm.ErrRecordNotFound, err)
 	})
+
+	t.Run("Update", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "79.379.491/0001-83", "NULL")
+		customer.TraceTo("batch", "base.txt", "hash", 3)
+		_, _, _, err := repo.UpsertBulk([]*entity.Customer{customer})
+		assert.Nil(t, err)
+
+		updated, _ := entity.NewCustomer("041.091.641-25", true, false, nil, 20, 5, "NULL", "79.379.491/0008-50")
+		updated.BaseEntity = customer.BaseEntity
+		err = repo.Update(updated)
+		assert.Nil(t, err)
+
+		storedCustomer, err := repo.GetById(customer.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "04109164125", storedCustomer.CpfNormalizado)
+		assert.True(t, storedCustomer.Private)
+		assert.Equal(t, 20.0, storedCustomer.TicketMedio)
+		assert.Nil(t, storedCustomer.LojaMaisFrequenteCnpj)
+		assert.Equal(t, "79379491000850", *storedCustomer.LojaUltimaCompraCnpj)
+		assert.Empty(t, storedCustomer.ImportBatchID)
+		assert.Equal(t, 0, storedCustomer.SourceLineNumber)
+
+		_, err = repo.GetByCpf("92248810920")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+		missing, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 0, 0, "NULL", "NULL")
+		err = repo.Update(missing)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+
+	t.Run("CreateBulkCopy", func(t *testing.T) {
+		setupTestDB()
+		dataUltimaCompra := time.Date(2011, 10, 5, 0, 0, 0, 0, time.UTC)
+
+		customers := []*entity.Customer{
+			{
+				BaseEntity:         shared.NewBaseEntity(),
+				Cpf:                "891.098.302-78",
+				CpfValido:          true,
+				Private:            true,
+				Incompleto:         false,
+				DataUltimaCompra:   &dataUltimaCompra,
+				TicketMedio:        130.54,
+				TicketUltimaCompra: 130.54,
+				LojaMaisFrequente:  "79.379.491/0001-83",
+				LojaUltimaCompra:   "79.379.491/0001-83",
+			},
+			{
+				BaseEntity: shared.NewBaseEntity(),
+				Cpf:        "046.857.249-09",
+				Private:    false,
+				Incompleto: true,
+			},
+		}
+
+		postgresRepo := repo.(*databaseRepository.CustomerRepositoryPostgres)
+		err := postgresRepo.CreateBulkCopy(customers)
+		assert.Nil(t, err)
+
+		storedCustomer, err := repo.GetById(customers[0].ID)
+		assert.Nil(t, err)
+		assert.Equal(t, customers[0].Cpf, storedCustomer.Cpf)
+		assert.Equal(t, 130.54, storedCustomer.TicketMedio)
+		assert.True(t, dataUltimaCompra.Equal(*storedCustomer.DataUltimaCompra))
+
+		storedCustomer, err = repo.GetById(customers[1].ID)
+		assert.Nil(t, err)
+		assert.Nil(t, storedCustomer.DataUltimaCompra)
+	})
+
+	t.Run("UpsertBulk", func(t *testing.T) {
+		setupTestDB()
+
+		first, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "79.379.491/0001-83", "79.379.491/0001-83")
+		inserted, updated, _, err := repo.UpsertBulk([]*entity.Customer{first})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, inserted)
+		assert.Equal(t, 0, updated)
+
+		again, _ := entity.NewCustomer("92248810920", false, false, nil, 20, 20, "NULL", "NULL")
+		again.TraceTo("batch-2", "base_2.txt", "abc123", 7)
+		other, _ := entity.NewCustomer("046.857.249-09", false, false, nil, 30, 30, "NULL", "NULL")
+		inserted, updated, _, err = repo.UpsertBulk([]*entity.Customer{again, other})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, inserted)
+		assert.Equal(t, 1, updated)
+
+		storedCustomers, err := repo.Get(1)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(storedCustomers))
+
+		storedCustomer, err := repo.GetByCpf("922.488.109-20")
+		assert.Nil(t, err)
+		assert.Equal(t, first.ID, storedCustomer.ID)
+		assert.Equal(t, 20.0, storedCustomer.TicketMedio)
+		assert.Equal(t, "NULL", storedCustomer.LojaUltimaCompra)
+		assert.Equal(t, "batch-2", storedCustomer.ImportBatchID)
+		assert.Equal(t, "base_2.txt", storedCustomer.SourceFileName)
+		assert.Equal(t, "abc123", storedCustomer.SourceFileHash)
+		assert.Equal(t, 7, storedCustomer.SourceLineNumber)
+	})
+
+	t.Run("UpsertBulkReportsSuperseded", func(t *testing.T) {
+		setupTestDB()
+
+		first, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+		last, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 20, 20, "NULL", "NULL")
+		inserted, updated, superseded, err := repo.UpsertBulk([]*entity.Customer{first, last})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, inserted)
+		assert.Equal(t, 0, updated)
+		assert.Equal(t, 1, superseded)
+
+		storedCustomer, err := repo.GetByCpf("922.488.109-20")
+		assert.Nil(t, err)
+		assert.Equal(t, 20.0, storedCustomer.TicketMedio)
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		setupTestDB()
+
+		first, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+		inserted, err := repo.Upsert(first)
+		assert.Nil(t, err)
+		assert.True(t, inserted)
+
+		again, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 20, 20, "NULL", "NULL")
+		inserted, err = repo.Upsert(again)
+		assert.Nil(t, err)
+		assert.False(t, inserted)
+		assert.Equal(t, first.ID, again.ID)
+	})
+
+	t.Run("MigrateConvertsTextFlags", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+		other, _ := entity.NewCustomer("046.857.249-09", false, false, nil, 10, 10, "NULL", "NULL")
+		_, _, _, err := repo.UpsertBulk([]*entity.Customer{customer, other})
+		assert.Nil(t, err)
+
+		// Bring back the text columns the flags used to be stored in.
+		for _, column := range []string{"private", "incompleto"} {
+			db.Exec(fmt.Sprintf("ALTER TABLE customers ALTER COLUMN %[1]s DROP DEFAULT, ALTER COLUMN %[1]s TYPE text USING '0'", column))
+		}
+		db.Exec("UPDATE customers SET private = 'S', incompleto = ' 1 ' WHERE id = ?", customer.ID)
+		db.Exec("UPDATE customers SET private = NULL, incompleto = 'N' WHERE id = ?", other.ID)
+
+		err = databaseConfig.Migrate(db)
+		assert.Nil(t, err)
+
+		stored, err := repo.GetById(customer.ID)
+		assert.Nil(t, err)
+		assert.True(t, stored.Private)
+		assert.True(t, stored.Incompleto)
+
+		stored, err = repo.GetById(other.ID)
+		assert.Nil(t, err)
+		assert.False(t, stored.Private)
+		assert.False(t, stored.Incompleto)
+	})
+
+	t.Run("MigrateRejectsUnknownTextFlags", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+		other, _ := entity.NewCustomer("046.857.249-09", false, false, nil, 10, 10, "NULL", "NULL")
+		_, _, _, err := repo.UpsertBulk([]*entity.Customer{customer, other})
+		assert.Nil(t, err)
+
+		db.Exec("ALTER TABLE customers ALTER COLUMN private DROP DEFAULT, ALTER COLUMN private TYPE text USING '0'")
+		db.Exec("UPDATE customers SET private = 'x' WHERE id = ?", customer.ID)
+		db.Exec("UPDATE customers SET private = 'yes' WHERE id = ?", other.ID)
+
+		err = databaseConfig.Migrate(db)
+		assert.EqualError(t, err, `customers.private holds values that are not a yes/no flag, fix them before migrating: "x", "yes"`)
+
+		var columnType string
+		db.Raw("SELECT data_type FROM information_schema.columns WHERE table_name = 'customers' AND column_name = 'private'").Scan(&columnType)
+		assert.Equal(t, "text", columnType)
+	})
+
+	t.Run("MigrateNormalizesCpf", func(t *testing.T) {
+		setupTestDB()
+
+		formatted, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+		short, _ := entity.NewCustomer("026.987.379-13", false, false, nil, 10, 10, "NULL", "NULL")
+		padded, _ := entity.NewCustomer("041.091.641-25", false, false, nil, 10, 10, "NULL", "NULL")
+		stale, _ := entity.NewCustomer("041.091.641-25", false, false, nil, 20, 20, "NULL", "NULL")
+		stale.CreatedAt = padded.CreatedAt.Add(-time.Hour)
+		garbage, _ := entity.NewCustomer("123", false, false, nil, 10, 10, "NULL", "NULL")
+		_, _, _, err := repo.UpsertBulk([]*entity.Customer{formatted, short, padded, garbage})
+		assert.Nil(t, err)
+
+		// Rows written before the CPF was normalized: punctuated, and missing
+		// the leading zero next to a newer row that already has it.
+		db.Exec("UPDATE customers SET cpf = '922.488.109-20' WHERE id = ?", formatted.ID)
+		db.Exec("UPDATE customers SET cpf = '26987379-13', cpf_normalizado = '2698737913', cpf_valido = false WHERE id = ?", short.ID)
+		db.Exec(`INSERT INTO customers (id, cpf, cpf_normalizado, cpf_valido, cnpj_loja_mais_frequente_valido, cnpj_loja_ultima_compra_valido, created_at)
+			VALUES (?, '41091641-25', '4109164125', false, false, false, ?)`, stale.ID, stale.CreatedAt)
+
+		// The stale row would take the CPF of a newer one, so nothing changes
+		// until the collision is resolved.
+		err = databaseConfig.Migrate(db)
+		var collisionErr *databaseConfig.CpfCollisionError
+		assert.ErrorAs(t, err, &collisionErr)
+		assert.Equal(t, []databaseConfig.CpfCollision{{Cpf: "04109164125", IDs: []string{padded.ID, stale.ID}}}, collisionErr.Collisions)
+
+		stored, err := repo.GetById(stale.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "41091641-25", stored.Cpf)
+
+		_, err = databaseConfig.DedupeCpfCollisions(db, "dedupe:test")
+		assert.Nil(t, err)
+
+		err = databaseConfig.Migrate(db)
+		assert.Nil(t, err)
+
+		stored, err = repo.GetById(formatted.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "92248810920", stored.Cpf)
+
+		stored, err = repo.GetByCpf("026.987.379-13")
+		assert.Nil(t, err)
+		assert.Equal(t, short.ID, stored.ID)
+		assert.Equal(t, "02698737913", stored.Cpf)
+		assert.True(t, stored.CpfValido)
+
+		stored, err = repo.GetByCpf("04109164125")
+		assert.Nil(t, err)
+		assert.Equal(t, padded.ID, stored.ID)
+
+		_, err = repo.GetById(stale.ID)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+		stored, err = repo.GetById(garbage.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "123", stored.Cpf)
+		assert.False(t, stored.CpfValido)
+
+		// The rewrite runs once: a row written the old way afterwards is kept.
+		db.Exec("UPDATE customers SET cpf = '922.488.109-20' WHERE id = ?", formatted.ID)
+		err = databaseConfig.Migrate(db)
+		assert.Nil(t, err)
+		stored, err = repo.GetById(formatted.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "922.488.109-20", stored.Cpf)
+	})
+
+	t.Run("MigrateReportsCpfCollisions", func(t *testing.T) {
+		setupTestDB()
+		purchaseRepo, _ := databaseRepository.NewPostgresPurchaseRepository(db)
+
+		older, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+		newer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 20, 20, "NULL", "NULL")
+		older.CreatedAt = newer.CreatedAt.Add(-time.Hour)
+
+		// A database from before the unique index, holding the same CPF twice.
+		db.Exec("DROP INDEX idx_customers_cpf_normalizado")
+		assert.Nil(t, repo.(*databaseRepository.CustomerRepositoryPostgres).CreateBulkInsert([]*entity.Customer{older, newer}))
+		purchase, _ := purchaseEntity.NewPurchase(older.ID, "79.379.491/0001-83", time.Date(2011, 10, 5, 0, 0, 0, 0, time.UTC), 50)
+		assert.Nil(t, purchaseRepo.Create(purchase))
+
+		err := databaseConfig.Migrate(db)
+		var collisionErr *databaseConfig.CpfCollisionError
+		assert.ErrorAs(t, err, &collisionErr)
+		assert.Equal(t, []databaseConfig.CpfCollision{{Cpf: "92248810920", IDs: []string{newer.ID, older.ID}}}, collisionErr.Collisions)
+		assert.Contains(t, err.Error(), older.ID)
+
+		// Nothing was removed by the migration.
+		_, err = repo.GetById(older.ID)
+		assert.Nil(t, err)
+
+		resolved, err := databaseConfig.DedupeCpfCollisions(db, "dedupe:test")
+		assert.Nil(t, err)
+		assert.Len(t, resolved, 1)
+
+		_, err = repo.GetById(older.ID)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		stored, err := repo.GetById(newer.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 50.0, stored.TicketMedio)
+		moved, err := purchaseRepo.GetById(purchase.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, newer.ID, moved.CustomerID)
+
+		history, err := repo.GetHistory(older.ID, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.AuditOperationPurge, history[len(history)-1].Operation)
+		assert.Equal(t, "dedupe:test", history[len(history)-1].Actor)
+
+		err = databaseConfig.Migrate(db)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		setupTestDB()
+
+		var customers []*entity.Customer
+		for i, cpf := range []string{"922.488.109-20", "046.857.249-09", "000.000.000-00"} {
+			customer, _ := entity.NewCustomer(cpf, false, false, nil, 10, 10, "NULL", "NULL")
+			customer.CreatedAt = time.Date(2024, 1, i+1, 0, 0, 0, 0, time.UTC)
+			customer.TraceTo(fmt.Sprintf("batch-%d", i%2), "base.txt", "hash", i+2)
+			customers = append(customers, customer)
+		}
+		_, _, _, err := repo.UpsertBulk(customers)
+		assert.Nil(t, err)
+
+		var cpfs []string
+		collect := func(customer *entity.Customer) error {
+			cpfs = append(cpfs, customer.Cpf)
+			return nil
+		}
+
+		err = repo.Stream(repository.CustomerFilter{}, collect)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"92248810920", "04685724909", "00000000000"}, cpfs)
+
+		cpfs = nil
+		valid := true
+		err = repo.Stream(repository.CustomerFilter{ImportBatchID: "batch-0", CpfValido: &valid}, collect)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"92248810920"}, cpfs)
+
+		cpfs = nil
+		from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		err = repo.Stream(repository.CustomerFilter{CreatedFrom: &from}, collect)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"04685724909", "00000000000"}, cpfs)
+
+		stop := errors.New("client went away")
+		err = repo.Stream(repository.CustomerFilter{}, func(*entity.Customer) error { return stop })
+		assert.Equal(t, stop, err)
+	})
+
+	t.Run("RollbackBatch", func(t *testing.T) {
+		setupTestDB()
+
+		existing, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+		existing.TraceTo("batch-0", "base_0.txt", "hash-0", 2)
+		_, _, _, err := repo.UpsertBulk([]*entity.Customer{existing})
+		assert.Nil(t, err)
+
+		updated, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 20, 20, "NULL", "NULL")
+		updated.TraceTo("batch-1", "base_1.txt", "hash-1", 2)
+		created, _ := entity.NewCustomer("046.857.249-09", false, false, nil, 30, 30, "NULL", "NULL")
+		created.TraceTo("batch-1", "base_1.txt", "hash-1", 3)
+		_, _, _, err = repo.UpsertBulk([]*entity.Customer{updated, created})
+		assert.Nil(t, err)
+
+		// A second write of the same customer by the batch keeps the first snapshot.
+		again, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 25, 25, "NULL", "NULL")
+		again.TraceTo("batch-1", "base_1.txt", "hash-1", 4)
+		_, err = repo.Upsert(again)
+		assert.Nil(t, err)
+
+		removed, reverted, skipped, err := repo.RollbackBatch("batch-1")
+		assert.Nil(t, err)
+		assert.Equal(t, 1, removed)
+		assert.Equal(t, 1, reverted)
+		assert.Equal(t, 0, skipped)
+
+		restored, err := repo.GetByCpf("922.488.109-20")
+		assert.Nil(t, err)
+		assert.Equal(t, 10.0, restored.TicketMedio)
+		assert.Equal(t, "batch-0", restored.ImportBatchID)
+		assert.Equal(t, "base_0.txt", restored.SourceFileName)
+
+		_, err = repo.GetByCpf("046.857.249-09")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+		_, _, _, err = repo.RollbackBatch("batch-1")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+
+	t.Run("RollbackBatchKeepsPurchases", func(t *testing.T) {
+		setupTestDB()
+		purchaseRepo, _ := databaseRepository.NewPostgresPurchaseRepository(db)
+
+		created, _ := entity.NewCustomer("046.857.249-09", false, false, nil, 30, 30, "NULL", "NULL")
+		created.TraceTo("batch-1", "base_1.txt", "hash-1", 2)
+		_, _, _, err := repo.UpsertBulk([]*entity.Customer{created})
+		assert.Nil(t, err)
+
+		purchase, _ := purchaseEntity.NewPurchase(created.ID, "79.379.491/0001-83", time.Date(2011, 10, 5, 0, 0, 0, 0, time.UTC), 10)
+		assert.Nil(t, purchaseRepo.CreateBulk([]*purchaseEntity.Purchase{purchase}))
+
+		removed, _, _, err := repo.RollbackBatch("batch-1")
+		assert.Nil(t, err)
+		assert.Equal(t, 1, removed)
+
+		_, err = repo.GetById(created.ID)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		_, err = purchaseRepo.GetById(purchase.ID)
+		assert.Nil(t, err)
+
+		deleted, err := repo.WithDeleted().GetById(created.ID)
+		assert.Nil(t, err)
+		assert.Nil(t, repo.Restore(deleted))
+		_, err = repo.GetById(created.ID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("GetByStore", func(t *testing.T) {
+		setupTestDB()
+
+		both, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "79.379.491/0001-83", "79.379.491/0001-83")
+		frequent, _ := entity.NewCustomer("046.857.249-09", false, false, nil, 10, 10, "79.379.491/0001-83", "79.379.491/0008-50")
+		other, _ := entity.NewCustomer("891.098.302-78", false, false, nil, 10, 10, "79.379.491/0008-50", "NULL")
+		frequent.CreatedAt = both.CreatedAt.Add(time.Second)
+		_, _, _, err := repo.UpsertBulk([]*entity.Customer{both, frequent, other})
+		assert.Nil(t, err)
+
+		var stores []*storeEntity.Store
+		db.Order("cnpj").Find(&stores)
+		assert.Len(t, stores, 2)
+		assert.Equal(t, "79379491000183", stores[0].Cnpj)
+
+		customers, err := repo.GetByStore("79.379.491/0001-83", "", 1)
+		assert.Nil(t, err)
+		assert.Len(t, customers, 2)
+		assert.Equal(t, both.ID, customers[0].ID)
+
+		customers, err = repo.GetByStore("79379491000850", repository.StoreRelationLastPurchase, 1)
+		assert.Nil(t, err)
+		assert.Len(t, customers, 1)
+		assert.Equal(t, frequent.ID, customers[0].ID)
+
+		customers, err = repo.GetByStore("79379491000850", repository.StoreRelationMostFrequent, 1)
+		assert.Nil(t, err)
+		assert.Len(t, customers, 1)
+		assert.Equal(t, other.ID, customers[0].ID)
+	})
+
+	t.Run("MigrateBackfillsStores", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "79.379.491/0001-83", "79.379.491/0008-50")
+		unformatted, _ := entity.NewCustomer("046.857.249-09", false, false, nil, 10, 10, "79379491000183", "NULL")
+		_, _, _, err := repo.UpsertBulk([]*entity.Customer{customer, unformatted})
+		assert.Nil(t, err)
+
+		// Go back to the schema from before stores existed.
+		db.Exec("ALTER TABLE customers DROP COLUMN loja_mais_frequente_cnpj, DROP COLUMN loja_ultima_compra_cnpj")
+		db.Exec("ALTER TABLE customer_snapshots DROP COLUMN loja_mais_frequente_cnpj, DROP COLUMN loja_ultima_compra_cnpj")
+		db.Exec("DROP TABLE purchases, stores")
+
+		err = databaseConfig.Migrate(db)
+		assert.Nil(t, err)
+
+		stored, err := repo.GetById(customer.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "79379491000183", *stored.LojaMaisFrequenteCnpj)
+		assert.Equal(t, "79379491000850", *stored.LojaUltimaCompraCnpj)
+
+		customers, err := repo.GetByStore("79379491000850", repository.StoreRelationLastPurchase, 1)
+		assert.Nil(t, err)
+		assert.Len(t, customers, 1)
+
+		// Both spellings of the CNPJ point at the same store.
+		customers, err = repo.GetByStore("79379491000183", repository.StoreRelationMostFrequent, 1)
+		assert.Nil(t, err)
+		assert.Len(t, customers, 2)
+		var stores int64
+		db.Model(&storeEntity.Store{}).Count(&stores)
+		assert.Equal(t, int64(2), stores)
+	})
+
+	t.Run("CreateBulkCopyIsAtomic", func(t *testing.T) {
+		setupTestDB()
+
+		existing, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+		assert.Nil(t, repo.Create(existing))
+
+		// The copy fails on the repeated ID after the store was saved.
+		fresh, _ := entity.NewCustomer("046.857.249-09", false, false, nil, 10, 10, "79.379.491/0001-83", "NULL")
+		err := repo.CreateBulk([]*entity.Customer{fresh, existing})
+		assert.Error(t, err)
+
+		_, err = repo.GetById(fresh.ID)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		var stores int64
+		db.Model(&storeEntity.Store{}).Count(&stores)
+		assert.Equal(t, int64(0), stores)
+		history, err := repo.GetHistory(fresh.ID, 1)
+		assert.Nil(t, err)
+		assert.Empty(t, history)
+	})
+
+	t.Run("CreateBulkFallsBackInsideTransaction", func(t *testing.T) {
+		setupTestDB()
+
+		customers := []*entity.Customer{{BaseEntity: shared.NewBaseEntity(), Cpf: "891.098.302-78"}}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			txRepo, _ := databaseRepository.NewPostgresCustomerRepository(tx)
+			return txRepo.CreateBulk(customers)
+		})
+		assert.Nil(t, err)
+
+		_, err = repo.GetById(customers[0].ID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("SoftDeleteAndRestore", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+		err := repo.Create(customer)
+		assert.Nil(t, err)
+
+		err = repo.Restore(customer)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+		err = repo.Delete(customer)
+		assert.Nil(t, err)
+
+		_, err = repo.GetById(customer.ID)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		customers, err := repo.Get(1)
+		assert.Nil(t, err)
+		assert.Len(t, customers, 0)
+
+		deleted, err := repo.WithDeleted().GetByCpf("922.488.109-20")
+		assert.Nil(t, err)
+		assert.NotNil(t, deleted.DeletionTime())
+
+		err = repo.Restore(customer)
+		assert.Nil(t, err)
+
+		restored, err := repo.GetById(customer.ID)
+		assert.Nil(t, err)
+		assert.Nil(t, restored.DeletionTime())
+	})
+
+	t.Run("UpsertRevivesDeleted", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+		_, _, _, err := repo.UpsertBulk([]*entity.Customer{customer})
+		assert.Nil(t, err)
+		assert.Nil(t, repo.Delete(customer))
+
+		again, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 20, 20, "NULL", "NULL")
+		again.TraceTo("batch-1", "base_1.txt", "hash-1", 2)
+		inserted, updated, _, err := repo.UpsertBulk([]*entity.Customer{again})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, inserted)
+		assert.Equal(t, 1, updated)
+
+		revived, err := repo.GetById(customer.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 20.0, revived.TicketMedio)
+
+		// Rolling the batch back deletes the customer again.
+		_, reverted, _, err := repo.RollbackBatch("batch-1")
+		assert.Nil(t, err)
+		assert.Equal(t, 1, reverted)
+
+		_, err = repo.GetById(customer.ID)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		deleted, err := repo.WithDeleted().GetById(customer.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 10.0, deleted.TicketMedio)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		setupTestDB()
+		purchaseRepo, _ := databaseRepository.NewPostgresPurchaseRepository(db)
+
+		deleted, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+		kept, _ := entity.NewCustomer("046.857.249-09", false, false, nil, 10, 10, "NULL", "NULL")
+		_, _, _, err := repo.UpsertBulk([]*entity.Customer{deleted, kept})
+		assert.Nil(t, err)
+
+		purchase, _ := purchaseEntity.NewPurchase(deleted.ID, "79.379.491/0001-83", time.Date(2011, 10, 5, 0, 0, 0, 0, time.UTC), 10)
+		assert.Nil(t, purchaseRepo.CreateBulk([]*purchaseEntity.Purchase{purchase}))
+		assert.Nil(t, repo.Delete(deleted))
+
+		purged, err := repo.Purge(time.Now().Add(-time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, 0, purged)
+
+		purged, err = repo.Purge(time.Now().Add(time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, purged)
+
+		_, err = repo.WithDeleted().GetById(deleted.ID)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		_, err = purchaseRepo.GetById(purchase.ID)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		_, err = repo.GetById(kept.ID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("PurgeInBatches", func(t *testing.T) {
+		setupTestDB()
+
+		customers := make([]*entity.Customer, 1001)
+		for i := range customers {
+			customers[i], _ = entity.NewCustomer(fmt.Sprintf("%011d", i+1), false, false, nil, 10, 10, "NULL", "NULL")
+		}
+		_, _, _, err := repo.UpsertBulk(customers)
+		assert.Nil(t, err)
+		assert.Nil(t, db.Model(&entity.Customer{}).Where("1 = 1").Update("deleted_at", time.Now()).Error)
+
+		purged, err := repo.Purge(time.Now().Add(time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, 1001, purged)
+
+		var remaining int64
+		assert.Nil(t, db.Unscoped().Model(&entity.Customer{}).Count(&remaining).Error)
+		assert.Equal(t, int64(0), remaining)
+	})
+
+	t.Run("AuditLog", func(t *testing.T) {
+		setupTestDB()
+		audited := repo.WithAudit(entity.AuditInfo{Actor: "alice", RequestID: "req-1"})
+
+		customer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+		_, err := audited.Upsert(customer)
+		assert.Nil(t, err)
+
+		customer.TicketMedio = 15
+		assert.Nil(t, audited.Update(customer))
+
+		imported, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 20, 20, "NULL", "NULL")
+		imported.TraceTo("batch-1", "base_1.txt", "hash-1", 2)
+		_, _, _, err = audited.UpsertBulk([]*entity.Customer{imported})
+		assert.Nil(t, err)
+		assert.Equal(t, customer.ID, imported.ID)
+
+		// Importing the same values again changes nothing and is not logged.
+		again, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 20, 20, "NULL", "NULL")
+		again.TraceTo("batch-1", "base_1.txt", "hash-1", 2)
+		_, _, _, err = audited.UpsertBulk([]*entity.Customer{again})
+		assert.Nil(t, err)
+
+		_, _, _, err = audited.RollbackBatch("batch-1")
+		assert.Nil(t, err)
+		assert.Nil(t, audited.Delete(customer))
+		assert.Nil(t, audited.Restore(customer))
+		assert.Nil(t, audited.Delete(customer))
+		_, err = audited.Purge(time.Now().Add(time.Hour))
+		assert.Nil(t, err)
+
+		history, err := repo.GetHistory(customer.ID, 1)
+		assert.Nil(t, err)
+		operations := make([]string, len(history))
+		for i, audit := range history {
+			operations[i] = audit.Operation
+			assert.Equal(t, customer.ID, audit.CustomerID)
+			assert.Equal(t, "alice", audit.Actor)
+			assert.Equal(t, "req-1", audit.RequestID)
+		}
+		assert.Equal(t, []string{
+			entity.AuditOperationCreate,
+			entity.AuditOperationUpdate,
+			entity.AuditOperationImport,
+			entity.AuditOperationRollback,
+			entity.AuditOperationDelete,
+			entity.AuditOperationRestore,
+			entity.AuditOperationDelete,
+			entity.AuditOperationPurge,
+		}, operations)
+
+		assert.Equal(t, entity.FieldChange{Before: nil, After: "92248810920"}, history[0].Changes["cpf"])
+		assert.Equal(t, entity.FieldChange{Before: 10.0, After: 15.0}, history[1].Changes["ticket_medio"])
+		assert.Equal(t, "batch-1", history[2].ImportBatchID)
+		assert.Equal(t, entity.FieldChange{Before: 15.0, After: 20.0}, history[2].Changes["ticket_medio"])
+		assert.Equal(t, entity.FieldChange{Before: 20.0, After: 15.0}, history[3].Changes["ticket_medio"])
+		assert.Nil(t, history[4].Changes["deleted_at"].Before)
+		assert.NotNil(t, history[4].Changes["deleted_at"].After)
+		assert.Nil(t, history[5].Changes["deleted_at"].After)
+		assert.Equal(t, entity.FieldChange{Before: "92248810920", After: nil}, history[7].Changes["cpf"])
+	})
+}
+
+// syntheticCustomers parses a generated base file in the default layout, so the
+// benchmarks load rows shaped like a real import.
+func syntheticCustomers(b *testing.B, rows int) []*entity.Customer {
+	var file strings.Builder
+	file.WriteString("CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA\n")
+	for i := 0; i < rows; i++ {
+		fmt.Fprintf(&file, "%-19s%-12s%-12s%-22s%-22s%-24s%-20s%s\n",
+			fmt.Sprintf("%03d.%03d.%03d-%02d", i/1000000%1000, i/1000%1000, i%1000, i%100),
+			"0", "1", "2011-01-20", "159,31", "159,31", "79.379.491/0001-83", "79.379.491/0001-83")
+	}
+
+	parseService := service.NewParseTxtFileService(service.NewLayoutRegistry())
+	customers := make([]*entity.Customer, 0, rows)
+	err := parseService.StreamParse(strings.NewReader(file.String()), service.ParseOptions{}, func(line dto.ParsedCustomerLineDto) error {
+		if line.Err != nil {
+			return line.Err
+		}
+		customer, err := entity.NewCustomer(line.Customer.Cpf, line.Customer.Private, line.Customer.Incompleto, line.Customer.DataUltimaCompra,
+			line.Customer.TicketMedio, line.Customer.TicketUltimaCompra, line.Customer.LojaMaisFrequente, line.Customer.LojaUltimaCompra)
+		customers = append(customers, customer)
+		return err
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return customers
+}
+
+func benchmarkCreateBulk(b *testing.B, load func(*databaseRepository.CustomerRepositoryPostgres, []*entity.Customer) error) {
+	const rows = 10000
+	customers := syntheticCustomers(b, rows)
+	repo := &databaseRepository.CustomerRepositoryPostgres{Db: db}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		setupTestDB()
+		for _, customer := range customers {
+			customer.ID = shared.NewBaseEntity().ID
+		}
+		b.StartTimer()
+
+		if err := load(repo, customers); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(rows*b.N)/b.Elapsed().Seconds(), "rows/s")
+}
+
+func BenchmarkCreateBulkCopy(b *testing.B) {
+	benchmarkCreateBulk(b, (*databaseRepository.CustomerRepositoryPostgres).CreateBulkCopy)
+}
+
+func BenchmarkCreateBulkInsert(b *testing.B) {
+	benchmarkCreateBulk(b, (*databaseRepository.CustomerRepositoryPostgres).CreateBulkInsert)
 }
@@ -0,0 +1,71 @@
+package databaseRepository
+
+import (
+	"neoway_test/internal/domain/importjob/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ImportJobRepositoryMock struct {
+	mock.Mock
+}
+
+func (r *ImportJobRepositoryMock) Create(job *entity.ImportJob) error {
+	args := r.Called(job)
+	return args.Error(0)
+}
+
+func (r *ImportJobRepositoryMock) Update(job *entity.ImportJob) error {
+	args := r.Called(job)
+	return args.Error(0)
+}
+
+func (r *ImportJobRepositoryMock) Get(page int) ([]*entity.ImportJob, error) {
+	args := r.Called(page)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.ImportJob), nil
+}
+
+func (r *ImportJobRepositoryMock) GetById(id string) (*entity.ImportJob, error) {
+	args := r.Called(id)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ImportJob), nil
+}
+
+func (r *ImportJobRepositoryMock) Delete(job *entity.ImportJob) error {
+	args := r.Called(job)
+	return args.Error(0)
+}
+
+func (r *ImportJobRepositoryMock) ClaimNext() (*entity.ImportJob, error) {
+	args := r.Called()
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ImportJob), nil
+}
+
+func (r *ImportJobRepositoryMock) FailStale(staleBefore time.Time, reason string) error {
+	args := r.Called(staleBefore, reason)
+	return args.Error(0)
+}
+
+func (r *ImportJobRepositoryMock) Heartbeat(id string) error {
+	args := r.Called(id)
+	return args.Error(0)
+}
+
+func (r *ImportJobRepositoryMock) SaveProgress(job *entity.ImportJob) error {
+	args := r.Called(job)
+	return args.Error(0)
+}
+
+func (r *ImportJobRepositoryMock) Finish(job *entity.ImportJob) (bool, error) {
+	args := r.Called(job)
+	return args.Bool(0), args.Error(1)
+}
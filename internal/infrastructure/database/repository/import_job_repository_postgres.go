@@ -0,0 +1,106 @@
+package databaseRepository
+
+import (
+	"neoway_test/internal/domain/importjob/entity"
+	"neoway_test/internal/domain/importjob/repository"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ImportJobRepositoryPostgres struct {
+	Db *gorm.DB
+}
+
+func NewPostgresImportJobRepository(db *gorm.DB) (repository.ImportJobRepository, error) {
+	return &ImportJobRepositoryPostgres{Db: db}, nil
+}
+
+func (r *ImportJobRepositoryPostgres) Create(job *entity.ImportJob) error {
+	tx := r.Db.Create(job)
+	return tx.Error
+}
+
+func (r *ImportJobRepositoryPostgres) Update(job *entity.ImportJob) error {
+	tx := r.Db.Save(job)
+	return tx.Error
+}
+
+func (r *ImportJobRepositoryPostgres) Get(page int) ([]*entity.ImportJob, error) {
+	const pageSize = 100
+	offset := (page - 1) * pageSize
+
+	var jobs []*entity.ImportJob
+	tx := r.Db.Omit("report").Order("created_at desc").Limit(pageSize).Offset(offset).Find(&jobs)
+	return jobs, tx.Error
+}
+
+func (r *ImportJobRepositoryPostgres) GetById(id string) (*entity.ImportJob, error) {
+	var job entity.ImportJob
+	tx := r.Db.First(&job, "id = ?", id)
+	return &job, tx.Error
+}
+
+func (r *ImportJobRepositoryPostgres) Delete(job *entity.ImportJob) error {
+	tx := r.Db.Delete(job)
+	return tx.Error
+}
+
+func (r *ImportJobRepositoryPostgres) ClaimNext() (*entity.ImportJob, error) {
+	var job entity.ImportJob
+
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", entity.ImportJobQueued).
+			Order("created_at").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		job.Start()
+		return tx.Save(&job).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (r *ImportJobRepositoryPostgres) FailStale(staleBefore time.Time, reason string) error {
+	// Jobs started before heartbeats were recorded fall back to their start.
+	tx := r.Db.Model(&entity.ImportJob{}).
+		Where("status = ? AND coalesce(heartbeat_at, started_at) < ?", entity.ImportJobRunning, staleBefore).
+		Updates(map[string]interface{}{
+			"status":      entity.ImportJobFailed,
+			"error":       reason,
+			"finished_at": time.Now(),
+		})
+	return tx.Error
+}
+
+func (r *ImportJobRepositoryPostgres) Heartbeat(id string) error {
+	tx := r.Db.Model(&entity.ImportJob{}).
+		Where("id = ? AND status = ?", id, entity.ImportJobRunning).
+		Update("heartbeat_at", time.Now())
+	return tx.Error
+}
+
+func (r *ImportJobRepositoryPostgres) SaveProgress(job *entity.ImportJob) error {
+	tx := r.Db.Model(job).
+		Where("status = ?", entity.ImportJobRunning).
+		Select("rows_processed", "rows_rejected", "heartbeat_at").
+		Updates(job)
+	return tx.Error
+}
+
+func (r *ImportJobRepositoryPostgres) Finish(job *entity.ImportJob) (bool, error) {
+	tx := r.Db.Model(job).
+		Where("status = ?", entity.ImportJobRunning).
+		Select("status", "file_hash", "rows_processed", "rows_rejected", "error", "report", "finished_at").
+		Updates(job)
+	return tx.RowsAffected > 0, tx.Error
+}
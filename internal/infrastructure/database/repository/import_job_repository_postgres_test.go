@@ -0,0 +1,143 @@
+package databaseRepository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	customerDto "neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/importjob/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+)
+
+func setupImportJobTestDB() {
+	db.Exec("DROP TABLE IF EXISTS import_jobs")
+	db.AutoMigrate(&entity.ImportJob{})
+}
+
+func TestPostgresImportJobRepository(t *testing.T) {
+	repo, _ := databaseRepository.NewPostgresImportJobRepository(db)
+
+	t.Run("CreateAndUpdate", func(t *testing.T) {
+		setupImportJobTestDB()
+
+		job := entity.NewImportJob("base_teste.txt", "/tmp/import-1", "txt", "", "", false, "")
+		err := repo.Create(job)
+		assert.Nil(t, err)
+
+		job.Start()
+		job.Succeed(customerDto.OutputCreateCustomerBulkDto{
+			Accepted: 1,
+			Rejected: 1,
+			RejectedLines: []customerDto.RejectedCustomerLineDto{
+				{LineNumber: 3, Content: "041.091.641-25", Reason: "invalid file format: line too short"},
+			},
+		})
+		err = repo.Update(job)
+		assert.Nil(t, err)
+
+		storedJob, err := repo.GetById(job.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.ImportJobSucceeded, storedJob.Status)
+		assert.Equal(t, 2, storedJob.RowsProcessed)
+		assert.Equal(t, 1, storedJob.RowsRejected)
+		assert.Equal(t, job.Report, storedJob.Report)
+		assert.NotNil(t, storedJob.FinishedAt)
+	})
+
+	t.Run("ClaimNext", func(t *testing.T) {
+		setupImportJobTestDB()
+
+		job := entity.NewImportJob("base_teste.txt", "/tmp/import-1", "txt", "", "", false, "")
+		repo.Create(job)
+
+		claimed, err := repo.ClaimNext()
+		assert.Nil(t, err)
+		assert.Equal(t, job.ID, claimed.ID)
+		assert.Equal(t, entity.ImportJobRunning, claimed.Status)
+		assert.NotNil(t, claimed.StartedAt)
+
+		_, err = repo.ClaimNext()
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+
+	t.Run("FailStale", func(t *testing.T) {
+		setupImportJobTestDB()
+
+		stale := entity.NewImportJob("base_teste.txt", "/tmp/import-1", "txt", "", "", false, "")
+		stale.Start()
+		old := time.Now().Add(-time.Hour)
+		stale.HeartbeatAt = &old
+		repo.Create(stale)
+
+		live := entity.NewImportJob("base_teste.txt", "/tmp/import-2", "txt", "", "", false, "")
+		live.Start()
+		repo.Create(live)
+
+		err := repo.FailStale(time.Now().Add(-time.Minute), "import interrupted")
+		assert.Nil(t, err)
+
+		storedJob, err := repo.GetById(stale.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.ImportJobFailed, storedJob.Status)
+		assert.Equal(t, "import interrupted", storedJob.Error)
+
+		storedJob, err = repo.GetById(live.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.ImportJobRunning, storedJob.Status)
+	})
+
+	t.Run("ProgressCannotUndoFailStale", func(t *testing.T) {
+		setupImportJobTestDB()
+
+		job := entity.NewImportJob("base_teste.txt", "/tmp/import-1", "txt", "", "", false, "")
+		job.Start()
+		old := time.Now().Add(-time.Hour)
+		job.HeartbeatAt = &old
+		repo.Create(job)
+
+		assert.Nil(t, repo.FailStale(time.Now().Add(-time.Minute), "import interrupted"))
+
+		job.Progress(10, 1)
+		assert.Nil(t, repo.SaveProgress(job))
+		assert.Nil(t, repo.Heartbeat(job.ID))
+		job.Succeed(customerDto.OutputCreateCustomerBulkDto{Accepted: 10})
+		finished, err := repo.Finish(job)
+		assert.Nil(t, err)
+		assert.False(t, finished)
+
+		storedJob, err := repo.GetById(job.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.ImportJobFailed, storedJob.Status)
+		assert.Equal(t, "import interrupted", storedJob.Error)
+		assert.Equal(t, 0, storedJob.RowsProcessed)
+	})
+
+	t.Run("ProgressAndFinish", func(t *testing.T) {
+		setupImportJobTestDB()
+
+		job := entity.NewImportJob("base_teste.txt", "/tmp/import-1", "txt", "", "", false, "")
+		job.Start()
+		repo.Create(job)
+
+		job.Progress(10, 1)
+		assert.Nil(t, repo.SaveProgress(job))
+		storedJob, err := repo.GetById(job.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 10, storedJob.RowsProcessed)
+		assert.Equal(t, entity.ImportJobRunning, storedJob.Status)
+
+		job.Succeed(customerDto.OutputCreateCustomerBulkDto{Accepted: 10, Rejected: 1})
+		finished, err := repo.Finish(job)
+		assert.Nil(t, err)
+		assert.True(t, finished)
+
+		storedJob, err = repo.GetById(job.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.ImportJobSucceeded, storedJob.Status)
+		assert.Equal(t, 11, storedJob.RowsProcessed)
+		assert.NotNil(t, storedJob.FinishedAt)
+	})
+}
@@ -0,0 +1,109 @@
+package databaseRepository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+var errCopyUnsupported = errors.New("copy from stdin is not supported by this connection")
+
+// copySource holds rows ready for COPY. Columns come from the gorm schema of
+// the model, so the table layout is described in a single place.
+type copySource struct {
+	table   string
+	columns []string
+	rows    [][]interface{}
+}
+
+func newCopySource[T any](db *gorm.DB, rows []*T) (copySource, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return copySource{}, err
+	}
+
+	source := copySource{table: stmt.Schema.Table}
+	fields := stmt.Schema.Fields[:0:0]
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || !field.Creatable {
+			continue
+		}
+		source.columns = append(source.columns, field.DBName)
+		fields = append(fields, field)
+	}
+
+	ctx := context.Background()
+	source.rows = make([][]interface{}, len(rows))
+	for i, row := range rows {
+		value := reflect.ValueOf(row).Elem()
+		source.rows[i] = make([]interface{}, len(fields))
+		for j, field := range fields {
+			source.rows[i][j], _ = field.ValueOf(ctx, value)
+		}
+	}
+
+	return source, nil
+}
+
+// copyInto loads the rows of source into table with COPY FROM STDIN.
+type copyInto func(table string, source copySource) error
+
+// withCopyTx runs fn in a transaction on a single connection taken from the
+// pool. fn gets a gorm handle on the transaction and a copyInto that runs
+// COPY on the same connection, so everything fn writes is committed or rolled
+// back together. It fails with errCopyUnsupported inside a transaction or
+// when the driver is not pgx, before anything is written.
+func withCopyTx(db *gorm.DB, fn func(tx *gorm.DB, copyRows copyInto) error) error {
+	ctx := context.Background()
+
+	if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); ok {
+		return errCopyUnsupported
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return errCopyUnsupported
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		if _, ok := driverConn.(*stdlib.Conn); !ok {
+			return errCopyUnsupported
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sqlTx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer sqlTx.Rollback()
+
+	tx := db.Session(&gorm.Session{Context: ctx})
+	tx.Statement.ConnPool = sqlTx
+
+	// The transaction is idle between statements, so COPY can borrow the
+	// connection underneath it.
+	copyRows := func(table string, source copySource) error {
+		return conn.Raw(func(driverConn interface{}) error {
+			pgxConn := driverConn.(*stdlib.Conn).Conn()
+			_, err := pgxConn.CopyFrom(ctx, pgx.Identifier{table}, source.columns, pgx.CopyFromRows(source.rows))
+			return err
+		})
+	}
+
+	if err := fn(tx, copyRows); err != nil {
+		return err
+	}
+	return sqlTx.Commit()
+}
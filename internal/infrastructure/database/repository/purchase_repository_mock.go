@@ -0,0 +1,57 @@
+package databaseRepository
+
+import (
+	customerEntity "neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/purchase/entity"
+	"neoway_test/internal/domain/purchase/repository"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PurchaseRepositoryMock struct {
+	mock.Mock
+}
+
+func (r *PurchaseRepositoryMock) Create(purchase *entity.Purchase) error {
+	args := r.Called(purchase)
+	return args.Error(0)
+}
+
+func (r *PurchaseRepositoryMock) CreateBulk(purchases []*entity.Purchase) error {
+	args := r.Called(purchases)
+	return args.Error(0)
+}
+
+func (r *PurchaseRepositoryMock) WithAudit(info customerEntity.AuditInfo) repository.PurchaseRepository {
+	args := r.Called(info)
+	return args.Get(0).(repository.PurchaseRepository)
+}
+
+func (r *PurchaseRepositoryMock) Find(filter repository.PurchaseFilter, page int) ([]*entity.Purchase, error) {
+	args := r.Called(filter, page)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Purchase), nil
+}
+
+func (r *PurchaseRepositoryMock) Get(page int) ([]*entity.Purchase, error) {
+	args := r.Called(page)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Purchase), nil
+}
+
+func (r *PurchaseRepositoryMock) GetById(id string) (*entity.Purchase, error) {
+	args := r.Called(id)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Purchase), nil
+}
+
+func (r *PurchaseRepositoryMock) Delete(purchase *entity.Purchase) error {
+	args := r.Called(purchase)
+	return args.Error(0)
+}
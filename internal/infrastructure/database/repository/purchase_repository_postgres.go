@@ -0,0 +1,167 @@
+package databaseRepository
+
+import (
+	customerEntity "neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/purchase/entity"
+	"neoway_test/internal/domain/purchase/repository"
+
+	"gorm.io/gorm"
+)
+
+// purchaseSummaryColumns are the customer columns derived from purchases.
+var purchaseSummaryColumns = []string{
+	"data_ultima_compra",
+	"ticket_medio",
+	"ticket_ultima_compra",
+	"loja_mais_frequente",
+	"cnpj_loja_mais_frequente_valido",
+	"loja_mais_frequente_cnpj",
+	"loja_ultima_compra",
+	"cnpj_loja_ultima_compra_valido",
+	"loja_ultima_compra_cnpj",
+}
+
+type PurchaseRepositoryPostgres struct {
+	Db    *gorm.DB
+	audit customerEntity.AuditInfo
+}
+
+func NewPostgresPurchaseRepository(db *gorm.DB) (repository.PurchaseRepository, error) {
+	return &PurchaseRepositoryPostgres{Db: db}, nil
+}
+
+func (r *PurchaseRepositoryPostgres) Create(purchase *entity.Purchase) error {
+	return r.CreateBulk([]*entity.Purchase{purchase})
+}
+
+func (r *PurchaseRepositoryPostgres) CreateBulk(purchases []*entity.Purchase) error {
+	if len(purchases) == 0 {
+		return nil
+	}
+
+	cnpjs := make([]string, len(purchases))
+	customerIDs := make([]string, len(purchases))
+	for i, purchase := range purchases {
+		cnpjs[i] = purchase.LojaCnpj
+		customerIDs[i] = purchase.CustomerID
+	}
+
+	return r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := saveStores(tx, cnpjs); err != nil {
+			return err
+		}
+		if err := tx.CreateInBatches(purchases, 1000).Error; err != nil {
+			return err
+		}
+		return r.summarize(tx, customerIDs)
+	})
+}
+
+func (r *PurchaseRepositoryPostgres) WithAudit(info customerEntity.AuditInfo) repository.PurchaseRepository {
+	return &PurchaseRepositoryPostgres{Db: r.Db, audit: info}
+}
+
+func (r *PurchaseRepositoryPostgres) Get(page int) ([]*entity.Purchase, error) {
+	return r.Find(repository.PurchaseFilter{}, page)
+}
+
+func (r *PurchaseRepositoryPostgres) Find(filter repository.PurchaseFilter, page int) ([]*entity.Purchase, error) {
+	const pageSize = 100
+	offset := (page - 1) * pageSize
+
+	query := r.Db.Order("data desc, created_at desc, id").Limit(pageSize).Offset(offset)
+	if filter.CustomerID != "" {
+		query = query.Where("customer_id = ?", filter.CustomerID)
+	}
+	if filter.LojaCnpj != "" {
+		query = query.Where("loja_cnpj = ?", filter.LojaCnpj)
+	}
+	if filter.From != nil {
+		query = query.Where("data >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("data < ?", *filter.To)
+	}
+
+	var purchases []*entity.Purchase
+	tx := query.Find(&purchases)
+	return purchases, tx.Error
+}
+
+func (r *PurchaseRepositoryPostgres) GetById(id string) (*entity.Purchase, error) {
+	var purchase entity.Purchase
+	tx := r.Db.First(&purchase, "id = ?", id)
+	return &purchase, tx.Error
+}
+
+// Delete removes the purchase. A customer left without purchases keeps the
+// values derived from the purchases it had.
+func (r *PurchaseRepositoryPostgres) Delete(purchase *entity.Purchase) error {
+	return r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(purchase).Error; err != nil {
+			return err
+		}
+		return r.summarize(tx, []string{purchase.CustomerID})
+	})
+}
+
+// summarize derives the purchase fields of the customers with the given IDs
+// again and logs the change of each as an update.
+func (r *PurchaseRepositoryPostgres) summarize(tx *gorm.DB, customerIDs []string) error {
+	byID := func(customer *customerEntity.Customer) string { return customer.ID }
+	before, err := findCustomers(tx, "id", customerIDs, byID)
+	if err != nil {
+		return err
+	}
+	if _, err := summarizeCustomers(tx, customerIDs); err != nil {
+		return err
+	}
+	after, err := findCustomers(tx, "id", customerIDs, byID)
+	if err != nil {
+		return err
+	}
+
+	audits := make([]*customerEntity.CustomerAudit, 0, len(before))
+	seen := make(map[string]bool, len(before))
+	for _, id := range customerIDs {
+		if seen[id] || before[id] == nil {
+			continue
+		}
+		seen[id] = true
+		audits = append(audits, customerEntity.NewCustomerAudit(r.audit, customerEntity.AuditOperationUpdate, "", before[id], after[id]))
+	}
+	return saveAudits(tx, audits)
+}
+
+// summarizeCustomers derives the purchase fields of the customers with the
+// given IDs from their purchases and returns the summaries by customer ID.
+// Customers without purchases are left alone.
+func summarizeCustomers(tx *gorm.DB, customerIDs []string) (map[string]entity.PurchaseSummary, error) {
+	summaries := make(map[string]entity.PurchaseSummary)
+	if len(customerIDs) == 0 {
+		return summaries, nil
+	}
+
+	var purchases []*entity.Purchase
+	if err := tx.Where("customer_id IN ?", customerIDs).Find(&purchases).Error; err != nil {
+		return nil, err
+	}
+
+	byCustomer := make(map[string][]*entity.Purchase)
+	for _, purchase := range purchases {
+		byCustomer[purchase.CustomerID] = append(byCustomer[purchase.CustomerID], purchase)
+	}
+
+	for customerID, customerPurchases := range byCustomer {
+		summary, _ := entity.Summarize(customerPurchases)
+		summaries[customerID] = summary
+
+		customer := &customerEntity.Customer{}
+		customer.ID = customerID
+		customer.ApplyPurchases(summary)
+		if err := tx.Model(customer).Select(purchaseSummaryColumns).Updates(customer).Error; err != nil {
+			return nil, err
+		}
+	}
+	return summaries, nil
+}
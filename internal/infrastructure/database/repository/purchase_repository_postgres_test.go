@@ -0,0 +1,177 @@
+package databaseRepository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	customerEntity "neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/purchase/entity"
+	"neoway_test/internal/domain/purchase/repository"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+)
+
+func TestPostgresPurchaseRepository(t *testing.T) {
+	repo, _ := databaseRepository.NewPostgresPurchaseRepository(db)
+	customerRepo, _ := databaseRepository.NewPostgresCustomerRepository(db)
+	storeRepo, _ := databaseRepository.NewPostgresStoreRepository(db)
+
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	t.Run("CreateBulkSummarizesCustomers", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := customerEntity.NewCustomer("922.488.109-20", false, false, nil, 999, 999, "NULL", "NULL")
+		err := customerRepo.Create(customer)
+		assert.Nil(t, err)
+
+		first, _ := entity.NewPurchase(customer.ID, "79.379.491/0001-83", day(1), 100)
+		second, _ := entity.NewPurchase(customer.ID, "79.379.491/0001-83", day(2), 60)
+		last, _ := entity.NewPurchase(customer.ID, "79.379.491/0008-50", day(3), 20)
+		err = repo.CreateBulk([]*entity.Purchase{first, second, last})
+		assert.Nil(t, err)
+
+		_, err = storeRepo.GetByCnpj("79379491000850")
+		assert.Nil(t, err)
+
+		storedCustomer, err := customerRepo.GetById(customer.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 60.0, storedCustomer.TicketMedio)
+		assert.Equal(t, 20.0, storedCustomer.TicketUltimaCompra)
+		assert.Equal(t, "2024-03-03", storedCustomer.DataUltimaCompra.UTC().Format("2006-01-02"))
+		assert.Equal(t, "79379491000183", *storedCustomer.LojaMaisFrequenteCnpj)
+		assert.Equal(t, "79379491000850", *storedCustomer.LojaUltimaCompraCnpj)
+	})
+
+	t.Run("CreateBulkAuditsCustomers", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := customerEntity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+		customerRepo.Create(customer)
+
+		purchase, _ := entity.NewPurchase(customer.ID, "79379491000183", day(1), 40)
+		other, _ := entity.NewPurchase(customer.ID, "79379491000183", day(2), 20)
+		err := repo.WithAudit(customerEntity.AuditInfo{Actor: "alice", RequestID: "req-1"}).CreateBulk([]*entity.Purchase{purchase, other})
+		assert.Nil(t, err)
+
+		history, err := customerRepo.GetHistory(customer.ID, 1)
+		assert.Nil(t, err)
+		assert.Len(t, history, 2)
+		assert.Equal(t, customerEntity.AuditOperationUpdate, history[1].Operation)
+		assert.Equal(t, "alice", history[1].Actor)
+		assert.Equal(t, "req-1", history[1].RequestID)
+		assert.Equal(t, customerEntity.FieldChange{Before: 10.0, After: 30.0}, history[1].Changes["ticket_medio"])
+		assert.Equal(t, customerEntity.FieldChange{Before: 10.0, After: 20.0}, history[1].Changes["ticket_ultima_compra"])
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := customerEntity.NewCustomer("922.488.109-20", false, false, nil, 0, 0, "NULL", "NULL")
+		other, _ := customerEntity.NewCustomer("041.091.641-25", false, false, nil, 0, 0, "NULL", "NULL")
+		customerRepo.Create(customer)
+		customerRepo.Create(other)
+
+		first, _ := entity.NewPurchase(customer.ID, "79379491000183", day(1), 10)
+		second, _ := entity.NewPurchase(customer.ID, "79379491000850", day(5), 20)
+		third, _ := entity.NewPurchase(other.ID, "79379491000183", day(3), 30)
+		err := repo.CreateBulk([]*entity.Purchase{first, second, third})
+		assert.Nil(t, err)
+
+		purchases, err := repo.Find(repository.PurchaseFilter{}, 1)
+		assert.Nil(t, err)
+		assert.Len(t, purchases, 3)
+		assert.Equal(t, second.ID, purchases[0].ID)
+
+		purchases, err = repo.Find(repository.PurchaseFilter{CustomerID: customer.ID, LojaCnpj: "79379491000183"}, 1)
+		assert.Nil(t, err)
+		assert.Len(t, purchases, 1)
+		assert.Equal(t, first.ID, purchases[0].ID)
+
+		from, to := day(2), day(5)
+		purchases, err = repo.Find(repository.PurchaseFilter{From: &from, To: &to}, 1)
+		assert.Nil(t, err)
+		assert.Len(t, purchases, 1)
+		assert.Equal(t, third.ID, purchases[0].ID)
+
+		purchases, err = repo.Find(repository.PurchaseFilter{}, 2)
+		assert.Nil(t, err)
+		assert.Empty(t, purchases)
+	})
+
+	t.Run("DeleteSummarizesCustomer", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := customerEntity.NewCustomer("922.488.109-20", false, false, nil, 0, 0, "NULL", "NULL")
+		customerRepo.Create(customer)
+
+		first, _ := entity.NewPurchase(customer.ID, "79379491000183", day(1), 10)
+		last, _ := entity.NewPurchase(customer.ID, "79379491000850", day(2), 30)
+		repo.CreateBulk([]*entity.Purchase{first, last})
+
+		err := repo.Delete(last)
+		assert.Nil(t, err)
+
+		storedCustomer, _ := customerRepo.GetById(customer.ID)
+		assert.Equal(t, 10.0, storedCustomer.TicketMedio)
+		assert.Equal(t, "79379491000183", storedCustomer.LojaUltimaCompra)
+	})
+
+	t.Run("UpsertKeepsPurchaseSummary", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := customerEntity.NewCustomer("922.488.109-20", false, false, nil, 0, 0, "NULL", "NULL")
+		customerRepo.Create(customer)
+		purchase, _ := entity.NewPurchase(customer.ID, "79379491000183", day(1), 40)
+		repo.Create(purchase)
+
+		date := day(20)
+		imported, _ := customerEntity.NewCustomer("922.488.109-20", true, false, &date, 999, 999, "79.379.491/0008-50", "79.379.491/0008-50")
+		_, _, _, err := customerRepo.UpsertBulk([]*customerEntity.Customer{imported})
+		assert.Nil(t, err)
+		assert.Equal(t, 40.0, imported.TicketMedio)
+
+		storedCustomer, _ := customerRepo.GetById(customer.ID)
+		assert.True(t, storedCustomer.Private)
+		assert.Equal(t, 40.0, storedCustomer.TicketMedio)
+		assert.Equal(t, "2024-03-01", storedCustomer.DataUltimaCompra.UTC().Format("2006-01-02"))
+		assert.Equal(t, "79379491000183", storedCustomer.LojaMaisFrequente)
+	})
+
+	t.Run("UpdateKeepsPurchaseSummary", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := customerEntity.NewCustomer("922.488.109-20", false, false, nil, 0, 0, "NULL", "NULL")
+		customerRepo.Create(customer)
+		purchase, _ := entity.NewPurchase(customer.ID, "79379491000183", day(1), 40)
+		repo.Create(purchase)
+
+		updated, _ := customerEntity.NewCustomer("922.488.109-20", true, false, nil, 999, 999, "NULL", "NULL")
+		updated.BaseEntity = customer.BaseEntity
+		err := customerRepo.Update(updated)
+		assert.Nil(t, err)
+		assert.Equal(t, 40.0, updated.TicketMedio)
+
+		storedCustomer, _ := customerRepo.GetById(customer.ID)
+		assert.True(t, storedCustomer.Private)
+		assert.Equal(t, 40.0, storedCustomer.TicketMedio)
+		assert.Equal(t, "79379491000183", storedCustomer.LojaUltimaCompra)
+	})
+
+	t.Run("DeletingCustomerDeletesPurchases", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := customerEntity.NewCustomer("922.488.109-20", false, false, nil, 0, 0, "NULL", "NULL")
+		customerRepo.Create(customer)
+		purchase, _ := entity.NewPurchase(customer.ID, "79379491000183", day(1), 40)
+		repo.Create(purchase)
+
+		err := customerRepo.Delete(customer)
+		assert.Nil(t, err)
+
+		purchases, err := repo.Find(repository.PurchaseFilter{CustomerID: customer.ID}, 1)
+		assert.Nil(t, err)
+		assert.Empty(t, purchases)
+	})
+}
@@ -0,0 +1,45 @@
+package databaseRepository
+
+import (
+	"neoway_test/internal/domain/store/entity"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type StoreRepositoryMock struct {
+	mock.Mock
+}
+
+func (r *StoreRepositoryMock) Create(store *entity.Store) error {
+	args := r.Called(store)
+	return args.Error(0)
+}
+
+func (r *StoreRepositoryMock) Get(page int) ([]*entity.Store, error) {
+	args := r.Called(page)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.Store), nil
+}
+
+func (r *StoreRepositoryMock) GetById(id string) (*entity.Store, error) {
+	args := r.Called(id)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Store), nil
+}
+
+func (r *StoreRepositoryMock) GetByCnpj(cnpj string) (*entity.Store, error) {
+	args := r.Called(cnpj)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Store), nil
+}
+
+func (r *StoreRepositoryMock) Delete(store *entity.Store) error {
+	args := r.Called(store)
+	return args.Error(0)
+}
@@ -0,0 +1,76 @@
+package databaseRepository
+
+import (
+	"neoway_test/internal/domain/store/entity"
+	"neoway_test/internal/domain/store/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StoreRepositoryPostgres struct {
+	Db *gorm.DB
+}
+
+func NewPostgresStoreRepository(db *gorm.DB) (repository.StoreRepository, error) {
+	return &StoreRepositoryPostgres{Db: db}, nil
+}
+
+func (r *StoreRepositoryPostgres) Create(store *entity.Store) error {
+	tx := r.Db.Create(store)
+	return tx.Error
+}
+
+func (r *StoreRepositoryPostgres) Get(page int) ([]*entity.Store, error) {
+	const pageSize = 100
+	offset := (page - 1) * pageSize
+
+	var stores []*entity.Store
+	tx := r.Db.Order("cnpj").Limit(pageSize).Offset(offset).Find(&stores)
+	return stores, tx.Error
+}
+
+func (r *StoreRepositoryPostgres) GetById(id string) (*entity.Store, error) {
+	var store entity.Store
+	tx := r.Db.First(&store, "id = ?", id)
+	return &store, tx.Error
+}
+
+func (r *StoreRepositoryPostgres) GetByCnpj(cnpj string) (*entity.Store, error) {
+	var store entity.Store
+	normalized := entity.NormalizeCnpj(cnpj)
+	if normalized == "" {
+		return &store, gorm.ErrRecordNotFound
+	}
+	tx := r.Db.First(&store, "cnpj = ?", normalized)
+	return &store, tx.Error
+}
+
+// Delete removes the store; customers referring to it keep the CNPJ text but
+// lose the reference.
+func (r *StoreRepositoryPostgres) Delete(store *entity.Store) error {
+	tx := r.Db.Delete(store)
+	return tx.Error
+}
+
+// saveStores creates the stores with the given CNPJs that are not stored yet.
+// Records referring to stores call it before being written, so their foreign
+// keys hold.
+func saveStores(tx *gorm.DB, cnpjs []string) error {
+	seen := make(map[string]bool)
+	var stores []*entity.Store
+	for _, cnpj := range cnpjs {
+		store := entity.NewStore(cnpj)
+		if store == nil || seen[store.Cnpj] {
+			continue
+		}
+		seen[store.Cnpj] = true
+		stores = append(stores, store)
+	}
+	if len(stores) == 0 {
+		return nil
+	}
+
+	return tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "cnpj"}}, DoNothing: true}).
+		CreateInBatches(stores, 1000).Error
+}
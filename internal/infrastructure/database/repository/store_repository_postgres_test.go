@@ -0,0 +1,72 @@
+package databaseRepository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	customerEntity "neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/store/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+)
+
+func TestPostgresStoreRepository(t *testing.T) {
+	repo, _ := databaseRepository.NewPostgresStoreRepository(db)
+	customerRepo, _ := databaseRepository.NewPostgresCustomerRepository(db)
+
+	t.Run("CreateAndGetByCnpj", func(t *testing.T) {
+		setupTestDB()
+
+		store := entity.NewStore("79.379.491/0001-83")
+		store.Nome = "LOJA CENTRO"
+		store.Metadata = map[string]string{"cidade": "Florianópolis"}
+		err := repo.Create(store)
+		assert.Nil(t, err)
+
+		for _, cnpj := range []string{"79379491000183", "79.379.491/0001-83"} {
+			storedStore, err := repo.GetByCnpj(cnpj)
+			assert.Nil(t, err)
+			assert.Equal(t, store.ID, storedStore.ID)
+			assert.Equal(t, "LOJA CENTRO", storedStore.Nome)
+			assert.Equal(t, store.Metadata, storedStore.Metadata)
+		}
+
+		_, err = repo.GetByCnpj("NULL")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		setupTestDB()
+
+		repo.Create(entity.NewStore("79.379.491/0008-50"))
+		repo.Create(entity.NewStore("79.379.491/0001-83"))
+
+		stores, err := repo.Get(1)
+		assert.Nil(t, err)
+		assert.Len(t, stores, 2)
+		assert.Equal(t, "79379491000183", stores[0].Cnpj)
+
+		stores, err = repo.Get(2)
+		assert.Nil(t, err)
+		assert.Empty(t, stores)
+	})
+
+	t.Run("DeleteKeepsCustomers", func(t *testing.T) {
+		setupTestDB()
+
+		customer, _ := customerEntity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "79.379.491/0001-83", "NULL")
+		err := customerRepo.Create(customer)
+		assert.Nil(t, err)
+
+		store, err := repo.GetByCnpj("79379491000183")
+		assert.Nil(t, err)
+		err = repo.Delete(store)
+		assert.Nil(t, err)
+
+		storedCustomer, err := customerRepo.GetById(customer.ID)
+		assert.Nil(t, err)
+		assert.Nil(t, storedCustomer.LojaMaisFrequenteCnpj)
+		assert.Equal(t, "79.379.491/0001-83", storedCustomer.LojaMaisFrequente)
+	})
+}
@@ -0,0 +1,66 @@
+package databaseRepository
+
+import (
+	"neoway_test/internal/domain/uploadsession/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type UploadSessionRepositoryMock struct {
+	mock.Mock
+}
+
+func (r *UploadSessionRepositoryMock) Create(session *entity.UploadSession) error {
+	args := r.Called(session)
+	return args.Error(0)
+}
+
+func (r *UploadSessionRepositoryMock) Update(session *entity.UploadSession) error {
+	args := r.Called(session)
+	return args.Error(0)
+}
+
+func (r *UploadSessionRepositoryMock) ClaimChunk(session *entity.UploadSession, token string) (bool, error) {
+	args := r.Called(session, token)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *UploadSessionRepositoryMock) CommitChunk(session *entity.UploadSession, token string, offset int64) (bool, error) {
+	args := r.Called(session, token, offset)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *UploadSessionRepositoryMock) ClaimFinalize(session *entity.UploadSession) (bool, error) {
+	args := r.Called(session)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *UploadSessionRepositoryMock) DeleteStale(before time.Time) ([]*entity.UploadSession, error) {
+	args := r.Called(before)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.UploadSession), nil
+}
+
+func (r *UploadSessionRepositoryMock) Get(page int) ([]*entity.UploadSession, error) {
+	args := r.Called(page)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entity.UploadSession), nil
+}
+
+func (r *UploadSessionRepositoryMock) GetById(id string) (*entity.UploadSession, error) {
+	args := r.Called(id)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UploadSession), nil
+}
+
+func (r *UploadSessionRepositoryMock) Delete(session *entity.UploadSession) error {
+	args := r.Called(session)
+	return args.Error(0)
+}
@@ -0,0 +1,92 @@
+package databaseRepository
+
+import (
+	"neoway_test/internal/domain/uploadsession/entity"
+	"neoway_test/internal/domain/uploadsession/repository"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UploadSessionRepositoryPostgres struct {
+	Db *gorm.DB
+}
+
+func NewPostgresUploadSessionRepository(db *gorm.DB) (repository.UploadSessionRepository, error) {
+	return &UploadSessionRepositoryPostgres{Db: db}, nil
+}
+
+func (r *UploadSessionRepositoryPostgres) Create(session *entity.UploadSession) error {
+	tx := r.Db.Create(session)
+	return tx.Error
+}
+
+func (r *UploadSessionRepositoryPostgres) Update(session *entity.UploadSession) error {
+	tx := r.Db.Save(session)
+	return tx.Error
+}
+
+func (r *UploadSessionRepositoryPostgres) ClaimChunk(session *entity.UploadSession, token string) (bool, error) {
+	now := time.Now()
+	tx := r.Db.Model(&entity.UploadSession{}).
+		Where("id = ? AND status = ? AND received_bytes = ? AND (chunk_token = '' OR chunk_claimed_at < ?)",
+			session.ID, entity.UploadSessionOpen, session.ReceivedBytes, now.Add(-entity.ChunkLease)).
+		Updates(map[string]interface{}{
+			"chunk_token":      token,
+			"chunk_claimed_at": now,
+			"updated_at":       now,
+		})
+	return tx.RowsAffected > 0, tx.Error
+}
+
+func (r *UploadSessionRepositoryPostgres) CommitChunk(session *entity.UploadSession, token string, offset int64) (bool, error) {
+	tx := r.Db.Model(&entity.UploadSession{}).
+		Where("id = ? AND chunk_token = ? AND received_bytes = ?", session.ID, token, offset).
+		Updates(map[string]interface{}{
+			"received_bytes":   session.ReceivedBytes,
+			"updated_at":       session.UpdatedAt,
+			"chunk_token":      "",
+			"chunk_claimed_at": nil,
+		})
+	return tx.RowsAffected > 0, tx.Error
+}
+
+func (r *UploadSessionRepositoryPostgres) ClaimFinalize(session *entity.UploadSession) (bool, error) {
+	tx := r.Db.Model(&entity.UploadSession{}).
+		Where("id = ? AND status = ? AND received_bytes = ? AND (chunk_token = '' OR chunk_claimed_at < ?)",
+			session.ID, entity.UploadSessionOpen, session.ReceivedBytes, time.Now().Add(-entity.ChunkLease)).
+		Updates(map[string]interface{}{
+			"status":     entity.UploadSessionFinalized,
+			"updated_at": time.Now(),
+		})
+	return tx.RowsAffected > 0, tx.Error
+}
+
+func (r *UploadSessionRepositoryPostgres) DeleteStale(before time.Time) ([]*entity.UploadSession, error) {
+	var sessions []*entity.UploadSession
+	tx := r.Db.Clauses(clause.Returning{}).
+		Where("status = ? AND updated_at < ?", entity.UploadSessionOpen, before).
+		Delete(&sessions)
+	return sessions, tx.Error
+}
+
+func (r *UploadSessionRepositoryPostgres) Get(page int) ([]*entity.UploadSession, error) {
+	const pageSize = 100
+	offset := (page - 1) * pageSize
+
+	var sessions []*entity.UploadSession
+	tx := r.Db.Order("created_at desc").Limit(pageSize).Offset(offset).Find(&sessions)
+	return sessions, tx.Error
+}
+
+func (r *UploadSessionRepositoryPostgres) GetById(id string) (*entity.UploadSession, error) {
+	var session entity.UploadSession
+	tx := r.Db.First(&session, "id = ?", id)
+	return &session, tx.Error
+}
+
+func (r *UploadSessionRepositoryPostgres) Delete(session *entity.UploadSession) error {
+	tx := r.Db.Delete(session)
+	return tx.Error
+}
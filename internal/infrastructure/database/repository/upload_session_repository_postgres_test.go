@@ -0,0 +1,145 @@
+package databaseRepository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"neoway_test/internal/domain/uploadsession/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+)
+
+func setupUploadSessionTestDB() {
+	db.Exec("DROP TABLE IF EXISTS upload_sessions")
+	db.AutoMigrate(&entity.UploadSession{})
+}
+
+func TestPostgresUploadSessionRepository(t *testing.T) {
+	repo, _ := databaseRepository.NewPostgresUploadSessionRepository(db)
+
+	t.Run("CreateAndUpdate", func(t *testing.T) {
+		setupUploadSessionTestDB()
+
+		session := entity.NewUploadSession("base_teste.txt", "text/plain", "/tmp/upload-1", "txt", "", "", false, "", 100)
+		err := repo.Create(session)
+		assert.Nil(t, err)
+
+		session.Receive(100)
+		session.Finalize("job-1")
+		err = repo.Update(session)
+		assert.Nil(t, err)
+
+		storedSession, err := repo.GetById(session.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.UploadSessionFinalized, storedSession.Status)
+		assert.Equal(t, int64(100), storedSession.ReceivedBytes)
+		assert.Equal(t, "job-1", storedSession.ImportJobID)
+	})
+
+	t.Run("ClaimChunkOnce", func(t *testing.T) {
+		setupUploadSessionTestDB()
+
+		session := entity.NewUploadSession("base_teste.txt", "text/plain", "/tmp/upload-1", "txt", "", "", false, "", 100)
+		repo.Create(session)
+
+		claimed, err := repo.ClaimChunk(session, "first")
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+
+		claimed, err = repo.ClaimChunk(session, "second")
+		assert.Nil(t, err)
+		assert.False(t, claimed)
+
+		claimed, err = repo.ClaimFinalize(session)
+		assert.Nil(t, err)
+		assert.False(t, claimed)
+
+		session.Receive(40)
+		committed, err := repo.CommitChunk(session, "second", 0)
+		assert.Nil(t, err)
+		assert.False(t, committed)
+
+		committed, err = repo.CommitChunk(session, "first", 0)
+		assert.Nil(t, err)
+		assert.True(t, committed)
+
+		storedSession, err := repo.GetById(session.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(40), storedSession.ReceivedBytes)
+		assert.Empty(t, storedSession.ChunkToken)
+
+		claimed, err = repo.ClaimChunk(storedSession, "second")
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+	})
+
+	t.Run("ClaimChunkTakesOverStaleClaim", func(t *testing.T) {
+		setupUploadSessionTestDB()
+
+		session := entity.NewUploadSession("base_teste.txt", "text/plain", "/tmp/upload-1", "txt", "", "", false, "", 100)
+		repo.Create(session)
+
+		claimed, err := repo.ClaimChunk(session, "first")
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+		db.Model(&entity.UploadSession{}).Where("id = ?", session.ID).Update("chunk_claimed_at", time.Now().Add(-2*entity.ChunkLease))
+
+		claimed, err = repo.ClaimChunk(session, "second")
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+
+		committed, err := repo.CommitChunk(session, "first", 0)
+		assert.Nil(t, err)
+		assert.False(t, committed)
+	})
+
+	t.Run("ClaimFinalizeOnce", func(t *testing.T) {
+		setupUploadSessionTestDB()
+
+		session := entity.NewUploadSession("base_teste.txt", "text/plain", "/tmp/upload-1", "txt", "", "", false, "", 100)
+		session.Receive(100)
+		repo.Create(session)
+
+		claimed, err := repo.ClaimFinalize(session)
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+
+		claimed, err = repo.ClaimFinalize(session)
+		assert.Nil(t, err)
+		assert.False(t, claimed)
+
+		storedSession, err := repo.GetById(session.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.UploadSessionFinalized, storedSession.Status)
+	})
+
+	t.Run("DeleteStale", func(t *testing.T) {
+		setupUploadSessionTestDB()
+
+		stale := entity.NewUploadSession("base_teste.txt", "text/plain", "/tmp/upload-1", "txt", "", "", false, "", 100)
+		stale.UpdatedAt = time.Now().Add(-48 * time.Hour)
+		repo.Create(stale)
+
+		finalized := entity.NewUploadSession("base_teste.txt", "text/plain", "/tmp/upload-2", "txt", "", "", false, "", 0)
+		finalized.Finalize("job-1")
+		finalized.UpdatedAt = time.Now().Add(-48 * time.Hour)
+		repo.Create(finalized)
+
+		fresh := entity.NewUploadSession("base_teste.txt", "text/plain", "/tmp/upload-3", "txt", "", "", false, "", 100)
+		repo.Create(fresh)
+
+		sessions, err := repo.DeleteStale(time.Now().Add(-24 * time.Hour))
+		assert.Nil(t, err)
+		assert.Len(t, sessions, 1)
+		assert.Equal(t, stale.ID, sessions[0].ID)
+		assert.Equal(t, "/tmp/upload-1", sessions[0].FilePath)
+
+		_, err = repo.GetById(stale.ID)
+		assert.NotNil(t, err)
+		_, err = repo.GetById(finalized.ID)
+		assert.Nil(t, err)
+		_, err = repo.GetById(fresh.ID)
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,50 @@
+package databaseRepository
+
+import (
+	"neoway_test/internal/domain/watchedfile/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type WatchedFileRepositoryMock struct {
+	mock.Mock
+}
+
+func (r *WatchedFileRepositoryMock) Create(file *entity.WatchedFile) error {
+	args := r.Called(file)
+	return args.Error(0)
+}
+
+func (r *WatchedFileRepositoryMock) Update(file *entity.WatchedFile) error {
+	args := r.Called(file)
+	return args.Error(0)
+}
+
+func (r *WatchedFileRepositoryMock) GetByHash(fileHash string) (*entity.WatchedFile, error) {
+	args := r.Called(fileHash)
+	if args.Error(1) != nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WatchedFile), nil
+}
+
+func (r *WatchedFileRepositoryMock) Retry(file *entity.WatchedFile) (bool, error) {
+	args := r.Called(file)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *WatchedFileRepositoryMock) Heartbeat(id string, attempt int) error {
+	args := r.Called(id, attempt)
+	return args.Error(0)
+}
+
+func (r *WatchedFileRepositoryMock) Finish(file *entity.WatchedFile) (bool, error) {
+	args := r.Called(file)
+	return args.Bool(0), args.Error(1)
+}
+
+func (r *WatchedFileRepositoryMock) FailStale(staleBefore time.Time, reason string) error {
+	args := r.Called(staleBefore, reason)
+	return args.Error(0)
+}
@@ -0,0 +1,68 @@
+package databaseRepository
+
+import (
+	"neoway_test/internal/domain/watchedfile/entity"
+	"neoway_test/internal/domain/watchedfile/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WatchedFileRepositoryPostgres struct {
+	Db *gorm.DB
+}
+
+func NewPostgresWatchedFileRepository(db *gorm.DB) (repository.WatchedFileRepository, error) {
+	return &WatchedFileRepositoryPostgres{Db: db}, nil
+}
+
+func (r *WatchedFileRepositoryPostgres) Create(file *entity.WatchedFile) error {
+	tx := r.Db.Create(file)
+	return tx.Error
+}
+
+func (r *WatchedFileRepositoryPostgres) Update(file *entity.WatchedFile) error {
+	tx := r.Db.Save(file)
+	return tx.Error
+}
+
+func (r *WatchedFileRepositoryPostgres) GetByHash(fileHash string) (*entity.WatchedFile, error) {
+	var file entity.WatchedFile
+	tx := r.Db.First(&file, "file_hash = ?", fileHash)
+	return &file, tx.Error
+}
+
+func (r *WatchedFileRepositoryPostgres) Retry(file *entity.WatchedFile) (bool, error) {
+	tx := r.Db.Model(file).
+		Where("status = ? AND attempt = ?", entity.WatchedFileFailed, file.Attempt-1).
+		Select("file_name", "status", "error", "report", "finished_at", "heartbeat_at", "attempt").
+		Updates(file)
+	return tx.RowsAffected > 0, tx.Error
+}
+
+func (r *WatchedFileRepositoryPostgres) Heartbeat(id string, attempt int) error {
+	tx := r.Db.Model(&entity.WatchedFile{}).
+		Where("id = ? AND status = ? AND attempt = ?", id, entity.WatchedFileProcessing, attempt).
+		Update("heartbeat_at", time.Now())
+	return tx.Error
+}
+
+func (r *WatchedFileRepositoryPostgres) Finish(file *entity.WatchedFile) (bool, error) {
+	tx := r.Db.Model(file).
+		Where("status = ? AND attempt = ?", entity.WatchedFileProcessing, file.Attempt).
+		Select("status", "error", "report", "finished_at").
+		Updates(file)
+	return tx.RowsAffected > 0, tx.Error
+}
+
+func (r *WatchedFileRepositoryPostgres) FailStale(staleBefore time.Time, reason string) error {
+	// Files picked up before heartbeats were recorded fall back to their creation.
+	tx := r.Db.Model(&entity.WatchedFile{}).
+		Where("status = ? AND coalesce(heartbeat_at, created_at) < ?", entity.WatchedFileProcessing, staleBefore).
+		Updates(map[string]interface{}{
+			"status":      entity.WatchedFileFailed,
+			"error":       reason,
+			"finished_at": time.Now(),
+		})
+	return tx.Error
+}
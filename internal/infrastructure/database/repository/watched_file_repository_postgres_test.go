@@ -0,0 +1,139 @@
+package databaseRepository_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	customerDto "neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/watchedfile/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+)
+
+func setupWatchedFileTestDB() {
+	db.Exec("DROP TABLE IF EXISTS watched_files")
+	db.AutoMigrate(&entity.WatchedFile{})
+}
+
+func TestPostgresWatchedFileRepository(t *testing.T) {
+	repo, _ := databaseRepository.NewPostgresWatchedFileRepository(db)
+
+	t.Run("CreateAndGetByHash", func(t *testing.T) {
+		setupWatchedFileTestDB()
+
+		file := entity.NewWatchedFile("base_teste.txt", "abc123")
+		err := repo.Create(file)
+		assert.Nil(t, err)
+
+		file.Succeed(customerDto.OutputCreateCustomerBulkDto{Accepted: 1})
+		err = repo.Update(file)
+		assert.Nil(t, err)
+
+		storedFile, err := repo.GetByHash("abc123")
+		assert.Nil(t, err)
+		assert.Equal(t, file.ID, storedFile.ID)
+		assert.Equal(t, entity.WatchedFileImported, storedFile.Status)
+		assert.Equal(t, file.Report, storedFile.Report)
+
+		_, err = repo.GetByHash("def456")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+
+	t.Run("HashIsUnique", func(t *testing.T) {
+		setupWatchedFileTestDB()
+
+		err := repo.Create(entity.NewWatchedFile("base_teste.txt", "abc123"))
+		assert.Nil(t, err)
+
+		err = repo.Create(entity.NewWatchedFile("base_copia.txt", "abc123"))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("FailStale", func(t *testing.T) {
+		setupWatchedFileTestDB()
+
+		stale := entity.NewWatchedFile("base_teste.txt", "abc123")
+		old := time.Now().Add(-time.Hour)
+		stale.HeartbeatAt = &old
+		repo.Create(stale)
+
+		live := entity.NewWatchedFile("base_copia.txt", "def456")
+		repo.Create(live)
+		assert.Nil(t, repo.Heartbeat(live.ID, live.Attempt))
+
+		err := repo.FailStale(time.Now().Add(-time.Minute), "import interrupted")
+		assert.Nil(t, err)
+
+		storedFile, err := repo.GetByHash("abc123")
+		assert.Nil(t, err)
+		assert.Equal(t, entity.WatchedFileFailed, storedFile.Status)
+		assert.Equal(t, "import interrupted", storedFile.Error)
+
+		storedFile, err = repo.GetByHash("def456")
+		assert.Nil(t, err)
+		assert.Equal(t, entity.WatchedFileProcessing, storedFile.Status)
+	})
+
+	t.Run("RetryClaimsOnce", func(t *testing.T) {
+		setupWatchedFileTestDB()
+
+		file := entity.NewWatchedFile("base_teste.txt", "abc123")
+		file.Fail(errors.New("internal server error"))
+		repo.Create(file)
+
+		first, _ := repo.GetByHash("abc123")
+		second, _ := repo.GetByHash("abc123")
+
+		first.Retry("base_retry.txt")
+		claimed, err := repo.Retry(first)
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+
+		second.Retry("base_retry.txt")
+		claimed, err = repo.Retry(second)
+		assert.Nil(t, err)
+		assert.False(t, claimed)
+
+		storedFile, err := repo.GetByHash("abc123")
+		assert.Nil(t, err)
+		assert.Equal(t, entity.WatchedFileProcessing, storedFile.Status)
+		assert.Equal(t, "base_retry.txt", storedFile.FileName)
+		assert.Empty(t, storedFile.Error)
+		assert.Nil(t, storedFile.FinishedAt)
+		assert.Equal(t, 2, storedFile.Attempt)
+	})
+
+	t.Run("FinishOnlyTheCurrentAttempt", func(t *testing.T) {
+		setupWatchedFileTestDB()
+
+		abandoned := entity.NewWatchedFile("base_teste.txt", "abc123")
+		old := time.Now().Add(-time.Hour)
+		abandoned.HeartbeatAt = &old
+		repo.Create(abandoned)
+		assert.Nil(t, repo.FailStale(time.Now().Add(-time.Minute), "import interrupted"))
+
+		retry, _ := repo.GetByHash("abc123")
+		retry.Retry("base_teste.txt")
+		claimed, err := repo.Retry(retry)
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+
+		abandoned.Succeed(customerDto.OutputCreateCustomerBulkDto{Accepted: 1})
+		finished, err := repo.Finish(abandoned)
+		assert.Nil(t, err)
+		assert.False(t, finished)
+
+		retry.Succeed(customerDto.OutputCreateCustomerBulkDto{Accepted: 2})
+		finished, err = repo.Finish(retry)
+		assert.Nil(t, err)
+		assert.True(t, finished)
+
+		storedFile, err := repo.GetByHash("abc123")
+		assert.Nil(t, err)
+		assert.Equal(t, entity.WatchedFileImported, storedFile.Status)
+		assert.Equal(t, 2, storedFile.Report.Accepted)
+	})
+}
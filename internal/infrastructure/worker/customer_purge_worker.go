@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"log"
+	usecasePurge "neoway_test/internal/usecase/customer/purge"
+	"time"
+)
+
+// CustomerPurgeWorker periodically removes for good the customers deleted
+// longer ago than the retention period.
+type CustomerPurgeWorker struct {
+	purgeDeletedCustomersUsecase *usecasePurge.PurgeDeletedCustomersUseCase
+	interval                     time.Duration
+}
+
+func NewCustomerPurgeWorker(purgeDeletedCustomersUsecase *usecasePurge.PurgeDeletedCustomersUseCase, interval time.Duration) *CustomerPurgeWorker {
+	return &CustomerPurgeWorker{
+		purgeDeletedCustomersUsecase: purgeDeletedCustomersUsecase,
+		interval:                     interval,
+	}
+}
+
+// Start purges right away and then once every interval until ctx is done.
+func (w *CustomerPurgeWorker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.purge()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (w *CustomerPurgeWorker) purge() {
+	purged, err := w.purgeDeletedCustomersUsecase.Execute()
+	if err != nil {
+		log.Printf("customer purge: %v", err)
+		return
+	}
+	if purged > 0 {
+		log.Printf("customer purge: removed %d deleted customers", purged)
+	}
+}
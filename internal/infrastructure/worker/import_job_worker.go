@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	usecaseRun "neoway_test/internal/usecase/importjob/run"
+	"time"
+)
+
+// ImportJobWorker processes queued import jobs in the background. Jobs live in
+// the database, so Enqueue only wakes the worker up; the poll interval picks up
+// anything that was missed, e.g. jobs queued before a restart.
+type ImportJobWorker struct {
+	runImportJobUsecase *usecaseRun.RunImportJobUseCase
+	pollInterval        time.Duration
+	wake                chan struct{}
+}
+
+func NewImportJobWorker(runImportJobUsecase *usecaseRun.RunImportJobUseCase, pollInterval time.Duration) *ImportJobWorker {
+	return &ImportJobWorker{
+		runImportJobUsecase: runImportJobUsecase,
+		pollInterval:        pollInterval,
+		wake:                make(chan struct{}, 1),
+	}
+}
+
+func (w *ImportJobWorker) Enqueue(jobID string) {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+		// A wake-up is already pending and will pick this job up too.
+	}
+}
+
+// Start processes the queue until ctx is done, failing on every poll the jobs
+// abandoned by a process that stopped.
+func (w *ImportJobWorker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := w.runImportJobUsecase.Recover(); err != nil {
+				log.Printf("import worker: failed to recover interrupted jobs: %v", err)
+			}
+			w.drain(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.wake:
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (w *ImportJobWorker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := w.runImportJobUsecase.RunNext()
+		if errors.Is(err, usecaseRun.ErrNoQueuedJob) {
+			return
+		}
+		if err != nil {
+			log.Printf("import worker: %v", err)
+			return
+		}
+		log.Printf("import worker: job %s finished with status %s", job.ID, job.Status)
+	}
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"log"
+	usecaseExpire "neoway_test/internal/usecase/uploadsession/expire"
+	"time"
+)
+
+// UploadSessionExpiryWorker periodically removes the upload sessions that were
+// abandoned before being finalized, with their files.
+type UploadSessionExpiryWorker struct {
+	expireUploadSessionsUsecase *usecaseExpire.ExpireUploadSessionsUseCase
+	interval                    time.Duration
+}
+
+func NewUploadSessionExpiryWorker(expireUploadSessionsUsecase *usecaseExpire.ExpireUploadSessionsUseCase, interval time.Duration) *UploadSessionExpiryWorker {
+	return &UploadSessionExpiryWorker{
+		expireUploadSessionsUsecase: expireUploadSessionsUsecase,
+		interval:                    interval,
+	}
+}
+
+// Start expires sessions right away and then once every interval until ctx is
+// done.
+func (w *UploadSessionExpiryWorker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.expire()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (w *UploadSessionExpiryWorker) expire() {
+	expired, err := w.expireUploadSessionsUsecase.Execute()
+	if expired > 0 {
+		log.Printf("upload session expiry: removed %d abandoned sessions", expired)
+	}
+	if err != nil {
+		log.Printf("upload session expiry: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"log"
+	usecaseIngest "neoway_test/internal/usecase/watchfolder/ingest"
+	"time"
+)
+
+// WatchFolderWorker polls the watch folder and imports the files dropped in it.
+type WatchFolderWorker struct {
+	ingestWatchFolderUsecase *usecaseIngest.IngestWatchFolderUseCase
+	pollInterval             time.Duration
+}
+
+func NewWatchFolderWorker(ingestWatchFolderUsecase *usecaseIngest.IngestWatchFolderUseCase, pollInterval time.Duration) *WatchFolderWorker {
+	return &WatchFolderWorker{
+		ingestWatchFolderUsecase: ingestWatchFolderUsecase,
+		pollInterval:             pollInterval,
+	}
+}
+
+// Start prepares the watch folder and then polls it until ctx is done.
+func (w *WatchFolderWorker) Start(ctx context.Context) error {
+	if err := w.ingestWatchFolderUsecase.Prepare(); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(w.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			w.poll()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (w *WatchFolderWorker) poll() {
+	reports, err := w.ingestWatchFolderUsecase.Execute()
+	for _, report := range reports {
+		log.Printf("watch folder: %s finished with status %s (batch %s)", report.FileName, report.Status, report.BatchID)
+	}
+	if err != nil {
+		log.Printf("watch folder: %v", err)
+	}
+}
@@ -8,6 +8,9 @@ import (
 
 var ErrInternal error = errors.New("internal server error")
 
+// ErrConflict is wrapped by errors caused by the current state of a resource.
+var ErrConflict error = errors.New("conflict")
+
 func ProcessErrorToReturn(err error) error {
 	if !errors.Is(err, gorm.ErrRecordNotFound) {
 		return ErrInternal
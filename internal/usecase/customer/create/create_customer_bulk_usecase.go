@@ -2,54 +2,304 @@ package usecase
 
 import (
 	"io"
+	"neoway_test/internal/domain/customer/dto"
 	"neoway_test/internal/domain/customer/entity"
 	"neoway_test/internal/domain/customer/repository"
 	"neoway_test/internal/domain/customer/service"
 	internalerrors "neoway_test/internal/internal-errors"
+	"os"
+	"path"
+
+	"github.com/rs/xid"
 )
 
+// DefaultBulkBatchSize is how many customers are buffered before each insert.
+const DefaultBulkBatchSize = 1000
+
+// DefaultReportLimit is how many entries each list of the import report holds,
+// so a file of broken lines cannot make the report as large as the file.
+const DefaultReportLimit = 1000
+
 type CreateCustomerBulkUseCase struct {
-	repo                repository.CustomerRepository
-	parseTxtFileService *service.ParseTxtFileService
+	repo        repository.CustomerRepository
+	fileFormats *service.FileFormatRegistry
+	batchSize   int
+	reportLimit int
 }
 
-func NewCreateCustomersBulkUseCase(repo repository.CustomerRepository, parseService *service.ParseTxtFileService) *CreateCustomerBulkUseCase {
+func NewCreateCustomersBulkUseCase(repo repository.CustomerRepository, fileFormats *service.FileFormatRegistry) *CreateCustomerBulkUseCase {
 	return &CreateCustomerBulkUseCase{
-		repo:                repo,
-		parseTxtFileService: parseService,
+		repo:        repo,
+		fileFormats: fileFormats,
+		batchSize:   DefaultBulkBatchSize,
+		reportLimit: DefaultReportLimit,
 	}
 }
 
-func (uc *CreateCustomerBulkUseCase) Execute(file io.Reader) (string, error) {
-	customersDTO, err := uc.parseTxtFileService.ExecuteParseTxtFileService(file)
+// WithBatchSize changes how many customers are sent to the repository at once,
+// which also bounds how many are held in memory.
+func (uc *CreateCustomerBulkUseCase) WithBatchSize(size int) *CreateCustomerBulkUseCase {
+	if size > 0 {
+		uc.batchSize = size
+	}
+	return uc
+}
+
+// WithReportLimit changes how many entries each list of the report holds; the
+// counts still cover every line. A limit of zero or less lists them all.
+func (uc *CreateCustomerBulkUseCase) WithReportLimit(limit int) *CreateCustomerBulkUseCase {
+	uc.reportLimit = limit
+	return uc
+}
+
+// listed tells whether a report list already holding count entries takes
+// another one.
+func (uc *CreateCustomerBulkUseCase) listed(count int) bool {
+	return uc.reportLimit <= 0 || count < uc.reportLimit
+}
+
+// Execute upserts the customers of the file in batches by CPF and reports the
+// lines it accepted, rejected or resolved, without aborting on a bad line.
+func (uc *CreateCustomerBulkUseCase) Execute(input dto.InputCreateCustomerBulkDto) (dto.OutputCreateCustomerBulkDto, error) {
+	if err := service.ValidateDuplicatePolicy(input.DuplicatePolicy); err != nil {
+		return dto.OutputCreateCustomerBulkDto{}, err
+	}
+	if input.BatchID == "" {
+		input.BatchID = xid.New().String()
+	}
+
+	output := dto.OutputCreateCustomerBulkDto{
+		BatchID:         input.BatchID,
+		DryRun:          input.DryRun,
+		Strict:          input.Strict,
+		DuplicatePolicy: input.DuplicatePolicy,
+		Files:           []dto.OutputImportedFileDto{},
+		RejectedLines:   []dto.RejectedCustomerLineDto{},
+		Warnings:        []dto.CustomerFieldWarningDto{},
+		Collisions:      []dto.DuplicateCollisionDto{},
+	}
 
+	err := service.ExpandUpload(input.File, input.FileName, func(member service.ArchiveMember) error {
+		return uc.importMember(input, member, &output)
+	})
 	if err != nil {
-		return "", err
-	}
-
-	var customers []*entity.Customer
-	for _, newCustomer := range customersDTO {
-		customer, err := entity.NewCustomer(
-			newCustomer.Cpf,
-			newCustomer.Private,
-			newCustomer.Incompleto,
-			newCustomer.DataUltimaCompra,
-			newCustomer.TicketMedio,
-			newCustomer.TicketUltimaCompra,
-			newCustomer.LojaMaisFrequente,
-			newCustomer.LojaUltimaCompra,
-		)
+		return dto.OutputCreateCustomerBulkDto{}, err
+	}
+
+	output.Summary.Rows = output.Accepted + output.Rejected + output.Duplicates
+
+	switch {
+	case input.DryRun && output.Rejected > 0:
+		output.Message = "Validation Completed With Rejected Lines"
+	case input.DryRun:
+		output.Message = "Validation Successful"
+	case output.Rejected > 0:
+		output.Message = "Bulk Insert Completed With Rejected Lines"
+	default:
+		output.Message = "Bulk Insert Successful"
+	}
+
+	return output, nil
+}
+
+func (uc *CreateCustomerBulkUseCase) importMember(input dto.InputCreateCustomerBulkDto, member service.ArchiveMember, output *dto.OutputCreateCustomerBulkDto) error {
+	file := dto.OutputImportedFileDto{Name: member.Name}
+	batch := make([]*entity.Customer, 0, uc.batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if !input.DryRun {
+			repo := uc.repo.WithAudit(entity.AuditInfo{Actor: input.Actor, RequestID: input.RequestID})
+			inserted, updated, superseded, err := repo.UpsertBulk(batch)
+			if err != nil {
+				return internalerrors.ErrInternal
+			}
+			output.Inserted += inserted
+			output.Updated += updated
+			output.Superseded += superseded
+		}
+		file.Accepted += len(batch)
+		output.Accepted += len(batch)
+		batch = make([]*entity.Customer, 0, uc.batchSize)
+		if input.OnProgress != nil {
+			input.OnProgress(output.Accepted+output.Rejected, output.Rejected)
+		}
+		return nil
+	}
+
+	reject := func(line dto.ParsedCustomerLineDto, reason error) {
+		file.Rejected++
+		output.Rejected++
+		rejected := dto.RejectedCustomerLineDto{
+			LineNumber: line.LineNumber,
+			Content:    line.Raw,
+			Reason:     reason.Error(),
+		}
+		if member.Archived {
+			rejected.File = member.Name
+		}
+		if uc.listed(len(output.RejectedLines)) {
+			output.RejectedLines = append(output.RejectedLines, rejected)
+		}
+	}
+
+	options := service.ParseOptions{
+		Format:   input.Format,
+		FileName: member.Name,
+		Layout:   input.Layout,
+		Encoding: input.Encoding,
+		Strict:   input.Strict,
+	}
+	// The upload's content type describes the archive, not its members.
+	sourceFileName := member.Name
+	if member.Archived {
+		sourceFileName = path.Join(input.FileName, member.Name)
+	} else {
+		options.ContentType = input.ContentType
+	}
+
+	resolver := service.NewDuplicateResolver(input.DuplicatePolicy)
+	content := member.Content
+	if resolver.NeedsPlan() {
+		spooled, err := spool(member.Content)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(spooled.Name())
+		defer spooled.Close()
+
+		err = uc.fileFormats.StreamParse(spooled, options, func(line dto.ParsedCustomerLineDto) error {
+			if customer, err := newCustomerFromLine(line); err == nil {
+				return resolver.Observe(line.LineNumber, customer)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := spooled.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		content = spooled
+	}
+
+	err := uc.fileFormats.StreamParse(content, options, func(line dto.ParsedCustomerLineDto) error {
+		customer, err := newCustomerFromLine(line)
+		if err != nil {
+			reject(line, err)
+			return nil
+		}
+
+		keep, collision, err := resolver.Resolve(line.LineNumber, customer)
 		if err != nil {
-			return "", err
+			return err
+		}
+		if collision != nil {
+			output.CollisionCount++
+			if member.Archived {
+				collision.File = member.Name
+			}
+			if uc.listed(len(output.Collisions)) {
+				output.Collisions = append(output.Collisions, *collision)
+			}
+		}
+		if !keep {
+			if collision.Resolution == service.ResolutionRejected {
+				reject(line, service.ErrDuplicateCpf)
+			} else {
+				output.Duplicates++
+			}
+			return nil
 		}
-		customers = append(customers, customer)
+
+		customer.TraceTo(input.BatchID, sourceFileName, input.FileHash, line.LineNumber)
+
+		for _, warning := range line.Warnings {
+			output.WarningCount++
+			if !uc.listed(len(output.Warnings)) {
+				break
+			}
+			if member.Archived {
+				warning.File = member.Name
+			}
+			output.Warnings = append(output.Warnings, warning)
+		}
+
+		summarize(&output.Summary, customer)
+		batch = append(batch, customer)
+		if len(batch) >= uc.batchSize {
+			return flush()
+		}
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// Salva o restante no repositório
+	if err := flush(); err != nil {
+		return err
 	}
 
-	// 3. Salva no repositório
-	err = uc.repo.CreateBulk(customers)
+	output.Files = append(output.Files, file)
+	return nil
+}
+
+// newCustomerFromLine builds the customer of a parsed line, or returns why the
+// line cannot be imported.
+func newCustomerFromLine(line dto.ParsedCustomerLineDto) (*entity.Customer, error) {
+	if line.Err != nil {
+		return nil, line.Err
+	}
+
+	return entity.NewCustomer(
+		line.Customer.Cpf,
+		line.Customer.Private,
+		line.Customer.Incompleto,
+		line.Customer.DataUltimaCompra,
+		line.Customer.TicketMedio,
+		line.Customer.TicketUltimaCompra,
+		line.Customer.LojaMaisFrequente,
+		line.Customer.LojaUltimaCompra,
+	)
+}
+
+// spool copies content to a temporary file so it can be read twice. The
+// caller closes and removes the file.
+func spool(content io.Reader) (*os.File, error) {
+	file, err := os.CreateTemp("", "customer-import-*")
 	if err != nil {
-		return "", internalerrors.ErrInternal
+		return nil, internalerrors.ErrInternal
 	}
+	_, err = io.Copy(file, content)
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return file, nil
+}
 
-	return "Bulk Insert Successful", nil
+func summarize(summary *dto.OutputBulkSummaryDto, customer *entity.Customer) {
+	if !customer.CpfValido {
+		summary.InvalidCpfs++
+	}
+	if customer.LojaMaisFrequente != "NULL" && !customer.CnpjLojaMaisFrequenteValido {
+		summary.InvalidCnpjs++
+	}
+	if customer.LojaUltimaCompra != "NULL" && !customer.CnpjLojaUltimaCompraValido {
+		summary.InvalidCnpjs++
+	}
+	if customer.DataUltimaCompra == nil {
+		summary.NullDates++
+	}
+	if customer.TicketMedio == 0 || customer.TicketUltimaCompra == 0 {
+		summary.ZeroTickets++
+	}
 }
@@ -1,10 +1,14 @@
 package usecase
 
 import (
+	"archive/zip"
 	"bytes"
 	"errors"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
 	"neoway_test/internal/domain/customer/service"
 	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,15 +22,22 @@ func TestCreateCustomerBulkUseCase_Success(t *testing.T) {
 	reader := bytes.NewReader([]byte(fileContent))
 
 	mockRepo := new(databaseRepository.CustomerRepositoryMock)
-	parseService := service.NewParseTxtFileService()
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
 	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService)
 
-	mockRepo.On("CreateBulk", mock.AnythingOfType("[]*entity.Customer")).Return(nil)
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Return(1, 1, 0, nil)
 
-	result, err := createCustomerBulkUseCase.Execute(reader)
+	result, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{File: reader})
 
 	assert.Nil(t, err)
-	assert.Equal(t, "Bulk Insert Successful", result)
+	assert.Equal(t, "Bulk Insert Successful", result.Message)
+	assert.Equal(t, 2, result.Accepted)
+	assert.Equal(t, 1, result.Inserted)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 0, result.Rejected)
+	assert.Empty(t, result.RejectedLines)
+	assert.NotEmpty(t, result.BatchID)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -36,14 +47,76 @@ func TestCreateCustomerBulkUseCase_ParsingError(t *testing.T) {
 	reader := bytes.NewReader([]byte(invalidFileContent))
 
 	mockRepo := new(databaseRepository.CustomerRepositoryMock)
-	parseService := service.NewParseTxtFileService()
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
 	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService)
 
-	result, err := createCustomerBulkUseCase.Execute(reader)
+	result, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{File: reader})
 
-	assert.Empty(t, result)
-	assert.EqualError(t, err, "invalid file format: line too short")
-	mockRepo.AssertNotCalled(t, "CreateBulk")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.Accepted)
+	assert.Equal(t, 1, result.Rejected)
+	assert.Equal(t, []dto.RejectedCustomerLineDto{{
+		LineNumber: 2,
+		Content:    "922.488.109-20   0              0              2011-01-27",
+		Reason:     "invalid file format: line too short",
+	}}, result.RejectedLines)
+	mockRepo.AssertNotCalled(t, "UpsertBulk")
+}
+
+func TestCreateCustomerBulkUseCase_KeepsGoingAfterRejectedLine(t *testing.T) {
+	fileContent := `CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA
+026.987.379-13     0           0           2011-01-20            159,31                159,31                  79.379.491/0001-83  79.379.491/0001-83
+041.091.641-25     0           1
+058.189.421-98     0           0           2011-01-22            89,00                 89,00                   79.379.491/0001-83  79.379.491/0001-83`
+	reader := bytes.NewReader([]byte(fileContent))
+
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService)
+
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("UpsertBulk", mock.MatchedBy(func(customers []*entity.Customer) bool { return len(customers) == 2 })).Return(0, 0, 0, nil)
+
+	result, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{File: reader})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Bulk Insert Completed With Rejected Lines", result.Message)
+	assert.Equal(t, 2, result.Accepted)
+	assert.Equal(t, 1, result.Rejected)
+	assert.Equal(t, 3, result.RejectedLines[0].LineNumber)
+	assert.Equal(t, "041.091.641-25     0           1", result.RejectedLines[0].Content)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateCustomerBulkUseCase_LimitsRejectedLines(t *testing.T) {
+	fileContent := "cpf,ticket_medio\n" + strings.Repeat("026.987.379-13,abc\n", 5)
+
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService).WithReportLimit(2)
+
+	result, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{File: strings.NewReader(fileContent), FileName: "base.csv", Strict: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 5, result.Rejected)
+	assert.Len(t, result.RejectedLines, 2)
+	assert.Equal(t, 2, result.RejectedLines[0].LineNumber)
+	assert.Equal(t, 3, result.RejectedLines[1].LineNumber)
+}
+
+func TestCreateCustomerBulkUseCase_LimitsWarnings(t *testing.T) {
+	fileContent := "cpf,ticket_medio,ticket_ultima_compra\n" + strings.Repeat("026.987.379-13,abc,abc\n", 2)
+
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService).WithReportLimit(3)
+
+	result, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{File: strings.NewReader(fileContent), FileName: "base.csv", DryRun: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, result.WarningCount)
+	assert.Len(t, result.Warnings, 3)
+	assert.Equal(t, 3, result.Warnings[2].LineNumber)
 }
 
 func TestCreateCustomerBulkUseCase_RepositoryError(t *testing.T) {
@@ -52,14 +125,287 @@ func TestCreateCustomerBulkUseCase_RepositoryError(t *testing.T) {
 	reader := bytes.NewReader([]byte(fileContent))
 
 	mockRepo := new(databaseRepository.CustomerRepositoryMock)
-	parseService := service.NewParseTxtFileService()
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
 	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService)
 
-	mockRepo.On("CreateBulk", mock.AnythingOfType("[]*entity.Customer")).Return(errors.New("database error"))
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Return(0, 0, 0, errors.New("database error"))
 
-	result, err := createCustomerBulkUseCase.Execute(reader)
+	result, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{File: reader})
 
 	assert.Empty(t, result)
 	assert.EqualError(t, err, "internal server error")
 	mockRepo.AssertExpectations(t)
 }
+
+func TestCreateCustomerBulkUseCase_FlushesInBatches(t *testing.T) {
+	fileContent := `CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA
+026.987.379-13     0           0           2011-01-20            159,31                159,31                  79.379.491/0001-83  79.379.491/0001-83
+041.091.641-25     0           1           NULL                  NULL                  NULL                    NULL                NULL
+058.189.421-98     0           0           2011-01-22            89,00                 89,00                   79.379.491/0001-83  79.379.491/0001-83`
+	reader := bytes.NewReader([]byte(fileContent))
+
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService).WithBatchSize(2)
+
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("UpsertBulk", mock.MatchedBy(func(customers []*entity.Customer) bool { return len(customers) == 2 })).Return(0, 0, 0, nil).Once()
+	mockRepo.On("UpsertBulk", mock.MatchedBy(func(customers []*entity.Customer) bool { return len(customers) == 1 })).Return(0, 0, 0, nil).Once()
+
+	result, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{File: reader})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, result.Accepted)
+	mockRepo.AssertNumberOfCalls(t, "UpsertBulk", 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateCustomerBulkUseCase_ReportsProgress(t *testing.T) {
+	fileContent := `CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA
+026.987.379-13     0           0           2011-01-20            159,31                159,31                  79.379.491/0001-83  79.379.491/0001-83
+041.091.641-25     0           1
+058.189.421-98     0           0           2011-01-22            89,00                 89,00                   79.379.491/0001-83  79.379.491/0001-83`
+	reader := bytes.NewReader([]byte(fileContent))
+
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService).WithBatchSize(1)
+
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Return(0, 0, 0, nil)
+
+	var progress [][2]int
+	_, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{
+		File: reader,
+		OnProgress: func(processed int, rejected int) {
+			progress = append(progress, [2]int{processed, rejected})
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, [][2]int{{1, 0}, {3, 1}}, progress)
+}
+
+func TestCreateCustomerBulkUseCase_ReportsEachZipMember(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	members := []struct{ name, content string }{
+		{"loja_a.txt", `CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA
+026.987.379-13     0           0           2011-01-20            159,31                159,31                  79.379.491/0001-83  79.379.491/0001-83
+041.091.641-25     0           1`},
+		{"loja_b.csv", "CPF,PRIVATE,INCOMPLETO\n058.189.421-98,0,0\n"},
+		{"loja_c.ndjson", `{"cpf":"041.091.641-25","private":"0","incompleto":"1"}` + "\n"},
+	}
+	for _, member := range members {
+		entry, _ := writer.Create(member.name)
+		entry.Write([]byte(member.content))
+	}
+	writer.Close()
+
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService)
+
+	var imported []*entity.Customer
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Run(func(args mock.Arguments) {
+		imported = append(imported, args.Get(0).([]*entity.Customer)...)
+	}).Return(0, 0, 0, nil)
+
+	result, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{
+		File:        bytes.NewReader(buffer.Bytes()),
+		FileName:    "bases.zip",
+		ContentType: "application/zip",
+		BatchID:     "batch-1",
+		FileHash:    "abc123",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, result.Accepted)
+	assert.Equal(t, 1, result.Rejected)
+	assert.Equal(t, []dto.OutputImportedFileDto{
+		{Name: "loja_a.txt", Accepted: 1, Rejected: 1},
+		{Name: "loja_b.csv", Accepted: 1, Rejected: 0},
+		{Name: "loja_c.ndjson", Accepted: 1, Rejected: 0},
+	}, result.Files)
+	assert.Equal(t, "loja_a.txt", result.RejectedLines[0].File)
+	assert.Equal(t, 3, result.RejectedLines[0].LineNumber)
+	mockRepo.AssertNumberOfCalls(t, "UpsertBulk", 3)
+
+	assert.Equal(t, "batch-1", result.BatchID)
+	assert.Len(t, imported, 3)
+	assert.Equal(t, "bases.zip/loja_b.csv", imported[1].SourceFileName)
+	assert.Equal(t, 2, imported[1].SourceLineNumber)
+	for _, customer := range imported {
+		assert.Equal(t, "batch-1", customer.ImportBatchID)
+		assert.Equal(t, "abc123", customer.SourceFileHash)
+	}
+}
+
+func TestCreateCustomerBulkUseCase_DryRunSummarizesWithoutWriting(t *testing.T) {
+	fileContent := `CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA
+026.987.379-13     0           0           2011-01-20            159,31                159,31                  79.379.491/0001-83  79.379.491/0001-83
+041.091.641-25     0           1           NULL                  NULL                  NULL                    NULL                NULL
+111.111.111-12     0           0           2011-01-22            89,00                 0,00                    11.111.111/0001-11  79.379.491/0001-83
+058.189.421-98     0           0`
+	reader := bytes.NewReader([]byte(fileContent))
+
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService).WithBatchSize(1)
+
+	result, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{File: reader, DryRun: true})
+
+	assert.Nil(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, "Validation Completed With Rejected Lines", result.Message)
+	assert.Equal(t, 3, result.Accepted)
+	assert.Equal(t, 1, result.Rejected)
+	assert.Equal(t, 0, result.Inserted)
+	assert.Equal(t, dto.OutputBulkSummaryDto{
+		Rows:         4,
+		InvalidCpfs:  1,
+		InvalidCnpjs: 1,
+		NullDates:    1,
+		ZeroTickets:  2,
+	}, result.Summary)
+	mockRepo.AssertNotCalled(t, "UpsertBulk", mock.Anything)
+}
+
+func TestCreateCustomerBulkUseCase_StrictModeRejectsMalformedValues(t *testing.T) {
+	fileContent := `CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA
+026.987.379-13     0           0           2011-01-20            12,3x                 159,31                  79.379.491/0001-83  79.379.491/0001-83
+041.091.641-25     0           1           NULL                  NULL                  NULL                    NULL                NULL`
+
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService)
+
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Return(1, 0, 0, nil)
+
+	strict, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{File: bytes.NewReader([]byte(fileContent)), Strict: true})
+
+	assert.Nil(t, err)
+	assert.True(t, strict.Strict)
+	assert.Equal(t, 1, strict.Accepted)
+	assert.Equal(t, 1, strict.Rejected)
+	assert.Equal(t, `ticket_medio "12,3x": invalid amount`, strict.RejectedLines[0].Reason)
+	assert.Empty(t, strict.Warnings)
+
+	lenient, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{File: bytes.NewReader([]byte(fileContent))})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, lenient.Accepted)
+	assert.Equal(t, 0, lenient.Rejected)
+	assert.Equal(t, []dto.CustomerFieldWarningDto{{
+		LineNumber: 2,
+		Field:      "ticket_medio",
+		Value:      "12,3x",
+		Message:    "invalid amount, stored as 0",
+	}}, lenient.Warnings)
+}
+
+func TestCreateCustomerBulkUseCase_ResolvesDuplicateCpfs(t *testing.T) {
+	fileContent := "cpf,data_ultima_compra,ticket_ultima_compra\n" +
+		"026.987.379-13,2011-01-20,10\n" +
+		"041.091.641-25,NULL,NULL\n" +
+		"026.987.379-13,2012-05-01,20\n"
+
+	tests := []struct {
+		policy     string
+		accepted   int
+		rejected   int
+		duplicates int
+		ticket     float64
+	}{
+		{"keep_first", 2, 0, 1, 10},
+		{"keep_last", 2, 0, 1, 20},
+		{"reject", 1, 2, 0, 0},
+		{"merge", 2, 0, 1, 20},
+	}
+
+	for _, test := range tests {
+		t.Run(test.policy, func(t *testing.T) {
+			mockRepo := new(databaseRepository.CustomerRepositoryMock)
+			parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+			createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService)
+
+			var imported []*entity.Customer
+			mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+			mockRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Run(func(args mock.Arguments) {
+				imported = append(imported, args.Get(0).([]*entity.Customer)...)
+			}).Return(0, 0, 0, nil)
+
+			result, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{
+				File:            bytes.NewReader([]byte(fileContent)),
+				FileName:        "base.csv",
+				DuplicatePolicy: test.policy,
+			})
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.accepted, result.Accepted)
+			assert.Equal(t, test.rejected, result.Rejected)
+			assert.Equal(t, test.duplicates, result.Duplicates)
+			assert.Equal(t, 3, result.Summary.Rows)
+			assert.Len(t, result.Collisions, test.duplicates+test.rejected)
+			assert.Equal(t, test.duplicates+test.rejected, result.CollisionCount)
+			for _, customer := range imported {
+				if customer.Cpf == "026.987.379-13" {
+					assert.Equal(t, test.ticket, customer.TicketUltimaCompra)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateCustomerBulkUseCase_LimitsCollisions(t *testing.T) {
+	fileContent := "cpf,ticket_ultima_compra\n" + strings.Repeat("026.987.379-13,10\n", 4)
+
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService).WithReportLimit(2)
+
+	result, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{
+		File:            strings.NewReader(fileContent),
+		FileName:        "base.csv",
+		DuplicatePolicy: "keep_first",
+		DryRun:          true,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, result.CollisionCount)
+	assert.Len(t, result.Collisions, 2)
+}
+
+func TestCreateCustomerBulkUseCase_ReportsSuperseded(t *testing.T) {
+	fileContent := "cpf,ticket_ultima_compra\n026.987.379-13,10\n041.091.641-25,20\n"
+
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService)
+
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Return(1, 0, 1, nil)
+
+	result, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{File: strings.NewReader(fileContent), FileName: "base.csv"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.Inserted)
+	assert.Equal(t, 0, result.Updated)
+	assert.Equal(t, 1, result.Superseded)
+}
+
+func TestCreateCustomerBulkUseCase_UnknownDuplicatePolicy(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	parseService := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+	createCustomerBulkUseCase := NewCreateCustomersBulkUseCase(mockRepo, parseService)
+
+	_, err := createCustomerBulkUseCase.Execute(dto.InputCreateCustomerBulkDto{
+		File:            bytes.NewReader(nil),
+		DuplicatePolicy: "newest",
+	})
+
+	assert.True(t, errors.Is(err, service.ErrUnknownDuplicatePolicy))
+}
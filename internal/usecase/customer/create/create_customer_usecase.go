@@ -42,7 +42,7 @@ func (uc *CreateCustomerUseCase) Execute(input dto.InputCreateCustomerDto) (dto.
 		return dto.OutputCreateCustomerDto{}, err
 	}
 
-	err = uc.repo.Create(customer)
+	inserted, err := uc.repo.WithAudit(entity.AuditInfo{Actor: input.Actor, RequestID: input.RequestID}).Upsert(customer)
 
 	if err != nil {
 		return dto.OutputCreateCustomerDto{}, internalerrors.ErrInternal
@@ -62,6 +62,7 @@ func (uc *CreateCustomerUseCase) Execute(input dto.InputCreateCustomerDto) (dto.
 		LojaUltimaCompra:            customer.LojaUltimaCompra,
 		CnpjLojaUltimaCompraValido:  customer.CnpjLojaUltimaCompraValido,
 		CreatedAt:                   customer.CreatedAt,
+		Updated:                     !inserted,
 	}
 
 	return output, nil
@@ -23,20 +23,22 @@ func TestCreateCustomerUseCase_Success(t *testing.T) {
 
 	input := dto.InputCreateCustomerDto{
 		Cpf:                "152.298.818-10",
-		Private:            "0",
-		Incompleto:         "1",
+		Private:            false,
+		Incompleto:         true,
 		DataUltimaCompra:   "2011-10-04",
 		TicketMedio:        100.5,
 		TicketUltimaCompra: 200.75,
 		LojaMaisFrequente:  "79.379.491/0008-50",
 		LojaUltimaCompra:   "79.379.491/0008-50",
+		Actor:              "alice",
+		RequestID:          "host/abc-000001",
 	}
 
 	customer := &entity.Customer{
-		Cpf:                         input.Cpf,
+		Cpf:                         "15229881810",
 		CpfValido:                   true,
-		Private:                     input.Private,
-		Incompleto:                  input.Incompleto,
+		Private:                     bool(input.Private),
+		Incompleto:                  bool(input.Incompleto),
 		DataUltimaCompra:            &dataUltimaCompra,
 		TicketMedio:                 input.TicketMedio,
 		TicketUltimaCompra:          input.TicketUltimaCompra,
@@ -46,7 +48,8 @@ func TestCreateCustomerUseCase_Success(t *testing.T) {
 		CnpjLojaUltimaCompraValido:  true,
 	}
 
-	mockRepo.On("Create", mock.AnythingOfType("*entity.Customer")).Return(nil)
+	mockRepo.On("WithAudit", entity.AuditInfo{Actor: "alice", RequestID: "host/abc-000001"}).Return(mockRepo)
+	mockRepo.On("Upsert", mock.AnythingOfType("*entity.Customer")).Return(true, nil)
 
 	output, err := createCustomerUseCase.Execute(input)
 
@@ -63,6 +66,7 @@ func TestCreateCustomerUseCase_Success(t *testing.T) {
 	assert.Equal(t, customer.CpfValido, output.CpfValido)
 	assert.Equal(t, customer.CnpjLojaMaisFrequenteValido, output.CnpjLojaMaisFrequenteValido)
 	assert.Equal(t, customer.CnpjLojaUltimaCompraValido, output.CnpjLojaUltimaCompraValido)
+	assert.False(t, output.Updated)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -76,7 +80,8 @@ func TestCreateCustomerUseCase_ErrorCreatingCustomer(t *testing.T) {
 		Cpf: "152.298.818-10",
 	}
 
-	mockRepo.On("Create", mock.AnythingOfType("*entity.Customer")).Return(errors.New("database error"))
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("Upsert", mock.AnythingOfType("*entity.Customer")).Return(false, errors.New("database error"))
 
 	output, err := createCustomerUseCase.Execute(input)
 
@@ -85,6 +90,28 @@ func TestCreateCustomerUseCase_ErrorCreatingCustomer(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCreateCustomerUseCase_UpdatesExistingCpf(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	parseService := service.NewParseService()
+	createCustomerUseCase := NewCreateCustomerUseCase(mockRepo, parseService)
+
+	input := dto.InputCreateCustomerDto{
+		Cpf: "152.298.818-10",
+	}
+
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("Upsert", mock.AnythingOfType("*entity.Customer")).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.Customer).ID = "stored-id"
+	}).Return(false, nil)
+
+	output, err := createCustomerUseCase.Execute(input)
+
+	assert.Nil(t, err)
+	assert.True(t, output.Updated)
+	assert.Equal(t, "stored-id", output.ID)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestCreateCustomerUseCase_EmptyCustomerData(t *testing.T) {
 	mockRepo := new(databaseRepository.CustomerRepositoryMock)
 	parseService := service.NewParseService()
@@ -111,7 +138,8 @@ func TestCreateCustomerUseCase_EmptyCustomerData(t *testing.T) {
 		CnpjLojaUltimaCompraValido:  false,
 	}
 
-	mockRepo.On("Create", mock.AnythingOfType("*entity.Customer")).Return(nil)
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("Upsert", mock.AnythingOfType("*entity.Customer")).Return(true, nil)
 
 	output, err := createCustomerUseCase.Execute(input)
 
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
 	"neoway_test/internal/domain/customer/repository"
 	internalerrors "neoway_test/internal/internal-errors"
 )
@@ -21,7 +22,7 @@ func (uc *DeleteCustomerUseCase) Execute(input dto.InputDeleteCustomerDto) error
 		return err
 	}
 
-	err = uc.repo.Delete(customerFound)
+	err = uc.repo.WithAudit(entity.AuditInfo{Actor: input.Actor, RequestID: input.RequestID}).Delete(customerFound)
 	if err != nil {
 		return internalerrors.ErrInternal
 	}
@@ -25,8 +25,8 @@ func TestDeleteCustomerUseCase_Success(t *testing.T) {
 		BaseEntity:                  shared.NewBaseEntity(),
 		Cpf:                         "922.488.109-20",
 		CpfValido:                   true,
-		Private:                     "1",
-		Incompleto:                  "0",
+		Private:                     true,
+		Incompleto:                  false,
 		DataUltimaCompra:            &dataUltimaCompra,
 		TicketMedio:                 130.54,
 		TicketUltimaCompra:          130.54,
@@ -41,6 +41,7 @@ func TestDeleteCustomerUseCase_Success(t *testing.T) {
 	}
 
 	mockRepo.On("GetById", input.ID).Return(customer, nil)
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
 	mockRepo.On("Delete", mock.AnythingOfType("*entity.Customer")).Return(nil)
 
 	err := deleteCustomerUseCase.Execute(input)
@@ -74,8 +75,8 @@ func TestDeleteCustomerUseCase_ErrorDeletingCustomer(t *testing.T) {
 		BaseEntity:                  shared.NewBaseEntity(),
 		Cpf:                         "922.488.109-20",
 		CpfValido:                   true,
-		Private:                     "1",
-		Incompleto:                  "0",
+		Private:                     true,
+		Incompleto:                  false,
 		DataUltimaCompra:            &dataUltimaCompra,
 		TicketMedio:                 130.54,
 		TicketUltimaCompra:          130.54,
@@ -91,6 +92,7 @@ func TestDeleteCustomerUseCase_ErrorDeletingCustomer(t *testing.T) {
 
 	mockRepo.On("GetById", input.ID).Return(customer, nil)
 
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
 	mockRepo.On("Delete", mock.AnythingOfType("*entity.Customer")).Return(internalerrors.ErrInternal)
 
 	err := deleteCustomerUseCase.Execute(input)
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"io"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/repository"
+	"neoway_test/internal/domain/customer/service"
+	internalerrors "neoway_test/internal/internal-errors"
+)
+
+type ExportCustomersUseCase struct {
+	repo          repository.CustomerRepository
+	exportService *service.ExportService
+}
+
+func NewExportCustomersUseCase(repo repository.CustomerRepository, exportService *service.ExportService) *ExportCustomersUseCase {
+	return &ExportCustomersUseCase{
+		repo:          repo,
+		exportService: exportService,
+	}
+}
+
+// Prepare validates the export options and describes the file, so callers can
+// reject a bad request before they start writing.
+func (uc *ExportCustomersUseCase) Prepare(input dto.InputExportCustomersDto) (dto.OutputExportCustomersDto, error) {
+	format, contentType, err := uc.exportService.Resolve(exportOptions(input))
+	if err != nil {
+		return dto.OutputExportCustomersDto{}, err
+	}
+
+	return dto.OutputExportCustomersDto{
+		Format:      format,
+		ContentType: contentType,
+		FileName:    "customers." + format,
+	}, nil
+}
+
+// Execute streams every customer matching input to w and returns how many
+// were written.
+func (uc *ExportCustomersUseCase) Execute(input dto.InputExportCustomersDto, w io.Writer) (int, error) {
+	writer, err := uc.exportService.NewWriter(w, exportOptions(input))
+	if err != nil {
+		return 0, err
+	}
+
+	filter := repository.CustomerFilter{
+		ImportBatchID: input.ImportBatchID,
+		CpfValido:     input.CpfValido,
+		CreatedFrom:   input.CreatedFrom,
+		CreatedTo:     input.CreatedTo,
+	}
+
+	written := 0
+	var writeErr error
+	err = uc.repo.Stream(filter, func(customer *entity.Customer) error {
+		if writeErr = writer.Write(customer); writeErr != nil {
+			return writeErr
+		}
+		written++
+		return nil
+	})
+
+	if writeErr != nil {
+		return written, writeErr
+	}
+	if err != nil {
+		return written, internalerrors.ErrInternal
+	}
+
+	return written, writer.Flush()
+}
+
+func exportOptions(input dto.InputExportCustomersDto) service.ExportOptions {
+	return service.ExportOptions{Format: input.Format, Layout: input.Layout}
+}
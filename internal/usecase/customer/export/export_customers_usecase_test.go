@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/repository"
+	"neoway_test/internal/domain/customer/service"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newExportCustomersUseCase() (*ExportCustomersUseCase, *databaseRepository.CustomerRepositoryMock) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	return NewExportCustomersUseCase(mockRepo, service.NewExportService(service.NewLayoutRegistry())), mockRepo
+}
+
+func TestExportCustomersUseCase_Prepare(t *testing.T) {
+	exportCustomersUseCase, _ := newExportCustomersUseCase()
+
+	output, err := exportCustomersUseCase.Prepare(dto.InputExportCustomersDto{Format: "NDJSON"})
+	assert.Nil(t, err)
+	assert.Equal(t, dto.OutputExportCustomersDto{Format: "ndjson", ContentType: "application/x-ndjson", FileName: "customers.ndjson"}, output)
+
+	_, err = exportCustomersUseCase.Prepare(dto.InputExportCustomersDto{Format: "txt", Layout: "desconhecido"})
+	assert.True(t, errors.Is(err, service.ErrUnknownLayout))
+}
+
+func TestExportCustomersUseCase_Execute(t *testing.T) {
+	exportCustomersUseCase, mockRepo := newExportCustomersUseCase()
+
+	valid := true
+	customer, _ := entity.NewCustomer("026.987.379-13", false, true, nil, 159.31, 159.31, "NULL", "NULL")
+	mockRepo.On("Stream", repository.CustomerFilter{ImportBatchID: "batch-1", CpfValido: &valid}).Return([]*entity.Customer{customer}, nil)
+
+	var out bytes.Buffer
+	written, err := exportCustomersUseCase.Execute(dto.InputExportCustomersDto{ImportBatchID: "batch-1", CpfValido: &valid}, &out)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, written)
+	assert.Equal(t, "cpf,private,incompleto,data_ultima_compra,ticket_medio,ticket_ultima_compra,loja_mais_frequente,loja_ultima_compra\n"+
+		"02698737913,0,1,NULL,159.31,159.31,NULL,NULL\n", out.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExportCustomersUseCase_RepositoryError(t *testing.T) {
+	exportCustomersUseCase, mockRepo := newExportCustomersUseCase()
+
+	mockRepo.On("Stream", repository.CustomerFilter{}).Return(nil, errors.New("connection refused"))
+
+	_, err := exportCustomersUseCase.Execute(dto.InputExportCustomersDto{}, &bytes.Buffer{})
+
+	assert.Equal(t, internalerrors.ErrInternal, err)
+}
+
+func TestExportCustomersUseCase_WriteError(t *testing.T) {
+	exportCustomersUseCase, mockRepo := newExportCustomersUseCase()
+
+	customer, _ := entity.NewCustomer("026.987.379-13", false, true, nil, 0, 0, "NULL", "NULL")
+	customer.Cpf = "026.987.379-13 com observação"
+	mockRepo.On("Stream", repository.CustomerFilter{}).Return([]*entity.Customer{customer}, nil)
+
+	written, err := exportCustomersUseCase.Execute(dto.InputExportCustomersDto{Format: "txt"}, &bytes.Buffer{})
+
+	assert.Equal(t, 0, written)
+	assert.True(t, errors.Is(err, service.ErrValueTooWide))
+}
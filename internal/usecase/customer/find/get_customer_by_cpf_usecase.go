@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
 	"neoway_test/internal/domain/customer/repository"
 	internalerrors "neoway_test/internal/internal-errors"
 )
@@ -15,15 +16,31 @@ func NewGetCustomerByCpfUseCase(repo repository.CustomerRepository) *GetCustomer
 }
 
 func (uc *GetCustomerByCpfUseCase) Execute(input dto.InputGetCustomerByCpfDto) (*dto.OutputGetCustomerDto, error) {
-	customer, err := uc.repo.GetByCpf(input.Cpf)
+	if err := entity.ValidateCpfFormat(input.CpfFormat); err != nil {
+		return nil, err
+	}
+
+	repo := uc.repo
+	if input.IncludeDeleted {
+		repo = repo.WithDeleted()
+	}
+
+	// Customers are stored by the CPF's digits, so "123.456.789-09" and
+	// "12345678909" find the same customer.
+	customer, err := repo.GetByCpf(entity.NewCpf(input.Cpf).Digits())
 
 	if err != nil {
 		return nil, internalerrors.ProcessErrorToReturn(err)
 	}
 
+	cpf, err := customer.RenderCpf(input.CpfFormat)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dto.OutputGetCustomerDto{
 		ID:                          customer.ID,
-		Cpf:                         customer.Cpf,
+		Cpf:                         cpf,
 		CpfValido:                   customer.CpfValido,
 		Private:                     customer.Private,
 		Incompleto:                  customer.Incompleto,
@@ -34,6 +51,11 @@ func (uc *GetCustomerByCpfUseCase) Execute(input dto.InputGetCustomerByCpfDto) (
 		CnpjLojaMaisFrequenteValido: customer.CnpjLojaMaisFrequenteValido,
 		LojaUltimaCompra:            customer.LojaUltimaCompra,
 		CnpjLojaUltimaCompraValido:  customer.CnpjLojaUltimaCompraValido,
+		ImportBatchID:               customer.ImportBatchID,
+		SourceFileName:              customer.SourceFileName,
+		SourceFileHash:              customer.SourceFileHash,
+		SourceLineNumber:            customer.SourceLineNumber,
 		CreatedAt:                   customer.CreatedAt,
+		DeletedAt:                   customer.DeletionTime(),
 	}, nil
 }
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 func TestGetCustomerByCpfUseCase_Success(t *testing.T) {
@@ -21,10 +22,10 @@ func TestGetCustomerByCpfUseCase_Success(t *testing.T) {
 
 	customer := &entity.Customer{
 		BaseEntity:                  shared.NewBaseEntity(),
-		Cpf:                         "922.488.109-20",
+		Cpf:                         "92248810920",
 		CpfValido:                   true,
-		Private:                     "1",
-		Incompleto:                  "0",
+		Private:                     true,
+		Incompleto:                  false,
 		DataUltimaCompra:            &dataUltimaCompra,
 		TicketMedio:                 130.54,
 		TicketUltimaCompra:          130.54,
@@ -35,10 +36,10 @@ func TestGetCustomerByCpfUseCase_Success(t *testing.T) {
 	}
 
 	input := dto.InputGetCustomerByCpfDto{
-		Cpf: customer.Cpf,
+		Cpf: "922.488.109-20",
 	}
 
-	mockRepo.On("GetByCpf", input.Cpf).Return(customer, nil)
+	mockRepo.On("GetByCpf", "92248810920").Return(customer, nil)
 
 	output, err := getCustomerByCpfUseCase.Execute(input)
 
@@ -68,7 +69,7 @@ func TestGetCustomerByCpfUseCase_CustomerNotFound(t *testing.T) {
 		Cpf: "customer123",
 	}
 
-	mockRepo.On("GetByCpf", input.Cpf).Return(nil, errors.New("record not found"))
+	mockRepo.On("GetByCpf", "123").Return(nil, errors.New("record not found"))
 
 	output, err := getCustomerByCpfUseCase.Execute(input)
 
@@ -85,7 +86,7 @@ func TestGetCustomerByCpfUseCase_InternalError(t *testing.T) {
 		Cpf: "customer123",
 	}
 
-	mockRepo.On("GetByCpf", input.Cpf).Return(nil, internalerrors.ErrInternal)
+	mockRepo.On("GetByCpf", "123").Return(nil, internalerrors.ErrInternal)
 
 	output, err := getCustomerByCpfUseCase.Execute(input)
 
@@ -93,3 +94,38 @@ func TestGetCustomerByCpfUseCase_InternalError(t *testing.T) {
 	assert.Equal(t, internalerrors.ErrInternal, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetCustomerByCpfUseCase_CpfFormat(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	getCustomerByCpfUseCase := NewGetCustomerByCpfUseCase(mockRepo)
+
+	customer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 0, 0, "NULL", "NULL")
+	mockRepo.On("GetByCpf", "92248810920").Return(customer, nil)
+
+	for input, expected := range map[string]string{"": "92248810920", "formatted": "922.488.109-20", "masked": "***.488.109-**"} {
+		output, err := getCustomerByCpfUseCase.Execute(dto.InputGetCustomerByCpfDto{Cpf: "92248810920", CpfFormat: input})
+		assert.Nil(t, err)
+		assert.Equal(t, expected, output.Cpf)
+	}
+
+	output, err := getCustomerByCpfUseCase.Execute(dto.InputGetCustomerByCpfDto{Cpf: "92248810920", CpfFormat: "hex"})
+	assert.Nil(t, output)
+	assert.True(t, errors.Is(err, entity.ErrUnknownCpfFormat))
+}
+
+func TestGetCustomerByCpfUseCase_IncludeDeleted(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	getCustomerByCpfUseCase := NewGetCustomerByCpfUseCase(mockRepo)
+
+	customer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+	customer.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	mockRepo.On("WithDeleted").Return(mockRepo)
+	mockRepo.On("GetByCpf", "92248810920").Return(customer, nil)
+
+	output, err := getCustomerByCpfUseCase.Execute(dto.InputGetCustomerByCpfDto{Cpf: "922.488.109-20", IncludeDeleted: true})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, output.DeletedAt)
+	mockRepo.AssertExpectations(t)
+}
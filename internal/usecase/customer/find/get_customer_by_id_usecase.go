@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
 	"neoway_test/internal/domain/customer/repository"
 	internalerrors "neoway_test/internal/internal-errors"
 )
@@ -15,15 +16,29 @@ func NewGetCustomerByIdUseCase(repo repository.CustomerRepository) *GetCustomerB
 }
 
 func (uc *GetCustomerByIdUseCase) Execute(input dto.InputGetCustomerByIdDto) (*dto.OutputGetCustomerDto, error) {
-	customer, err := uc.repo.GetById(input.ID)
+	if err := entity.ValidateCpfFormat(input.CpfFormat); err != nil {
+		return nil, err
+	}
+
+	repo := uc.repo
+	if input.IncludeDeleted {
+		repo = repo.WithDeleted()
+	}
+
+	customer, err := repo.GetById(input.ID)
 
 	if err != nil {
 		return nil, internalerrors.ProcessErrorToReturn(err)
 	}
 
+	cpf, err := customer.RenderCpf(input.CpfFormat)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dto.OutputGetCustomerDto{
 		ID:                          customer.ID,
-		Cpf:                         customer.Cpf,
+		Cpf:                         cpf,
 		CpfValido:                   customer.CpfValido,
 		Private:                     customer.Private,
 		Incompleto:                  customer.Incompleto,
@@ -34,6 +49,11 @@ func (uc *GetCustomerByIdUseCase) Execute(input dto.InputGetCustomerByIdDto) (*d
 		CnpjLojaMaisFrequenteValido: customer.CnpjLojaMaisFrequenteValido,
 		LojaUltimaCompra:            customer.LojaUltimaCompra,
 		CnpjLojaUltimaCompraValido:  customer.CnpjLojaUltimaCompraValido,
+		ImportBatchID:               customer.ImportBatchID,
+		SourceFileName:              customer.SourceFileName,
+		SourceFileHash:              customer.SourceFileHash,
+		SourceLineNumber:            customer.SourceLineNumber,
 		CreatedAt:                   customer.CreatedAt,
+		DeletedAt:                   customer.DeletionTime(),
 	}, nil
 }
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 func TestGetCustomerByIdUseCase_Success(t *testing.T) {
@@ -21,10 +22,10 @@ func TestGetCustomerByIdUseCase_Success(t *testing.T) {
 
 	customer := &entity.Customer{
 		BaseEntity:                  shared.NewBaseEntity(),
-		Cpf:                         "922.488.109-20",
+		Cpf:                         "92248810920",
 		CpfValido:                   true,
-		Private:                     "1",
-		Incompleto:                  "0",
+		Private:                     true,
+		Incompleto:                  false,
 		DataUltimaCompra:            &dataUltimaCompra,
 		TicketMedio:                 130.54,
 		TicketUltimaCompra:          130.54,
@@ -93,3 +94,21 @@ func TestGetCustomerByIdUseCase_InternalError(t *testing.T) {
 	assert.Equal(t, internalerrors.ErrInternal, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetCustomerByIdUseCase_IncludeDeleted(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	getCustomerByIdUseCase := NewGetCustomerByIdUseCase(mockRepo)
+
+	deletedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	customer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+	customer.DeletedAt = gorm.DeletedAt{Time: deletedAt, Valid: true}
+
+	mockRepo.On("WithDeleted").Return(mockRepo)
+	mockRepo.On("GetById", customer.ID).Return(customer, nil)
+
+	output, err := getCustomerByIdUseCase.Execute(dto.InputGetCustomerByIdDto{ID: customer.ID, IncludeDeleted: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, deletedAt, *output.DeletedAt)
+	mockRepo.AssertExpectations(t)
+}
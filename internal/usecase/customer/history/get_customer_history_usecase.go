@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+)
+
+type GetCustomerHistoryUseCase struct {
+	repo repository.CustomerRepository
+}
+
+func NewGetCustomerHistoryUseCase(repo repository.CustomerRepository) *GetCustomerHistoryUseCase {
+	return &GetCustomerHistoryUseCase{repo: repo}
+}
+
+// Execute lists the audit log of a customer, oldest first, with the CPFs it
+// holds rendered in the requested format.
+func (uc *GetCustomerHistoryUseCase) Execute(input dto.InputGetCustomerHistoryDto) ([]*dto.OutputCustomerAuditDto, error) {
+	if err := entity.ValidateCpfFormat(input.CpfFormat); err != nil {
+		return nil, err
+	}
+
+	audits, err := uc.repo.GetHistory(input.ID, input.Page)
+	if err != nil {
+		return nil, internalerrors.ProcessErrorToReturn(err)
+	}
+
+	var history []*dto.OutputCustomerAuditDto
+	for _, audit := range audits {
+		changes := make(map[string]dto.OutputFieldChangeDto, len(audit.Changes))
+		for name, change := range audit.Changes {
+			if name == "cpf" {
+				change.Before = renderCpf(change.Before, input.CpfFormat)
+				change.After = renderCpf(change.After, input.CpfFormat)
+			}
+			changes[name] = dto.OutputFieldChangeDto{Before: change.Before, After: change.After}
+		}
+
+		history = append(history, &dto.OutputCustomerAuditDto{
+			ID:            audit.ID,
+			CustomerID:    audit.CustomerID,
+			Operation:     audit.Operation,
+			Actor:         audit.Actor,
+			RequestID:     audit.RequestID,
+			ImportBatchID: audit.ImportBatchID,
+			Changes:       changes,
+			CreatedAt:     audit.CreatedAt,
+		})
+	}
+
+	return history, nil
+}
+
+// renderCpf renders a CPF recorded in the log like the customer queries do.
+// The format is already validated, and values that are not CPFs, such as the
+// nil of a created customer, are kept.
+func renderCpf(value interface{}, format string) interface{} {
+	cpf, ok := value.(string)
+	if !ok {
+		return value
+	}
+	rendered, err := (&entity.Customer{Cpf: cpf}).RenderCpf(format)
+	if err != nil {
+		return value
+	}
+	return rendered
+}
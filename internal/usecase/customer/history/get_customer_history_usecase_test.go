@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"errors"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCustomerHistoryUseCase_Success(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	getCustomerHistoryUseCase := NewGetCustomerHistoryUseCase(mockRepo)
+
+	customer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+	created := entity.NewCustomerAudit(entity.AuditInfo{Actor: "alice", RequestID: "req-1"}, entity.AuditOperationCreate, "", nil, customer)
+	updated := *customer
+	updated.TicketMedio = 20
+	edited := entity.NewCustomerAudit(entity.AuditInfo{Actor: "bob"}, entity.AuditOperationUpdate, "", customer, &updated)
+
+	mockRepo.On("GetHistory", customer.ID, 1).Return([]*entity.CustomerAudit{created, edited}, nil)
+
+	history, err := getCustomerHistoryUseCase.Execute(dto.InputGetCustomerHistoryDto{ID: customer.ID, Page: 1, CpfFormat: "masked"})
+
+	assert.Nil(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, created.ID, history[0].ID)
+	assert.Equal(t, customer.ID, history[0].CustomerID)
+	assert.Equal(t, entity.AuditOperationCreate, history[0].Operation)
+	assert.Equal(t, "alice", history[0].Actor)
+	assert.Equal(t, "req-1", history[0].RequestID)
+	assert.Equal(t, dto.OutputFieldChangeDto{Before: nil, After: "***.488.109-**"}, history[0].Changes["cpf"])
+	assert.Equal(t, entity.AuditOperationUpdate, history[1].Operation)
+	assert.Equal(t, map[string]dto.OutputFieldChangeDto{"ticket_medio": {Before: 10.0, After: 20.0}}, history[1].Changes)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetCustomerHistoryUseCase_Empty(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	getCustomerHistoryUseCase := NewGetCustomerHistoryUseCase(mockRepo)
+
+	mockRepo.On("GetHistory", "unknown", 1).Return([]*entity.CustomerAudit{}, nil)
+
+	history, err := getCustomerHistoryUseCase.Execute(dto.InputGetCustomerHistoryDto{ID: "unknown", Page: 1})
+
+	assert.Nil(t, err)
+	assert.Nil(t, history)
+}
+
+func TestGetCustomerHistoryUseCase_InvalidCpfFormat(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	getCustomerHistoryUseCase := NewGetCustomerHistoryUseCase(mockRepo)
+
+	history, err := getCustomerHistoryUseCase.Execute(dto.InputGetCustomerHistoryDto{ID: "id", Page: 1, CpfFormat: "upper"})
+
+	assert.Error(t, err)
+	assert.Nil(t, history)
+	mockRepo.AssertNotCalled(t, "GetHistory")
+}
+
+func TestGetCustomerHistoryUseCase_RepositoryError(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	getCustomerHistoryUseCase := NewGetCustomerHistoryUseCase(mockRepo)
+
+	mockRepo.On("GetHistory", "id", 1).Return(nil, errors.New("connection refused"))
+
+	history, err := getCustomerHistoryUseCase.Execute(dto.InputGetCustomerHistoryDto{ID: "id", Page: 1})
+
+	assert.Equal(t, internalerrors.ErrInternal, err)
+	assert.Nil(t, history)
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
 	"neoway_test/internal/domain/customer/repository"
 	internalerrors "neoway_test/internal/internal-errors"
 )
@@ -15,7 +16,16 @@ func NewGetCustomersListUseCase(repo repository.CustomerRepository) *GetCustomer
 }
 
 func (uc *GetCustomersListUseCase) Execute(input dto.InputGetCustomersListDto) ([]*dto.OutputGetCustomersListDto, error) {
-	customerList, err := uc.repo.Get(input.Page)
+	if err := entity.ValidateCpfFormat(input.CpfFormat); err != nil {
+		return nil, err
+	}
+
+	repo := uc.repo
+	if input.IncludeDeleted {
+		repo = repo.WithDeleted()
+	}
+
+	customerList, err := repo.Get(input.Page)
 
 	if err != nil {
 		return nil, internalerrors.ProcessErrorToReturn(err)
@@ -23,9 +33,13 @@ func (uc *GetCustomersListUseCase) Execute(input dto.InputGetCustomersListDto) (
 
 	var customersDto []*dto.OutputGetCustomersListDto
 	for _, customer := range customerList {
+		cpf, err := customer.RenderCpf(input.CpfFormat)
+		if err != nil {
+			return nil, err
+		}
 		filaLojaDto := &dto.OutputGetCustomersListDto{
 			ID:                          customer.ID,
-			Cpf:                         customer.Cpf,
+			Cpf:                         cpf,
 			CpfValido:                   customer.CpfValido,
 			Private:                     customer.Private,
 			Incompleto:                  customer.Incompleto,
@@ -36,7 +50,12 @@ func (uc *GetCustomersListUseCase) Execute(input dto.InputGetCustomersListDto) (
 			CnpjLojaMaisFrequenteValido: customer.CnpjLojaMaisFrequenteValido,
 			LojaUltimaCompra:            customer.LojaUltimaCompra,
 			CnpjLojaUltimaCompraValido:  customer.CnpjLojaUltimaCompraValido,
+			ImportBatchID:               customer.ImportBatchID,
+			SourceFileName:              customer.SourceFileName,
+			SourceFileHash:              customer.SourceFileHash,
+			SourceLineNumber:            customer.SourceLineNumber,
 			CreatedAt:                   customer.CreatedAt,
+			DeletedAt:                   customer.DeletionTime(),
 		}
 		customersDto = append(customersDto, filaLojaDto)
 	}
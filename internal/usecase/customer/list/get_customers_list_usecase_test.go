@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 func TestGetCustomersListUseCase_Success(t *testing.T) {
@@ -21,10 +22,10 @@ func TestGetCustomersListUseCase_Success(t *testing.T) {
 	customers := []*entity.Customer{
 		{
 			BaseEntity:                  shared.NewBaseEntity(),
-			Cpf:                         "922.488.109-20",
+			Cpf:                         "92248810920",
 			CpfValido:                   true,
-			Private:                     "1",
-			Incompleto:                  "0",
+			Private:                     true,
+			Incompleto:                  false,
 			DataUltimaCompra:            &dataUltimaCompra,
 			TicketMedio:                 130.54,
 			TicketUltimaCompra:          130.54,
@@ -91,3 +92,23 @@ func TestGetCustomersListUseCase_InternalError(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetCustomersListUseCase_IncludeDeleted(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	getCustomersListUseCase := NewGetCustomersListUseCase(mockRepo)
+
+	active, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+	deleted, _ := entity.NewCustomer("041.091.641-25", false, false, nil, 10, 10, "NULL", "NULL")
+	deleted.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	mockRepo.On("WithDeleted").Return(mockRepo)
+	mockRepo.On("Get", 1).Return([]*entity.Customer{active, deleted}, nil)
+
+	output, err := getCustomersListUseCase.Execute(dto.InputGetCustomersListDto{Page: 1, IncludeDeleted: true})
+
+	assert.Nil(t, err)
+	assert.Len(t, output, 2)
+	assert.Nil(t, output[0].DeletedAt)
+	assert.NotNil(t, output[1].DeletedAt)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"time"
+)
+
+// PurgeActor is the actor of purges in the audit log.
+const PurgeActor = "retention-purge"
+
+type PurgeDeletedCustomersUseCase struct {
+	repo      repository.CustomerRepository
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewPurgeDeletedCustomersUseCase purges the customers deleted longer than
+// retention ago.
+func NewPurgeDeletedCustomersUseCase(repo repository.CustomerRepository, retention time.Duration) *PurgeDeletedCustomersUseCase {
+	return &PurgeDeletedCustomersUseCase{repo: repo, retention: retention, now: time.Now}
+}
+
+// Execute removes for good the customers past the retention period, with
+// their purchases, and returns how many.
+func (uc *PurgeDeletedCustomersUseCase) Execute() (int, error) {
+	purged, err := uc.repo.WithAudit(entity.AuditInfo{Actor: PurgeActor}).Purge(uc.now().Add(-uc.retention))
+	if err != nil {
+		return 0, internalerrors.ErrInternal
+	}
+	return purged, nil
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"errors"
+	"neoway_test/internal/domain/customer/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPurgeDeletedCustomersUseCase_Success(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	purgeUseCase := NewPurgeDeletedCustomersUseCase(mockRepo, 30*24*time.Hour)
+	purgeUseCase.now = func() time.Time { return time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC) }
+
+	mockRepo.On("WithAudit", entity.AuditInfo{Actor: PurgeActor}).Return(mockRepo)
+	mockRepo.On("Purge", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)).Return(4, nil)
+
+	purged, err := purgeUseCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, purged)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPurgeDeletedCustomersUseCase_RepositoryError(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	purgeUseCase := NewPurgeDeletedCustomersUseCase(mockRepo, time.Hour)
+
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("Purge", mock.Anything).Return(0, errors.New("database error"))
+
+	purged, err := purgeUseCase.Execute()
+
+	assert.Equal(t, 0, purged)
+	assert.Equal(t, internalerrors.ErrInternal, err)
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"fmt"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+)
+
+var ErrCustomerNotDeleted = fmt.Errorf("%w: customer is not deleted", internalerrors.ErrConflict)
+
+type RestoreCustomerUseCase struct {
+	repo repository.CustomerRepository
+}
+
+func NewRestoreCustomerUseCase(repo repository.CustomerRepository) *RestoreCustomerUseCase {
+	return &RestoreCustomerUseCase{repo: repo}
+}
+
+// Execute brings back a deleted customer that was not purged yet.
+func (uc *RestoreCustomerUseCase) Execute(input dto.InputRestoreCustomerDto) (*dto.OutputGetCustomerDto, error) {
+	if err := entity.ValidateCpfFormat(input.CpfFormat); err != nil {
+		return nil, err
+	}
+
+	customer, err := uc.repo.WithDeleted().GetById(input.ID)
+	if err != nil {
+		return nil, internalerrors.ProcessErrorToReturn(err)
+	}
+	if customer.DeletionTime() == nil {
+		return nil, ErrCustomerNotDeleted
+	}
+
+	if err := uc.repo.WithAudit(entity.AuditInfo{Actor: input.Actor, RequestID: input.RequestID}).Restore(customer); err != nil {
+		return nil, internalerrors.ProcessErrorToReturn(err)
+	}
+
+	cpf, err := customer.RenderCpf(input.CpfFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OutputGetCustomerDto{
+		ID:                          customer.ID,
+		Cpf:                         cpf,
+		CpfValido:                   customer.CpfValido,
+		Private:                     customer.Private,
+		Incompleto:                  customer.Incompleto,
+		DataUltimaCompra:            customer.DataUltimaCompra,
+		TicketMedio:                 customer.TicketMedio,
+		TicketUltimaCompra:          customer.TicketUltimaCompra,
+		LojaMaisFrequente:           customer.LojaMaisFrequente,
+		CnpjLojaMaisFrequenteValido: customer.CnpjLojaMaisFrequenteValido,
+		LojaUltimaCompra:            customer.LojaUltimaCompra,
+		CnpjLojaUltimaCompraValido:  customer.CnpjLojaUltimaCompraValido,
+		ImportBatchID:               customer.ImportBatchID,
+		SourceFileName:              customer.SourceFileName,
+		SourceFileHash:              customer.SourceFileHash,
+		SourceLineNumber:            customer.SourceLineNumber,
+		CreatedAt:                   customer.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"errors"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestRestoreCustomerUseCase_Success(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	restoreCustomerUseCase := NewRestoreCustomerUseCase(mockRepo)
+
+	customer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+	customer.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	mockRepo.On("WithDeleted").Return(mockRepo)
+	mockRepo.On("GetById", customer.ID).Return(customer, nil)
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("Restore", customer).Return(nil)
+
+	output, err := restoreCustomerUseCase.Execute(dto.InputRestoreCustomerDto{ID: customer.ID, CpfFormat: "masked"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, customer.ID, output.ID)
+	assert.Equal(t, "***.488.109-**", output.Cpf)
+	assert.Nil(t, output.DeletedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRestoreCustomerUseCase_NotDeleted(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	restoreCustomerUseCase := NewRestoreCustomerUseCase(mockRepo)
+
+	customer, _ := entity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "NULL", "NULL")
+
+	mockRepo.On("WithDeleted").Return(mockRepo)
+	mockRepo.On("GetById", customer.ID).Return(customer, nil)
+
+	output, err := restoreCustomerUseCase.Execute(dto.InputRestoreCustomerDto{ID: customer.ID})
+
+	assert.Nil(t, output)
+	assert.True(t, errors.Is(err, internalerrors.ErrConflict))
+	mockRepo.AssertNotCalled(t, "Restore", customer)
+}
+
+func TestRestoreCustomerUseCase_NotFound(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	restoreCustomerUseCase := NewRestoreCustomerUseCase(mockRepo)
+
+	mockRepo.On("WithDeleted").Return(mockRepo)
+	mockRepo.On("GetById", "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	output, err := restoreCustomerUseCase.Execute(dto.InputRestoreCustomerDto{ID: "missing"})
+
+	assert.Nil(t, output)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"errors"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+
+	"gorm.io/gorm"
+)
+
+type RollbackImportBatchUseCase struct {
+	repo repository.CustomerRepository
+}
+
+func NewRollbackImportBatchUseCase(repo repository.CustomerRepository) *RollbackImportBatchUseCase {
+	return &RollbackImportBatchUseCase{repo: repo}
+}
+
+// Execute deletes the customers an import batch created and reverts the ones
+// it updated, all in one transaction. A batch with nothing left to undo is
+// reported as not found.
+func (uc *RollbackImportBatchUseCase) Execute(input dto.InputRollbackImportBatchDto) (dto.OutputRollbackImportBatchDto, error) {
+	removed, reverted, skipped, err := uc.repo.WithAudit(entity.AuditInfo{Actor: input.Actor, RequestID: input.RequestID}).RollbackBatch(input.BatchID)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.OutputRollbackImportBatchDto{}, err
+	}
+	if err != nil {
+		return dto.OutputRollbackImportBatchDto{}, internalerrors.ErrInternal
+	}
+
+	return dto.OutputRollbackImportBatchDto{
+		BatchID:  input.BatchID,
+		Removed:  removed,
+		Reverted: reverted,
+		Skipped:  skipped,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"errors"
+	"neoway_test/internal/domain/customer/dto"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestRollbackImportBatchUseCase_Success(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	rollbackImportBatchUseCase := NewRollbackImportBatchUseCase(mockRepo)
+
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("RollbackBatch", "batch-1").Return(3, 2, 1, nil)
+
+	output, err := rollbackImportBatchUseCase.Execute(dto.InputRollbackImportBatchDto{BatchID: "batch-1"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, dto.OutputRollbackImportBatchDto{BatchID: "batch-1", Removed: 3, Reverted: 2, Skipped: 1}, output)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRollbackImportBatchUseCase_NotFound(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	rollbackImportBatchUseCase := NewRollbackImportBatchUseCase(mockRepo)
+
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("RollbackBatch", "batch-1").Return(0, 0, 0, gorm.ErrRecordNotFound)
+
+	output, err := rollbackImportBatchUseCase.Execute(dto.InputRollbackImportBatchDto{BatchID: "batch-1"})
+
+	assert.Empty(t, output)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
+
+func TestRollbackImportBatchUseCase_RepositoryError(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	rollbackImportBatchUseCase := NewRollbackImportBatchUseCase(mockRepo)
+
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("RollbackBatch", "batch-1").Return(0, 0, 0, errors.New("database error"))
+
+	output, err := rollbackImportBatchUseCase.Execute(dto.InputRollbackImportBatchDto{BatchID: "batch-1"})
+
+	assert.Empty(t, output)
+	assert.Equal(t, internalerrors.ErrInternal, err)
+}
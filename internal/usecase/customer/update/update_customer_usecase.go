@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/repository"
+	"neoway_test/internal/domain/customer/service"
+	internalerrors "neoway_test/internal/internal-errors"
+
+	"gorm.io/gorm"
+)
+
+var ErrCpfInUse = fmt.Errorf("%w: cpf belongs to another customer", internalerrors.ErrConflict)
+
+type UpdateCustomerUseCase struct {
+	repo         repository.CustomerRepository
+	parseService *service.ParseService
+}
+
+func NewUpdateCustomerUseCase(repo repository.CustomerRepository, parseService *service.ParseService) *UpdateCustomerUseCase {
+	return &UpdateCustomerUseCase{
+		repo:         repo,
+		parseService: parseService,
+	}
+}
+
+// Execute applies input over the stored customer and validates the result
+// like a new customer, keeping its ID and creation time. An edited customer
+// no longer holds what its import wrote, so it loses its provenance and a
+// rollback of that import leaves it alone.
+func (uc *UpdateCustomerUseCase) Execute(input dto.InputUpdateCustomerDto) (*dto.OutputGetCustomerDto, error) {
+	if err := entity.ValidateCpfFormat(input.CpfFormat); err != nil {
+		return nil, err
+	}
+
+	stored, err := uc.repo.GetById(input.ID)
+	if err != nil {
+		return nil, internalerrors.ProcessErrorToReturn(err)
+	}
+
+	customerDTO, err := uc.parseService.ExecuteParseService(mergeCustomer(stored, input))
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := entity.NewCustomer(
+		customerDTO.Cpf,
+		customerDTO.Private,
+		customerDTO.Incompleto,
+		customerDTO.DataUltimaCompra,
+		customerDTO.TicketMedio,
+		customerDTO.TicketUltimaCompra,
+		customerDTO.LojaMaisFrequente,
+		customerDTO.LojaUltimaCompra,
+	)
+	if err != nil {
+		return nil, err
+	}
+	customer.BaseEntity = stored.BaseEntity
+
+	if customer.CpfNormalizado != "" && customer.CpfNormalizado != stored.CpfNormalizado {
+		// Deleted customers keep their CPF until they are purged.
+		other, err := uc.repo.WithDeleted().GetByCpf(customer.CpfNormalizado)
+		if err == nil && other.ID != customer.ID {
+			return nil, ErrCpfInUse
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, internalerrors.ErrInternal
+		}
+	}
+
+	if err := uc.repo.WithAudit(entity.AuditInfo{Actor: input.Actor, RequestID: input.RequestID}).Update(customer); err != nil {
+		return nil, internalerrors.ProcessErrorToReturn(err)
+	}
+
+	cpf, err := customer.RenderCpf(input.CpfFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OutputGetCustomerDto{
+		ID:                          customer.ID,
+		Cpf:                         cpf,
+		CpfValido:                   customer.CpfValido,
+		Private:                     customer.Private,
+		Incompleto:                  customer.Incompleto,
+		DataUltimaCompra:            customer.DataUltimaCompra,
+		TicketMedio:                 customer.TicketMedio,
+		TicketUltimaCompra:          customer.TicketUltimaCompra,
+		LojaMaisFrequente:           customer.LojaMaisFrequente,
+		CnpjLojaMaisFrequenteValido: customer.CnpjLojaMaisFrequenteValido,
+		LojaUltimaCompra:            customer.LojaUltimaCompra,
+		CnpjLojaUltimaCompraValido:  customer.CnpjLojaUltimaCompraValido,
+		ImportBatchID:               customer.ImportBatchID,
+		SourceFileName:              customer.SourceFileName,
+		SourceFileHash:              customer.SourceFileHash,
+		SourceLineNumber:            customer.SourceLineNumber,
+		CreatedAt:                   customer.CreatedAt,
+	}, nil
+}
+
+// mergeCustomer writes stored back as create input, with the fields set in
+// input in place of the stored ones.
+func mergeCustomer(stored *entity.Customer, input dto.InputUpdateCustomerDto) dto.InputCreateCustomerDto {
+	merged := dto.InputCreateCustomerDto{
+		Cpf:                stored.Cpf,
+		Private:            dto.BoolFlag(stored.Private),
+		Incompleto:         dto.BoolFlag(stored.Incompleto),
+		DataUltimaCompra:   "NULL",
+		TicketMedio:        stored.TicketMedio,
+		TicketUltimaCompra: stored.TicketUltimaCompra,
+		LojaMaisFrequente:  stored.LojaMaisFrequente,
+		LojaUltimaCompra:   stored.LojaUltimaCompra,
+	}
+	if stored.DataUltimaCompra != nil {
+		// Dates are stored as midnight UTC and read back in the local time zone.
+		merged.DataUltimaCompra = stored.DataUltimaCompra.UTC().Format("2006-01-02")
+	}
+
+	if input.Cpf != nil {
+		merged.Cpf = *input.Cpf
+	}
+	if input.Private != nil {
+		merged.Private = *input.Private
+	}
+	if input.Incompleto != nil {
+		merged.Incompleto = *input.Incompleto
+	}
+	if input.DataUltimaCompra != nil {
+		merged.DataUltimaCompra = *input.DataUltimaCompra
+	}
+	if input.TicketMedio != nil {
+		merged.TicketMedio = *input.TicketMedio
+	}
+	if input.TicketUltimaCompra != nil {
+		merged.TicketUltimaCompra = *input.TicketUltimaCompra
+	}
+	if input.LojaMaisFrequente != nil {
+		merged.LojaMaisFrequente = *input.LojaMaisFrequente
+	}
+	if input.LojaUltimaCompra != nil {
+		merged.LojaUltimaCompra = *input.LojaUltimaCompra
+	}
+	return merged
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"errors"
+	"neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/service"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func storedCustomer() *entity.Customer {
+	dataUltimaCompra := time.Date(2011, 10, 4, 0, 0, 0, 0, time.UTC).In(time.FixedZone("BRT", -3*60*60))
+	customer, _ := entity.NewCustomer("152.298.818-10", false, true, &dataUltimaCompra, 100.5, 200.75, "79.379.491/0008-50", "79.379.491/0008-50")
+	customer.TraceTo("batch", "base.txt", "hash", 7)
+	return customer
+}
+
+func TestUpdateCustomerUseCase_Patch(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	updateCustomerUseCase := NewUpdateCustomerUseCase(mockRepo, service.NewParseService())
+
+	stored := storedCustomer()
+	ticketMedio := 50.0
+	loja := " 79.379.491/0001-83 "
+	input := dto.InputUpdateCustomerDto{ID: stored.ID, TicketMedio: &ticketMedio, LojaUltimaCompra: &loja}
+
+	mockRepo.On("GetById", stored.ID).Return(stored, nil)
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("Update", mock.MatchedBy(func(customer *entity.Customer) bool {
+		return customer.ID == stored.ID &&
+			customer.CreatedAt.Equal(stored.CreatedAt) &&
+			customer.ImportBatchID == "" &&
+			customer.SourceLineNumber == 0 &&
+			customer.Cpf == "15229881810" &&
+			customer.Incompleto &&
+			customer.TicketMedio == 50 &&
+			customer.TicketUltimaCompra == 200.75 &&
+			customer.LojaUltimaCompra == "79.379.491/0001-83" &&
+			*customer.LojaUltimaCompraCnpj == "79379491000183" &&
+			customer.CnpjLojaUltimaCompraValido &&
+			customer.DataUltimaCompra.Equal(time.Date(2011, 10, 4, 0, 0, 0, 0, time.UTC))
+	})).Return(nil)
+
+	output, err := updateCustomerUseCase.Execute(input)
+
+	assert.Nil(t, err)
+	assert.Equal(t, stored.ID, output.ID)
+	assert.Equal(t, 50.0, output.TicketMedio)
+	assert.Equal(t, "79.379.491/0008-50", output.LojaMaisFrequente)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetByCpf")
+}
+
+func TestUpdateCustomerUseCase_PatchMalformedDate(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	updateCustomerUseCase := NewUpdateCustomerUseCase(mockRepo, service.NewParseService())
+
+	stored := storedCustomer()
+	date := "2011-13-45"
+	input := dto.InputUpdateCustomerDto{ID: stored.ID, DataUltimaCompra: &date}
+
+	mockRepo.On("GetById", stored.ID).Return(stored, nil)
+
+	output, err := updateCustomerUseCase.Execute(input)
+
+	assert.Nil(t, output)
+	assert.True(t, errors.Is(err, service.ErrInvalidDate))
+	assert.EqualError(t, err, `data_ultima_compra "2011-13-45": invalid date`)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestUpdateCustomerUseCase_Replace(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	updateCustomerUseCase := NewUpdateCustomerUseCase(mockRepo, service.NewParseService())
+
+	stored := storedCustomer()
+	input := dto.NewInputReplaceCustomerDto(stored.ID, dto.InputCreateCustomerDto{Cpf: "041.091.641-25", Private: true})
+	input.CpfFormat = "formatted"
+
+	mockRepo.On("GetById", stored.ID).Return(stored, nil)
+	mockRepo.On("WithDeleted").Return(mockRepo)
+	mockRepo.On("GetByCpf", "04109164125").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("Update", mock.AnythingOfType("*entity.Customer")).Return(nil)
+
+	output, err := updateCustomerUseCase.Execute(input)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "041.091.641-25", output.Cpf)
+	assert.True(t, output.Private)
+	assert.False(t, output.Incompleto)
+	assert.Nil(t, output.DataUltimaCompra)
+	assert.Equal(t, 0.0, output.TicketMedio)
+	assert.Equal(t, "NULL", output.LojaMaisFrequente)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateCustomerUseCase_CpfInUse(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	updateCustomerUseCase := NewUpdateCustomerUseCase(mockRepo, service.NewParseService())
+
+	stored := storedCustomer()
+	other, _ := entity.NewCustomer("041.091.641-25", false, false, nil, 0, 0, "NULL", "NULL")
+	cpf := "04109164125"
+
+	mockRepo.On("GetById", stored.ID).Return(stored, nil)
+	mockRepo.On("WithDeleted").Return(mockRepo)
+	mockRepo.On("GetByCpf", cpf).Return(other, nil)
+
+	output, err := updateCustomerUseCase.Execute(dto.InputUpdateCustomerDto{ID: stored.ID, Cpf: &cpf})
+
+	assert.Nil(t, output)
+	assert.True(t, errors.Is(err, internalerrors.ErrConflict))
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestUpdateCustomerUseCase_NotFound(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	updateCustomerUseCase := NewUpdateCustomerUseCase(mockRepo, service.NewParseService())
+
+	mockRepo.On("GetById", "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	output, err := updateCustomerUseCase.Execute(dto.InputUpdateCustomerDto{ID: "missing"})
+
+	assert.Nil(t, output)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
+
+func TestUpdateCustomerUseCase_RepositoryError(t *testing.T) {
+	mockRepo := new(databaseRepository.CustomerRepositoryMock)
+	updateCustomerUseCase := NewUpdateCustomerUseCase(mockRepo, service.NewParseService())
+
+	stored := storedCustomer()
+	mockRepo.On("GetById", stored.ID).Return(stored, nil)
+	mockRepo.On("WithAudit", mock.Anything).Return(mockRepo)
+	mockRepo.On("Update", mock.Anything).Return(errors.New("database error"))
+
+	output, err := updateCustomerUseCase.Execute(dto.InputUpdateCustomerDto{ID: stored.ID})
+
+	assert.Nil(t, output)
+	assert.Equal(t, internalerrors.ErrInternal, err)
+}
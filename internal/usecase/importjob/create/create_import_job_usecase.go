@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"bufio"
+	"io"
+	"neoway_test/internal/domain/customer/service"
+	"neoway_test/internal/domain/importjob/dto"
+	"neoway_test/internal/domain/importjob/entity"
+	"neoway_test/internal/domain/importjob/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"os"
+	"path/filepath"
+)
+
+// ImportJobQueue is notified whenever a new job is waiting to be processed.
+type ImportJobQueue interface {
+	Enqueue(jobID string)
+}
+
+type CreateImportJobUseCase struct {
+	repo        repository.ImportJobRepository
+	queue       ImportJobQueue
+	fileFormats *service.FileFormatRegistry
+	uploadDir   string
+}
+
+func NewCreateImportJobUseCase(repo repository.ImportJobRepository, queue ImportJobQueue, fileFormats *service.FileFormatRegistry, uploadDir string) *CreateImportJobUseCase {
+	return &CreateImportJobUseCase{
+		repo:        repo,
+		queue:       queue,
+		fileFormats: fileFormats,
+		uploadDir:   uploadDir,
+	}
+}
+
+// Execute stores the upload on disk, persists a queued job pointing at it and
+// hands the job to the background worker. An upload already on disk, given by
+// FilePath, is handed over to the job as is.
+func (uc *CreateImportJobUseCase) Execute(input dto.InputCreateImportJobDto) (dto.OutputImportJobDto, error) {
+	options := service.ParseOptions{
+		Format:      input.Format,
+		ContentType: input.ContentType,
+		FileName:    input.FileName,
+		Layout:      input.Layout,
+		Encoding:    input.Encoding,
+	}
+
+	// The format is resolved now, while the upload's name and content type are known.
+	format, err := uc.fileFormats.Resolve(options)
+	if err != nil {
+		return dto.OutputImportJobDto{}, err
+	}
+	if err := uc.fileFormats.ValidateOptions(options); err != nil {
+		return dto.OutputImportJobDto{}, err
+	}
+	if err := service.ValidateDuplicatePolicy(input.DuplicatePolicy); err != nil {
+		return dto.OutputImportJobDto{}, err
+	}
+	// The content type is not kept, so a charset it carries is resolved now too.
+	encoding, err := service.ResolveEncoding(options)
+	if err != nil {
+		return dto.OutputImportJobDto{}, err
+	}
+
+	upload := input.File
+	if input.FilePath != "" {
+		stored, err := os.Open(input.FilePath)
+		if err != nil {
+			return dto.OutputImportJobDto{}, internalerrors.ErrInternal
+		}
+		defer stored.Close()
+		upload = stored
+	}
+
+	buffered := bufio.NewReader(upload)
+	// Archive members are resolved one by one when the job runs, so only a
+	// format given explicitly applies to them.
+	if service.IsCompressed(buffered) {
+		format = input.Format
+	}
+
+	filePath := input.FilePath
+	if filePath == "" {
+		filePath, err = uc.store(buffered)
+		if err != nil {
+			return dto.OutputImportJobDto{}, err
+		}
+	}
+
+	job := entity.NewImportJob(filepath.Base(input.FileName), filePath, format, input.Layout, encoding, input.Strict, input.DuplicatePolicy)
+	job.RequestBy(input.Actor, input.RequestID)
+
+	if err := uc.repo.Create(job); err != nil {
+		if input.FilePath == "" {
+			os.Remove(filePath)
+		}
+		return dto.OutputImportJobDto{}, internalerrors.ErrInternal
+	}
+
+	uc.queue.Enqueue(job.ID)
+
+	return dto.OutputImportJobDto{
+		ID:              job.ID,
+		Status:          string(job.Status),
+		FileName:        job.FileName,
+		Format:          job.Format,
+		Layout:          job.Layout,
+		Encoding:        job.Encoding,
+		Strict:          job.Strict,
+		DuplicatePolicy: job.DuplicatePolicy,
+		CreatedAt:       job.CreatedAt,
+	}, nil
+}
+
+func (uc *CreateImportJobUseCase) store(upload io.Reader) (string, error) {
+	if err := os.MkdirAll(uc.uploadDir, 0o755); err != nil {
+		return "", internalerrors.ErrInternal
+	}
+
+	file, err := os.CreateTemp(uc.uploadDir, "import-*")
+	if err != nil {
+		return "", internalerrors.ErrInternal
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, upload); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"errors"
+	"neoway_test/internal/domain/customer/service"
+	"neoway_test/internal/domain/importjob/dto"
+	"neoway_test/internal/domain/importjob/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type importJobQueueMock struct {
+	mock.Mock
+}
+
+func (q *importJobQueueMock) Enqueue(jobID string) {
+	q.Called(jobID)
+}
+
+func TestCreateImportJobUseCase_Success(t *testing.T) {
+	mockRepo := new(databaseRepository.ImportJobRepositoryMock)
+	mockQueue := new(importJobQueueMock)
+	uploadDir := t.TempDir()
+	createImportJobUseCase := NewCreateImportJobUseCase(mockRepo, mockQueue, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())), uploadDir)
+
+	var created *entity.ImportJob
+	mockRepo.On("Create", mock.AnythingOfType("*entity.ImportJob")).Run(func(args mock.Arguments) {
+		created = args.Get(0).(*entity.ImportJob)
+	}).Return(nil)
+	mockQueue.On("Enqueue", mock.AnythingOfType("string")).Return()
+
+	output, err := createImportJobUseCase.Execute(dto.InputCreateImportJobDto{
+		FileName: "base_teste.txt",
+		File:     strings.NewReader("file content"),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, created.ID, output.ID)
+	assert.Equal(t, "queued", output.Status)
+	assert.Equal(t, "base_teste.txt", output.FileName)
+	assert.Equal(t, "txt", output.Format)
+
+	content, err := os.ReadFile(created.FilePath)
+	assert.Nil(t, err)
+	assert.Equal(t, "file content", string(content))
+
+	mockQueue.AssertCalled(t, "Enqueue", created.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateImportJobUseCase_RepositoryError(t *testing.T) {
+	mockRepo := new(databaseRepository.ImportJobRepositoryMock)
+	mockQueue := new(importJobQueueMock)
+	uploadDir := t.TempDir()
+	createImportJobUseCase := NewCreateImportJobUseCase(mockRepo, mockQueue, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())), uploadDir)
+
+	mockRepo.On("Create", mock.AnythingOfType("*entity.ImportJob")).Return(errors.New("database error"))
+
+	output, err := createImportJobUseCase.Execute(dto.InputCreateImportJobDto{
+		FileName: "base_teste.txt",
+		File:     strings.NewReader("file content"),
+	})
+
+	assert.Empty(t, output)
+	assert.Equal(t, internalerrors.ErrInternal, err)
+
+	files, _ := os.ReadDir(uploadDir)
+	assert.Empty(t, files)
+	mockQueue.AssertNotCalled(t, "Enqueue", mock.Anything)
+}
+
+func TestCreateImportJobUseCase_UnknownLayout(t *testing.T) {
+	mockRepo := new(databaseRepository.ImportJobRepositoryMock)
+	mockQueue := new(importJobQueueMock)
+	createImportJobUseCase := NewCreateImportJobUseCase(mockRepo, mockQueue, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())), t.TempDir())
+
+	output, err := createImportJobUseCase.Execute(dto.InputCreateImportJobDto{
+		FileName: "base_teste.txt",
+		File:     strings.NewReader("file content"),
+		Layout:   "missing",
+	})
+
+	assert.Empty(t, output)
+	assert.True(t, errors.Is(err, service.ErrUnknownLayout))
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateImportJobUseCase_ResolvesFormatFromUpload(t *testing.T) {
+	mockRepo := new(databaseRepository.ImportJobRepositoryMock)
+	mockQueue := new(importJobQueueMock)
+	createImportJobUseCase := NewCreateImportJobUseCase(mockRepo, mockQueue, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())), t.TempDir())
+
+	mockRepo.On("Create", mock.MatchedBy(func(job *entity.ImportJob) bool { return job.Format == "ndjson" })).Return(nil)
+	mockQueue.On("Enqueue", mock.AnythingOfType("string")).Return()
+
+	output, err := createImportJobUseCase.Execute(dto.InputCreateImportJobDto{
+		FileName:    "base_teste.json",
+		ContentType: "application/x-ndjson",
+		File:        strings.NewReader(`{"Cpf": "026.987.379-13"}`),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "ndjson", output.Format)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateImportJobUseCase_KeepsCharsetFromContentType(t *testing.T) {
+	mockRepo := new(databaseRepository.ImportJobRepositoryMock)
+	mockQueue := new(importJobQueueMock)
+	createImportJobUseCase := NewCreateImportJobUseCase(mockRepo, mockQueue, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())), t.TempDir())
+
+	mockRepo.On("Create", mock.MatchedBy(func(job *entity.ImportJob) bool { return job.Encoding == "iso-8859-1" })).Return(nil)
+	mockQueue.On("Enqueue", mock.AnythingOfType("string")).Return()
+
+	output, err := createImportJobUseCase.Execute(dto.InputCreateImportJobDto{
+		FileName:    "base_teste.csv",
+		ContentType: "text/csv; charset=latin1",
+		File:        strings.NewReader("file content"),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "iso-8859-1", output.Encoding)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateImportJobUseCase_UnknownEncoding(t *testing.T) {
+	mockRepo := new(databaseRepository.ImportJobRepositoryMock)
+	mockQueue := new(importJobQueueMock)
+	createImportJobUseCase := NewCreateImportJobUseCase(mockRepo, mockQueue, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())), t.TempDir())
+
+	output, err := createImportJobUseCase.Execute(dto.InputCreateImportJobDto{
+		FileName: "base_teste.txt",
+		File:     strings.NewReader("file content"),
+		Encoding: "ebcdic",
+	})
+
+	assert.Empty(t, output)
+	assert.True(t, errors.Is(err, service.ErrUnknownEncoding))
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateImportJobUseCase_LeavesFormatOpenForArchives(t *testing.T) {
+	mockRepo := new(databaseRepository.ImportJobRepositoryMock)
+	mockQueue := new(importJobQueueMock)
+	createImportJobUseCase := NewCreateImportJobUseCase(mockRepo, mockQueue, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())), t.TempDir())
+
+	mockRepo.On("Create", mock.AnythingOfType("*entity.ImportJob")).Return(nil)
+	mockQueue.On("Enqueue", mock.AnythingOfType("string")).Return()
+
+	output, err := createImportJobUseCase.Execute(dto.InputCreateImportJobDto{
+		FileName:    "bases.zip",
+		ContentType: "application/zip",
+		File:        strings.NewReader("PK\x03\x04rest of the archive"),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "", output.Format)
+}
+
+func TestCreateImportJobUseCase_TakesOverStoredFile(t *testing.T) {
+	mockRepo := new(databaseRepository.ImportJobRepositoryMock)
+	mockQueue := new(importJobQueueMock)
+	uploadDir := t.TempDir()
+	createImportJobUseCase := NewCreateImportJobUseCase(mockRepo, mockQueue, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())), uploadDir)
+
+	storedPath := filepath.Join(uploadDir, "upload-1")
+	os.WriteFile(storedPath, []byte("file content"), 0o600)
+
+	var created *entity.ImportJob
+	mockRepo.On("Create", mock.AnythingOfType("*entity.ImportJob")).Run(func(args mock.Arguments) {
+		created = args.Get(0).(*entity.ImportJob)
+	}).Return(nil)
+	mockQueue.On("Enqueue", mock.AnythingOfType("string")).Return()
+
+	_, err := createImportJobUseCase.Execute(dto.InputCreateImportJobDto{
+		FileName: "base_teste.csv",
+		FilePath: storedPath,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, storedPath, created.FilePath)
+	assert.Equal(t, "csv", created.Format)
+	entries, _ := os.ReadDir(uploadDir)
+	assert.Len(t, entries, 1)
+}
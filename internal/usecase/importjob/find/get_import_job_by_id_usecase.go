@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"neoway_test/internal/domain/importjob/dto"
+	"neoway_test/internal/domain/importjob/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+)
+
+type GetImportJobByIdUseCase struct {
+	repo repository.ImportJobRepository
+}
+
+func NewGetImportJobByIdUseCase(repo repository.ImportJobRepository) *GetImportJobByIdUseCase {
+	return &GetImportJobByIdUseCase{repo: repo}
+}
+
+func (uc *GetImportJobByIdUseCase) Execute(input dto.InputGetImportJobByIdDto) (*dto.OutputImportJobDto, error) {
+	job, err := uc.repo.GetById(input.ID)
+
+	if err != nil {
+		return nil, internalerrors.ProcessErrorToReturn(err)
+	}
+
+	return &dto.OutputImportJobDto{
+		ID:              job.ID,
+		Status:          string(job.Status),
+		FileName:        job.FileName,
+		FileHash:        job.FileHash,
+		Format:          job.Format,
+		Layout:          job.Layout,
+		Encoding:        job.Encoding,
+		Strict:          job.Strict,
+		DuplicatePolicy: job.DuplicatePolicy,
+		RowsProcessed:   job.RowsProcessed,
+		RowsRejected:    job.RowsRejected,
+		Error:           job.Error,
+		Report:          job.Report,
+		CreatedAt:       job.CreatedAt,
+		StartedAt:       job.StartedAt,
+		FinishedAt:      job.FinishedAt,
+		HeartbeatAt:     job.HeartbeatAt,
+	}, nil
+}
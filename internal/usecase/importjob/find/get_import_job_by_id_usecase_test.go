@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"errors"
+	"neoway_test/internal/domain/importjob/dto"
+	"neoway_test/internal/domain/importjob/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestGetImportJobByIdUseCase_Success(t *testing.T) {
+	mockRepo := new(databaseRepository.ImportJobRepositoryMock)
+	getImportJobByIdUseCase := NewGetImportJobByIdUseCase(mockRepo)
+
+	job := entity.NewImportJob("base_teste.txt", "/tmp/import-1", "txt", "", "", false, "")
+	job.Start()
+	job.Progress(1000, 3)
+
+	input := dto.InputGetImportJobByIdDto{ID: job.ID}
+
+	mockRepo.On("GetById", input.ID).Return(job, nil)
+
+	output, err := getImportJobByIdUseCase.Execute(input)
+
+	assert.Nil(t, err)
+	assert.Equal(t, job.ID, output.ID)
+	assert.Equal(t, "running", output.Status)
+	assert.Equal(t, "base_teste.txt", output.FileName)
+	assert.Equal(t, 1000, output.RowsProcessed)
+	assert.Equal(t, 3, output.RowsRejected)
+	assert.Equal(t, job.StartedAt, output.StartedAt)
+	assert.Nil(t, output.FinishedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetImportJobByIdUseCase_NotFound(t *testing.T) {
+	mockRepo := new(databaseRepository.ImportJobRepositoryMock)
+	getImportJobByIdUseCase := NewGetImportJobByIdUseCase(mockRepo)
+
+	input := dto.InputGetImportJobByIdDto{ID: "job123"}
+
+	mockRepo.On("GetById", input.ID).Return(nil, gorm.ErrRecordNotFound)
+
+	output, err := getImportJobByIdUseCase.Execute(input)
+
+	assert.Nil(t, output)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
+
+func TestGetImportJobByIdUseCase_InternalError(t *testing.T) {
+	mockRepo := new(databaseRepository.ImportJobRepositoryMock)
+	getImportJobByIdUseCase := NewGetImportJobByIdUseCase(mockRepo)
+
+	input := dto.InputGetImportJobByIdDto{ID: "job123"}
+
+	mockRepo.On("GetById", input.ID).Return(nil, errors.New("database error"))
+
+	output, err := getImportJobByIdUseCase.Execute(input)
+
+	assert.Nil(t, output)
+	assert.Equal(t, internalerrors.ErrInternal, err)
+}
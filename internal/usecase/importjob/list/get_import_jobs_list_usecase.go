@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"neoway_test/internal/domain/importjob/dto"
+	"neoway_test/internal/domain/importjob/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+)
+
+type GetImportJobsListUseCase struct {
+	repo repository.ImportJobRepository
+}
+
+func NewGetImportJobsListUseCase(repo repository.ImportJobRepository) *GetImportJobsListUseCase {
+	return &GetImportJobsListUseCase{repo: repo}
+}
+
+// Execute lists jobs without their reports, which can be large; fetch a single
+// job to see its rejected lines.
+func (uc *GetImportJobsListUseCase) Execute(input dto.InputGetImportJobsListDto) ([]*dto.OutputImportJobDto, error) {
+	jobs, err := uc.repo.Get(input.Page)
+
+	if err != nil {
+		return nil, internalerrors.ProcessErrorToReturn(err)
+	}
+
+	var jobsDto []*dto.OutputImportJobDto
+	for _, job := range jobs {
+		jobsDto = append(jobsDto, &dto.OutputImportJobDto{
+			ID:              job.ID,
+			Status:          string(job.Status),
+			FileName:        job.FileName,
+			FileHash:        job.FileHash,
+			Format:          job.Format,
+			Layout:          job.Layout,
+			Encoding:        job.Encoding,
+			Strict:          job.Strict,
+			DuplicatePolicy: job.DuplicatePolicy,
+			RowsProcessed:   job.RowsProcessed,
+			RowsRejected:    job.RowsRejected,
+			Error:           job.Error,
+			CreatedAt:       job.CreatedAt,
+			StartedAt:       job.StartedAt,
+			FinishedAt:      job.FinishedAt,
+			HeartbeatAt:     job.HeartbeatAt,
+		})
+	}
+
+	return jobsDto, nil
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"neoway_test/internal/domain/importjob/dto"
+	"neoway_test/internal/domain/importjob/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetImportJobsListUseCase_Success(t *testing.T) {
+	mockRepo := new(databaseRepository.ImportJobRepositoryMock)
+	getImportJobsListUseCase := NewGetImportJobsListUseCase(mockRepo)
+
+	jobs := []*entity.ImportJob{
+		entity.NewImportJob("base_1.txt", "/tmp/import-1", "txt", "", "", false, ""),
+		entity.NewImportJob("base_2.txt", "/tmp/import-2", "txt", "", "", false, ""),
+	}
+
+	input := dto.InputGetImportJobsListDto{Page: 1}
+
+	mockRepo.On("Get", input.Page).Return(jobs, nil)
+
+	output, err := getImportJobsListUseCase.Execute(input)
+
+	assert.Nil(t, err)
+	assert.Len(t, output, 2)
+	assert.Equal(t, jobs[0].ID, output[0].ID)
+	assert.Equal(t, "base_2.txt", output[1].FileName)
+	assert.Equal(t, "queued", output[1].Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetImportJobsListUseCase_InternalError(t *testing.T) {
+	mockRepo := new(databaseRepository.ImportJobRepositoryMock)
+	getImportJobsListUseCase := NewGetImportJobsListUseCase(mockRepo)
+
+	input := dto.InputGetImportJobsListDto{Page: 1}
+
+	mockRepo.On("Get", input.Page).Return(nil, internalerrors.ErrInternal)
+
+	output, err := getImportJobsListUseCase.Execute(input)
+
+	assert.Nil(t, output)
+	assert.Equal(t, internalerrors.ErrInternal, err)
+}
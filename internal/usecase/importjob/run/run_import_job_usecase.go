@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	customerDto "neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/service"
+	"neoway_test/internal/domain/importjob/entity"
+	"neoway_test/internal/domain/importjob/repository"
+	usecaseCreate "neoway_test/internal/usecase/customer/create"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoQueuedJob is returned by RunNext when there is nothing to process.
+var ErrNoQueuedJob = errors.New("no queued import job")
+
+// ErrJobLost is returned by RunNext when the job stopped running before it
+// finished, e.g. because another instance took its lease as expired.
+var ErrJobLost = errors.New("import job is no longer running")
+
+const interruptedJobReason = "import interrupted: no progress reported for too long"
+
+// JobLease is how long a running job may go without a heartbeat before it is
+// taken as abandoned.
+const JobLease = 10 * time.Minute
+
+// heartbeatInterval is how often a running job renews its lease, whether or
+// not a batch was written in the meantime.
+const heartbeatInterval = JobLease / 10
+
+type RunImportJobUseCase struct {
+	repo                       repository.ImportJobRepository
+	createCustomersBulkUsecase *usecaseCreate.CreateCustomerBulkUseCase
+	now                        func() time.Time
+	heartbeatInterval          time.Duration
+}
+
+func NewRunImportJobUseCase(repo repository.ImportJobRepository, createCustomersBulkUsecase *usecaseCreate.CreateCustomerBulkUseCase) *RunImportJobUseCase {
+	return &RunImportJobUseCase{
+		repo:                       repo,
+		createCustomersBulkUsecase: createCustomersBulkUsecase,
+		now:                        time.Now,
+		heartbeatInterval:          heartbeatInterval,
+	}
+}
+
+// Recover fails the running jobs whose heartbeat is older than JobLease, since
+// the process running them stopped and their progress cannot be resumed. Jobs
+// of other live instances keep their heartbeat fresh and are left alone.
+func (uc *RunImportJobUseCase) Recover() error {
+	return uc.repo.FailStale(uc.now().Add(-JobLease), interruptedJobReason)
+}
+
+// RunNext claims the oldest queued job and imports its file through the bulk
+// use case, persisting progress after every batch and renewing its lease
+// until it finishes.
+func (uc *RunImportJobUseCase) RunNext() (*entity.ImportJob, error) {
+	job, err := uc.repo.ClaimNext()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNoQueuedJob
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.Remove(job.FilePath)
+
+	stop := uc.keepAlive(job.ID)
+	defer stop()
+
+	file, err := os.Open(job.FilePath)
+	if err != nil {
+		job.Fail(err)
+		return uc.finish(job)
+	}
+	defer file.Close()
+
+	job.FileHash, err = service.HashUpload(file)
+	if err != nil {
+		job.Fail(err)
+		return uc.finish(job)
+	}
+
+	// The job ID doubles as the import batch ID of every customer it writes.
+	report, err := uc.createCustomersBulkUsecase.Execute(customerDto.InputCreateCustomerBulkDto{
+		File:            file,
+		FileName:        job.FileName,
+		BatchID:         job.ID,
+		FileHash:        job.FileHash,
+		Format:          job.Format,
+		Layout:          job.Layout,
+		Encoding:        job.Encoding,
+		Strict:          job.Strict,
+		DuplicatePolicy: job.DuplicatePolicy,
+		Actor:           job.Actor,
+		RequestID:       job.RequestID,
+		OnProgress: func(processed int, rejected int) {
+			job.Progress(processed, rejected)
+			// A lost update only delays the progress shown; the import goes on.
+			if err := uc.repo.SaveProgress(job); err != nil {
+				log.Printf("import job %s: failed to save progress: %v", job.ID, err)
+			}
+		},
+	})
+
+	if err != nil {
+		job.Fail(err)
+	} else {
+		job.Succeed(report)
+	}
+
+	return uc.finish(job)
+}
+
+func (uc *RunImportJobUseCase) finish(job *entity.ImportJob) (*entity.ImportJob, error) {
+	finished, err := uc.repo.Finish(job)
+	if err != nil {
+		return job, err
+	}
+	if !finished {
+		return job, fmt.Errorf("%w: %s", ErrJobLost, job.ID)
+	}
+	return job, nil
+}
+
+// keepAlive renews the lease of the job every heartbeatInterval until the
+// returned function is called, so a slow batch or a long run of rejected
+// lines is not taken as a crash.
+func (uc *RunImportJobUseCase) keepAlive(id string) func() {
+	ticker := time.NewTicker(uc.heartbeatInterval)
+	done := make(chan struct{})
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := uc.repo.Heartbeat(id); err != nil {
+					log.Printf("import job %s: failed to renew lease: %v", id, err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	customerEntity "neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/service"
+	"neoway_test/internal/domain/importjob/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	usecaseCreate "neoway_test/internal/usecase/customer/create"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const fileContent = `CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA
+026.987.379-13     0           0           2011-01-20            159,31                159,31                  79.379.491/0001-83  79.379.491/0001-83
+041.091.641-25     0           1`
+
+func newClaimedJob(t *testing.T) *entity.ImportJob {
+	path := filepath.Join(t.TempDir(), "import-1")
+	os.WriteFile(path, []byte(fileContent), 0o644)
+
+	job := entity.NewImportJob("base_teste.txt", path, "txt", "", "", false, "")
+	job.Start()
+	return job
+}
+
+func TestRunImportJobUseCase_Success(t *testing.T) {
+	mockJobRepo := new(databaseRepository.ImportJobRepositoryMock)
+	mockCustomerRepo := new(databaseRepository.CustomerRepositoryMock)
+	bulkUseCase := usecaseCreate.NewCreateCustomersBulkUseCase(mockCustomerRepo, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())))
+	runImportJobUseCase := NewRunImportJobUseCase(mockJobRepo, bulkUseCase)
+
+	job := newClaimedJob(t)
+	job.RequestBy("alice", "host/abc-000001")
+	mockJobRepo.On("ClaimNext").Return(job, nil)
+	mockJobRepo.On("SaveProgress", job).Return(nil)
+	mockJobRepo.On("Finish", job).Return(true, nil)
+	var imported []*customerEntity.Customer
+	mockCustomerRepo.On("WithAudit", customerEntity.AuditInfo{Actor: "alice", RequestID: "host/abc-000001"}).Return(mockCustomerRepo)
+	mockCustomerRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Run(func(args mock.Arguments) {
+		imported = args.Get(0).([]*customerEntity.Customer)
+	}).Return(0, 0, 0, nil)
+
+	result, err := runImportJobUseCase.RunNext()
+
+	hash := sha256.Sum256([]byte(fileContent))
+	assert.Nil(t, err)
+	assert.Equal(t, entity.ImportJobSucceeded, result.Status)
+	assert.Equal(t, hex.EncodeToString(hash[:]), result.FileHash)
+	assert.Equal(t, job.ID, result.Report.BatchID)
+	assert.Len(t, imported, 1)
+	assert.Equal(t, job.ID, imported[0].ImportBatchID)
+	assert.Equal(t, "base_teste.txt", imported[0].SourceFileName)
+	assert.Equal(t, result.FileHash, imported[0].SourceFileHash)
+	assert.Equal(t, 2, imported[0].SourceLineNumber)
+	assert.Equal(t, 2, result.RowsProcessed)
+	assert.Equal(t, 1, result.RowsRejected)
+	assert.Equal(t, 1, result.Report.Accepted)
+	assert.NotNil(t, result.FinishedAt)
+
+	_, statErr := os.Stat(job.FilePath)
+	assert.True(t, os.IsNotExist(statErr))
+	mockJobRepo.AssertExpectations(t)
+	mockCustomerRepo.AssertExpectations(t)
+}
+
+func TestRunImportJobUseCase_ImportFails(t *testing.T) {
+	mockJobRepo := new(databaseRepository.ImportJobRepositoryMock)
+	mockCustomerRepo := new(databaseRepository.CustomerRepositoryMock)
+	bulkUseCase := usecaseCreate.NewCreateCustomersBulkUseCase(mockCustomerRepo, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())))
+	runImportJobUseCase := NewRunImportJobUseCase(mockJobRepo, bulkUseCase)
+
+	job := newClaimedJob(t)
+	mockJobRepo.On("ClaimNext").Return(job, nil)
+	mockJobRepo.On("Finish", job).Return(true, nil)
+	mockCustomerRepo.On("WithAudit", mock.Anything).Return(mockCustomerRepo)
+	mockCustomerRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Return(0, 0, 0, errors.New("database error"))
+
+	result, err := runImportJobUseCase.RunNext()
+
+	assert.Nil(t, err)
+	assert.Equal(t, entity.ImportJobFailed, result.Status)
+	assert.Equal(t, "internal server error", result.Error)
+	mockJobRepo.AssertExpectations(t)
+}
+
+func TestRunImportJobUseCase_RenewsLeaseDuringSlowBatch(t *testing.T) {
+	mockJobRepo := new(databaseRepository.ImportJobRepositoryMock)
+	mockCustomerRepo := new(databaseRepository.CustomerRepositoryMock)
+	bulkUseCase := usecaseCreate.NewCreateCustomersBulkUseCase(mockCustomerRepo, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())))
+	runImportJobUseCase := NewRunImportJobUseCase(mockJobRepo, bulkUseCase)
+	runImportJobUseCase.heartbeatInterval = time.Millisecond
+
+	job := newClaimedJob(t)
+	mockJobRepo.On("ClaimNext").Return(job, nil)
+	mockJobRepo.On("Heartbeat", job.ID).Return(nil)
+	mockJobRepo.On("SaveProgress", job).Return(nil)
+	mockJobRepo.On("Finish", job).Return(true, nil)
+	mockCustomerRepo.On("WithAudit", mock.Anything).Return(mockCustomerRepo)
+	mockCustomerRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Run(func(args mock.Arguments) {
+		time.Sleep(20 * time.Millisecond)
+	}).Return(1, 0, 0, nil)
+
+	_, err := runImportJobUseCase.RunNext()
+
+	assert.Nil(t, err)
+	mockJobRepo.AssertCalled(t, "Heartbeat", job.ID)
+}
+
+func TestRunImportJobUseCase_JobLost(t *testing.T) {
+	mockJobRepo := new(databaseRepository.ImportJobRepositoryMock)
+	mockCustomerRepo := new(databaseRepository.CustomerRepositoryMock)
+	bulkUseCase := usecaseCreate.NewCreateCustomersBulkUseCase(mockCustomerRepo, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())))
+	runImportJobUseCase := NewRunImportJobUseCase(mockJobRepo, bulkUseCase)
+
+	job := newClaimedJob(t)
+	mockJobRepo.On("ClaimNext").Return(job, nil)
+	mockJobRepo.On("SaveProgress", job).Return(nil)
+	mockJobRepo.On("Finish", job).Return(false, nil)
+	mockCustomerRepo.On("WithAudit", mock.Anything).Return(mockCustomerRepo)
+	mockCustomerRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Return(1, 0, 0, nil)
+
+	_, err := runImportJobUseCase.RunNext()
+
+	assert.True(t, errors.Is(err, ErrJobLost))
+}
+
+func TestRunImportJobUseCase_NoQueuedJob(t *testing.T) {
+	mockJobRepo := new(databaseRepository.ImportJobRepositoryMock)
+	runImportJobUseCase := NewRunImportJobUseCase(mockJobRepo, nil)
+
+	mockJobRepo.On("ClaimNext").Return(nil, gorm.ErrRecordNotFound)
+
+	result, err := runImportJobUseCase.RunNext()
+
+	assert.Nil(t, result)
+	assert.Equal(t, ErrNoQueuedJob, err)
+}
+
+func TestRunImportJobUseCase_Recover(t *testing.T) {
+	mockJobRepo := new(databaseRepository.ImportJobRepositoryMock)
+	runImportJobUseCase := NewRunImportJobUseCase(mockJobRepo, nil)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	runImportJobUseCase.now = func() time.Time { return now }
+
+	mockJobRepo.On("FailStale", now.Add(-JobLease), "import interrupted: no progress reported for too long").Return(nil)
+
+	err := runImportJobUseCase.Recover()
+
+	assert.Nil(t, err)
+	mockJobRepo.AssertExpectations(t)
+}
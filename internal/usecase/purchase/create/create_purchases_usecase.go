@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	customerEntity "neoway_test/internal/domain/customer/entity"
+	customerRepository "neoway_test/internal/domain/customer/repository"
+	"neoway_test/internal/domain/purchase/dto"
+	"neoway_test/internal/domain/purchase/entity"
+	"neoway_test/internal/domain/purchase/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrNoPurchases = errors.New("no purchases given")
+
+type CreatePurchasesUseCase struct {
+	purchaseRepo repository.PurchaseRepository
+	customerRepo customerRepository.CustomerRepository
+}
+
+func NewCreatePurchasesUseCase(purchaseRepo repository.PurchaseRepository, customerRepo customerRepository.CustomerRepository) *CreatePurchasesUseCase {
+	return &CreatePurchasesUseCase{purchaseRepo: purchaseRepo, customerRepo: customerRepo}
+}
+
+// Execute records the valid purchases together and reports the ones that were
+// rejected, such as those of customers that do not exist. Recording them
+// derives again the tickets, last purchase and stores of their customers.
+func (uc *CreatePurchasesUseCase) Execute(input dto.InputCreatePurchasesDto) (dto.OutputCreatePurchasesDto, error) {
+	if len(input.Purchases) == 0 {
+		return dto.OutputCreatePurchasesDto{}, ErrNoPurchases
+	}
+
+	output := dto.OutputCreatePurchasesDto{RejectedPurchases: []dto.RejectedPurchaseDto{}}
+	reject := func(index int, reason string) {
+		output.RejectedPurchases = append(output.RejectedPurchases, dto.RejectedPurchaseDto{Index: index, Reason: reason})
+	}
+
+	customerIDs := make(map[string]string)
+	var purchases []*entity.Purchase
+	for i, item := range input.Purchases {
+		cpf := customerEntity.NewCpf(item.Cpf).Digits()
+		if cpf == "" {
+			reject(i, "customer cpf is required")
+			continue
+		}
+
+		customerID, ok := customerIDs[cpf]
+		if !ok {
+			customer, err := uc.customerRepo.GetByCpf(cpf)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				customerIDs[cpf] = ""
+			} else if err != nil {
+				return dto.OutputCreatePurchasesDto{}, internalerrors.ErrInternal
+			} else {
+				customerIDs[cpf] = customer.ID
+			}
+			customerID = customerIDs[cpf]
+		}
+		if customerID == "" {
+			reject(i, fmt.Sprintf("customer %s not found", cpf))
+			continue
+		}
+
+		data, err := time.Parse("2006-01-02", item.Data)
+		if err != nil {
+			reject(i, fmt.Sprintf("invalid date %q, expected YYYY-MM-DD", item.Data))
+			continue
+		}
+
+		purchase, err := entity.NewPurchase(customerID, item.Loja, data, item.Valor)
+		if err != nil {
+			reject(i, err.Error())
+			continue
+		}
+		purchases = append(purchases, purchase)
+	}
+
+	if len(purchases) > 0 {
+		repo := uc.purchaseRepo.WithAudit(customerEntity.AuditInfo{Actor: input.Actor, RequestID: input.RequestID})
+		if err := repo.CreateBulk(purchases); err != nil {
+			return dto.OutputCreatePurchasesDto{}, internalerrors.ErrInternal
+		}
+	}
+
+	output.Accepted = len(purchases)
+	output.Rejected = len(output.RejectedPurchases)
+	return output, nil
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"errors"
+	customerEntity "neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/purchase/dto"
+	"neoway_test/internal/domain/purchase/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCreatePurchasesUseCase_Success(t *testing.T) {
+	purchaseRepo := new(databaseRepository.PurchaseRepositoryMock)
+	customerRepo := new(databaseRepository.CustomerRepositoryMock)
+	createPurchasesUseCase := NewCreatePurchasesUseCase(purchaseRepo, customerRepo)
+
+	customer, _ := customerEntity.NewCustomer("922.488.109-20", false, false, nil, 0, 0, "NULL", "NULL")
+
+	input := dto.InputCreatePurchasesDto{Actor: "maria", RequestID: "req-1", Purchases: []dto.InputCreatePurchaseDto{
+		{Cpf: "922.488.109-20", Loja: "79.379.491/0001-83", Data: "2024-01-10", Valor: 100},
+		{Cpf: "92248810920", Loja: "79379491000183", Data: "2024-02-10", Valor: 50.555},
+		{Cpf: "041.091.641-25", Loja: "79379491000183", Data: "2024-02-10", Valor: 10},
+		{Cpf: "922.488.109-20", Loja: "79379491000183", Data: "10/02/2024", Valor: 10},
+		{Cpf: "922.488.109-20", Loja: "79379491000183", Data: "2024-02-10", Valor: 0},
+	}}
+
+	customerRepo.On("GetByCpf", "92248810920").Return(customer, nil).Once()
+	customerRepo.On("GetByCpf", "04109164125").Return(nil, gorm.ErrRecordNotFound).Once()
+	purchaseRepo.On("WithAudit", customerEntity.AuditInfo{Actor: "maria", RequestID: "req-1"}).Return(purchaseRepo)
+	purchaseRepo.On("CreateBulk", mock.MatchedBy(func(purchases []*entity.Purchase) bool {
+		return len(purchases) == 2 &&
+			purchases[0].CustomerID == customer.ID &&
+			purchases[0].LojaCnpj == "79379491000183" &&
+			purchases[1].Valor == 50.56
+	})).Return(nil)
+
+	output, err := createPurchasesUseCase.Execute(input)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, output.Accepted)
+	assert.Equal(t, 3, output.Rejected)
+	assert.Equal(t, []int{2, 3, 4}, []int{output.RejectedPurchases[0].Index, output.RejectedPurchases[1].Index, output.RejectedPurchases[2].Index})
+	assert.Equal(t, "customer 04109164125 not found", output.RejectedPurchases[0].Reason)
+	assert.Equal(t, entity.ErrPurchaseInvalidAmount.Error(), output.RejectedPurchases[2].Reason)
+	purchaseRepo.AssertExpectations(t)
+	customerRepo.AssertExpectations(t)
+}
+
+func TestCreatePurchasesUseCase_AllRejected(t *testing.T) {
+	purchaseRepo := new(databaseRepository.PurchaseRepositoryMock)
+	customerRepo := new(databaseRepository.CustomerRepositoryMock)
+	createPurchasesUseCase := NewCreatePurchasesUseCase(purchaseRepo, customerRepo)
+
+	customerRepo.On("GetByCpf", "04109164125").Return(nil, gorm.ErrRecordNotFound)
+
+	output, err := createPurchasesUseCase.Execute(dto.InputCreatePurchasesDto{Purchases: []dto.InputCreatePurchaseDto{
+		{Cpf: "041.091.641-25", Loja: "79379491000183", Data: "2024-02-10", Valor: 10},
+		{Loja: "79379491000183", Data: "2024-02-10", Valor: 10},
+	}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, output.Accepted)
+	assert.Equal(t, 2, output.Rejected)
+	purchaseRepo.AssertNotCalled(t, "CreateBulk")
+}
+
+func TestCreatePurchasesUseCase_NoPurchases(t *testing.T) {
+	createPurchasesUseCase := NewCreatePurchasesUseCase(new(databaseRepository.PurchaseRepositoryMock), new(databaseRepository.CustomerRepositoryMock))
+
+	_, err := createPurchasesUseCase.Execute(dto.InputCreatePurchasesDto{})
+
+	assert.True(t, errors.Is(err, ErrNoPurchases))
+}
+
+func TestCreatePurchasesUseCase_RepositoryError(t *testing.T) {
+	purchaseRepo := new(databaseRepository.PurchaseRepositoryMock)
+	customerRepo := new(databaseRepository.CustomerRepositoryMock)
+	createPurchasesUseCase := NewCreatePurchasesUseCase(purchaseRepo, customerRepo)
+
+	customer, _ := customerEntity.NewCustomer("922.488.109-20", false, false, nil, 0, 0, "NULL", "NULL")
+	customerRepo.On("GetByCpf", "92248810920").Return(customer, nil)
+	purchaseRepo.On("WithAudit", mock.Anything).Return(purchaseRepo)
+	purchaseRepo.On("CreateBulk", mock.Anything).Return(errors.New("database error"))
+
+	_, err := createPurchasesUseCase.Execute(dto.InputCreatePurchasesDto{Purchases: []dto.InputCreatePurchaseDto{
+		{Cpf: "922.488.109-20", Loja: "79379491000183", Data: "2024-02-10", Valor: 10},
+	}})
+
+	assert.Equal(t, internalerrors.ErrInternal, err)
+}
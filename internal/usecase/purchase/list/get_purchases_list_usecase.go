@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	customerEntity "neoway_test/internal/domain/customer/entity"
+	customerRepository "neoway_test/internal/domain/customer/repository"
+	"neoway_test/internal/domain/purchase/dto"
+	"neoway_test/internal/domain/purchase/repository"
+	storeEntity "neoway_test/internal/domain/store/entity"
+	internalerrors "neoway_test/internal/internal-errors"
+	"time"
+)
+
+var ErrInvalidPurchaseDate = errors.New("invalid purchase date, expected YYYY-MM-DD")
+
+type GetPurchasesListUseCase struct {
+	purchaseRepo repository.PurchaseRepository
+	customerRepo customerRepository.CustomerRepository
+}
+
+func NewGetPurchasesListUseCase(purchaseRepo repository.PurchaseRepository, customerRepo customerRepository.CustomerRepository) *GetPurchasesListUseCase {
+	return &GetPurchasesListUseCase{purchaseRepo: purchaseRepo, customerRepo: customerRepo}
+}
+
+// Execute lists purchases, most recent first, failing with
+// gorm.ErrRecordNotFound when the CPF filter names no customer.
+func (uc *GetPurchasesListUseCase) Execute(input dto.InputGetPurchasesListDto) ([]*dto.OutputGetPurchaseDto, error) {
+	filter := repository.PurchaseFilter{LojaCnpj: storeEntity.NormalizeCnpj(input.Cnpj)}
+
+	from, err := parseDate(input.From)
+	if err != nil {
+		return nil, err
+	}
+	filter.From = from
+
+	to, err := parseDate(input.To)
+	if err != nil {
+		return nil, err
+	}
+	if to != nil {
+		// The range includes the whole last day.
+		end := to.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+
+	if input.Cpf != "" {
+		customer, err := uc.customerRepo.GetByCpf(customerEntity.NewCpf(input.Cpf).Digits())
+		if err != nil {
+			return nil, internalerrors.ProcessErrorToReturn(err)
+		}
+		filter.CustomerID = customer.ID
+	}
+
+	purchases, err := uc.purchaseRepo.Find(filter, input.Page)
+	if err != nil {
+		return nil, internalerrors.ProcessErrorToReturn(err)
+	}
+
+	var purchasesDto []*dto.OutputGetPurchaseDto
+	for _, purchase := range purchases {
+		purchasesDto = append(purchasesDto, &dto.OutputGetPurchaseDto{
+			ID:         purchase.ID,
+			CustomerID: purchase.CustomerID,
+			LojaCnpj:   purchase.LojaCnpj,
+			Data:       purchase.Data.UTC().Format("2006-01-02"),
+			Valor:      purchase.Valor,
+			CreatedAt:  purchase.CreatedAt,
+		})
+	}
+
+	return purchasesDto, nil
+}
+
+// parseDate reads an optional YYYY-MM-DD date.
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPurchaseDate, value)
+	}
+	return &date, nil
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"errors"
+	customerEntity "neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/purchase/dto"
+	"neoway_test/internal/domain/purchase/entity"
+	"neoway_test/internal/domain/purchase/repository"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestGetPurchasesListUseCase_Success(t *testing.T) {
+	purchaseRepo := new(databaseRepository.PurchaseRepositoryMock)
+	customerRepo := new(databaseRepository.CustomerRepositoryMock)
+	getPurchasesListUseCase := NewGetPurchasesListUseCase(purchaseRepo, customerRepo)
+
+	customer, _ := customerEntity.NewCustomer("922.488.109-20", false, false, nil, 0, 0, "NULL", "NULL")
+	purchase, _ := entity.NewPurchase(customer.ID, "79.379.491/0001-83", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), 10)
+
+	input := dto.InputGetPurchasesListDto{Cpf: "922.488.109-20", Cnpj: "79.379.491/0001-83", From: "2024-02-01", To: "2024-02-10", Page: 1}
+
+	customerRepo.On("GetByCpf", "92248810920").Return(customer, nil)
+	purchaseRepo.On("Find", mock.MatchedBy(func(filter repository.PurchaseFilter) bool {
+		return filter.CustomerID == customer.ID &&
+			filter.LojaCnpj == "79379491000183" &&
+			filter.From.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) &&
+			filter.To.Equal(time.Date(2024, 2, 11, 0, 0, 0, 0, time.UTC))
+	}), 1).Return([]*entity.Purchase{purchase}, nil)
+
+	output, err := getPurchasesListUseCase.Execute(input)
+
+	assert.Nil(t, err)
+	assert.Len(t, output, 1)
+	assert.Equal(t, purchase.ID, output[0].ID)
+	assert.Equal(t, "2024-02-10", output[0].Data)
+	assert.Equal(t, 10.0, output[0].Valor)
+	purchaseRepo.AssertExpectations(t)
+	customerRepo.AssertExpectations(t)
+}
+
+func TestGetPurchasesListUseCase_CustomerNotFound(t *testing.T) {
+	purchaseRepo := new(databaseRepository.PurchaseRepositoryMock)
+	customerRepo := new(databaseRepository.CustomerRepositoryMock)
+	getPurchasesListUseCase := NewGetPurchasesListUseCase(purchaseRepo, customerRepo)
+
+	customerRepo.On("GetByCpf", "04109164125").Return(nil, gorm.ErrRecordNotFound)
+
+	output, err := getPurchasesListUseCase.Execute(dto.InputGetPurchasesListDto{Cpf: "041.091.641-25", Page: 1})
+
+	assert.Nil(t, output)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+	purchaseRepo.AssertNotCalled(t, "Find")
+}
+
+func TestGetPurchasesListUseCase_InvalidDate(t *testing.T) {
+	getPurchasesListUseCase := NewGetPurchasesListUseCase(new(databaseRepository.PurchaseRepositoryMock), new(databaseRepository.CustomerRepositoryMock))
+
+	output, err := getPurchasesListUseCase.Execute(dto.InputGetPurchasesListDto{From: "01/02/2024"})
+
+	assert.Nil(t, output)
+	assert.True(t, errors.Is(err, ErrInvalidPurchaseDate))
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	customerDto "neoway_test/internal/domain/customer/dto"
+	customerEntity "neoway_test/internal/domain/customer/entity"
+	customerRepository "neoway_test/internal/domain/customer/repository"
+	"neoway_test/internal/domain/store/dto"
+	"neoway_test/internal/domain/store/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+)
+
+var ErrUnknownStoreRelation = errors.New("unknown store relation")
+
+type GetStoreCustomersUseCase struct {
+	storeRepo    repository.StoreRepository
+	customerRepo customerRepository.CustomerRepository
+}
+
+func NewGetStoreCustomersUseCase(storeRepo repository.StoreRepository, customerRepo customerRepository.CustomerRepository) *GetStoreCustomersUseCase {
+	return &GetStoreCustomersUseCase{storeRepo: storeRepo, customerRepo: customerRepo}
+}
+
+// Execute lists the customers of a store, failing with gorm.ErrRecordNotFound
+// when the store does not exist.
+func (uc *GetStoreCustomersUseCase) Execute(input dto.InputGetStoreCustomersDto) ([]*customerDto.OutputGetCustomersListDto, error) {
+	switch input.Relation {
+	case "", customerRepository.StoreRelationMostFrequent, customerRepository.StoreRelationLastPurchase:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownStoreRelation, input.Relation)
+	}
+	if err := customerEntity.ValidateCpfFormat(input.CpfFormat); err != nil {
+		return nil, err
+	}
+
+	store, err := uc.storeRepo.GetByCnpj(input.Cnpj)
+	if err != nil {
+		return nil, internalerrors.ProcessErrorToReturn(err)
+	}
+
+	customers, err := uc.customerRepo.GetByStore(store.Cnpj, input.Relation, input.Page)
+	if err != nil {
+		return nil, internalerrors.ProcessErrorToReturn(err)
+	}
+
+	var customersDto []*customerDto.OutputGetCustomersListDto
+	for _, customer := range customers {
+		cpf, err := customer.RenderCpf(input.CpfFormat)
+		if err != nil {
+			return nil, err
+		}
+		customersDto = append(customersDto, &customerDto.OutputGetCustomersListDto{
+			ID:                          customer.ID,
+			Cpf:                         cpf,
+			CpfValido:                   customer.CpfValido,
+			Private:                     customer.Private,
+			Incompleto:                  customer.Incompleto,
+			DataUltimaCompra:            customer.DataUltimaCompra,
+			TicketMedio:                 customer.TicketMedio,
+			TicketUltimaCompra:          customer.TicketUltimaCompra,
+			LojaMaisFrequente:           customer.LojaMaisFrequente,
+			CnpjLojaMaisFrequenteValido: customer.CnpjLojaMaisFrequenteValido,
+			LojaUltimaCompra:            customer.LojaUltimaCompra,
+			CnpjLojaUltimaCompraValido:  customer.CnpjLojaUltimaCompraValido,
+			ImportBatchID:               customer.ImportBatchID,
+			SourceFileName:              customer.SourceFileName,
+			SourceFileHash:              customer.SourceFileHash,
+			SourceLineNumber:            customer.SourceLineNumber,
+			CreatedAt:                   customer.CreatedAt,
+		})
+	}
+
+	return customersDto, nil
+}
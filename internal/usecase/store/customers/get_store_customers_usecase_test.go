@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"errors"
+	customerEntity "neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/store/dto"
+	"neoway_test/internal/domain/store/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestGetStoreCustomersUseCase_Success(t *testing.T) {
+	storeRepo := new(databaseRepository.StoreRepositoryMock)
+	customerRepo := new(databaseRepository.CustomerRepositoryMock)
+	getStoreCustomersUseCase := NewGetStoreCustomersUseCase(storeRepo, customerRepo)
+
+	store := entity.NewStore("79.379.491/0001-83")
+	customer, _ := customerEntity.NewCustomer("922.488.109-20", false, false, nil, 10, 10, "79.379.491/0001-83", "NULL")
+
+	input := dto.InputGetStoreCustomersDto{Cnpj: "79.379.491/0001-83", Relation: "most_frequent", Page: 1, CpfFormat: "masked"}
+
+	storeRepo.On("GetByCnpj", input.Cnpj).Return(store, nil)
+	customerRepo.On("GetByStore", "79379491000183", "most_frequent", 1).Return([]*customerEntity.Customer{customer}, nil)
+
+	output, err := getStoreCustomersUseCase.Execute(input)
+
+	assert.Nil(t, err)
+	assert.Len(t, output, 1)
+	assert.Equal(t, customer.ID, output[0].ID)
+	assert.Equal(t, "***.488.109-**", output[0].Cpf)
+	assert.Equal(t, "79.379.491/0001-83", output[0].LojaMaisFrequente)
+	storeRepo.AssertExpectations(t)
+	customerRepo.AssertExpectations(t)
+}
+
+func TestGetStoreCustomersUseCase_StoreNotFound(t *testing.T) {
+	storeRepo := new(databaseRepository.StoreRepositoryMock)
+	customerRepo := new(databaseRepository.CustomerRepositoryMock)
+	getStoreCustomersUseCase := NewGetStoreCustomersUseCase(storeRepo, customerRepo)
+
+	input := dto.InputGetStoreCustomersDto{Cnpj: "12.312.312/3123-12", Page: 1}
+
+	storeRepo.On("GetByCnpj", input.Cnpj).Return(nil, gorm.ErrRecordNotFound)
+
+	output, err := getStoreCustomersUseCase.Execute(input)
+
+	assert.Nil(t, output)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+	customerRepo.AssertNotCalled(t, "GetByStore")
+}
+
+func TestGetStoreCustomersUseCase_UnknownRelation(t *testing.T) {
+	getStoreCustomersUseCase := NewGetStoreCustomersUseCase(new(databaseRepository.StoreRepositoryMock), new(databaseRepository.CustomerRepositoryMock))
+
+	output, err := getStoreCustomersUseCase.Execute(dto.InputGetStoreCustomersDto{Cnpj: "79379491000183", Relation: "favorite"})
+
+	assert.Nil(t, output)
+	assert.True(t, errors.Is(err, ErrUnknownStoreRelation))
+}
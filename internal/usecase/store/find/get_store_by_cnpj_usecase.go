@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"neoway_test/internal/domain/store/dto"
+	"neoway_test/internal/domain/store/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+)
+
+type GetStoreByCnpjUseCase struct {
+	repo repository.StoreRepository
+}
+
+func NewGetStoreByCnpjUseCase(repo repository.StoreRepository) *GetStoreByCnpjUseCase {
+	return &GetStoreByCnpjUseCase{repo: repo}
+}
+
+func (uc *GetStoreByCnpjUseCase) Execute(input dto.InputGetStoreByCnpjDto) (*dto.OutputGetStoreDto, error) {
+	store, err := uc.repo.GetByCnpj(input.Cnpj)
+
+	if err != nil {
+		return nil, internalerrors.ProcessErrorToReturn(err)
+	}
+
+	return &dto.OutputGetStoreDto{
+		ID:         store.ID,
+		Cnpj:       store.Cnpj,
+		CnpjValido: store.CnpjValido,
+		Nome:       store.Nome,
+		Metadata:   store.Metadata,
+		CreatedAt:  store.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"neoway_test/internal/domain/store/dto"
+	"neoway_test/internal/domain/store/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestGetStoreByCnpjUseCase_Success(t *testing.T) {
+	mockRepo := new(databaseRepository.StoreRepositoryMock)
+	getStoreByCnpjUseCase := NewGetStoreByCnpjUseCase(mockRepo)
+
+	store := entity.NewStore("79.379.491/0001-83")
+	store.Metadata = map[string]string{"cidade": "Florianópolis"}
+
+	input := dto.InputGetStoreByCnpjDto{Cnpj: "79.379.491/0001-83"}
+
+	mockRepo.On("GetByCnpj", input.Cnpj).Return(store, nil)
+
+	output, err := getStoreByCnpjUseCase.Execute(input)
+
+	assert.Nil(t, err)
+	assert.Equal(t, store.ID, output.ID)
+	assert.Equal(t, "79379491000183", output.Cnpj)
+	assert.True(t, output.CnpjValido)
+	assert.Equal(t, "Florianópolis", output.Metadata["cidade"])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetStoreByCnpjUseCase_NotFound(t *testing.T) {
+	mockRepo := new(databaseRepository.StoreRepositoryMock)
+	getStoreByCnpjUseCase := NewGetStoreByCnpjUseCase(mockRepo)
+
+	input := dto.InputGetStoreByCnpjDto{Cnpj: "12.312.312/3123-12"}
+
+	mockRepo.On("GetByCnpj", input.Cnpj).Return(nil, gorm.ErrRecordNotFound)
+
+	output, err := getStoreByCnpjUseCase.Execute(input)
+
+	assert.Nil(t, output)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"neoway_test/internal/domain/store/dto"
+	"neoway_test/internal/domain/store/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+)
+
+type GetStoresListUseCase struct {
+	repo repository.StoreRepository
+}
+
+func NewGetStoresListUseCase(repo repository.StoreRepository) *GetStoresListUseCase {
+	return &GetStoresListUseCase{repo: repo}
+}
+
+func (uc *GetStoresListUseCase) Execute(input dto.InputGetStoresListDto) ([]*dto.OutputGetStoreDto, error) {
+	stores, err := uc.repo.Get(input.Page)
+
+	if err != nil {
+		return nil, internalerrors.ProcessErrorToReturn(err)
+	}
+
+	var storesDto []*dto.OutputGetStoreDto
+	for _, store := range stores {
+		storesDto = append(storesDto, &dto.OutputGetStoreDto{
+			ID:         store.ID,
+			Cnpj:       store.Cnpj,
+			CnpjValido: store.CnpjValido,
+			Nome:       store.Nome,
+			Metadata:   store.Metadata,
+			CreatedAt:  store.CreatedAt,
+		})
+	}
+
+	return storesDto, nil
+}
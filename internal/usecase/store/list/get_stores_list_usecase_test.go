@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"neoway_test/internal/domain/store/dto"
+	"neoway_test/internal/domain/store/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStoresListUseCase_Success(t *testing.T) {
+	mockRepo := new(databaseRepository.StoreRepositoryMock)
+	getStoresListUseCase := NewGetStoresListUseCase(mockRepo)
+
+	named := entity.NewStore("79.379.491/0001-83")
+	named.Nome = "LOJA CENTRO"
+	stores := []*entity.Store{named, entity.NewStore("12.312.312/3123-12")}
+
+	input := dto.InputGetStoresListDto{Page: 1}
+
+	mockRepo.On("Get", input.Page).Return(stores, nil)
+
+	output, err := getStoresListUseCase.Execute(input)
+
+	assert.Nil(t, err)
+	assert.Len(t, output, 2)
+	assert.Equal(t, named.ID, output[0].ID)
+	assert.Equal(t, "79379491000183", output[0].Cnpj)
+	assert.Equal(t, "LOJA CENTRO", output[0].Nome)
+	assert.True(t, output[0].CnpjValido)
+	assert.False(t, output[1].CnpjValido)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetStoresListUseCase_InternalError(t *testing.T) {
+	mockRepo := new(databaseRepository.StoreRepositoryMock)
+	getStoresListUseCase := NewGetStoresListUseCase(mockRepo)
+
+	input := dto.InputGetStoresListDto{Page: 1}
+
+	mockRepo.On("Get", input.Page).Return(nil, internalerrors.ErrInternal)
+
+	output, err := getStoresListUseCase.Execute(input)
+
+	assert.Nil(t, output)
+	assert.Equal(t, internalerrors.ErrInternal, err)
+}
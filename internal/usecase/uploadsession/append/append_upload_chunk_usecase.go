@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"io"
+	"neoway_test/internal/domain/uploadsession/dto"
+	"neoway_test/internal/domain/uploadsession/entity"
+	"neoway_test/internal/domain/uploadsession/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"os"
+
+	"github.com/rs/xid"
+)
+
+type AppendUploadChunkUseCase struct {
+	repo repository.UploadSessionRepository
+}
+
+func NewAppendUploadChunkUseCase(repo repository.UploadSessionRepository) *AppendUploadChunkUseCase {
+	return &AppendUploadChunkUseCase{repo: repo}
+}
+
+// Execute writes the chunk at the session's current offset. When the chunk is
+// cut short, for example by a dropped connection, the bytes that did arrive
+// are kept and the client resumes from the offset the session reports. The
+// session is claimed before the chunk is read, so a concurrent chunk for the
+// same session fails with a conflict instead of writing over it, and the new
+// offset is committed only if the claim still holds. No transaction stays
+// open while the chunk arrives.
+func (uc *AppendUploadChunkUseCase) Execute(input dto.InputAppendUploadChunkDto) (dto.OutputUploadSessionDto, error) {
+	session, err := uc.repo.GetById(input.ID)
+	if err != nil {
+		return dto.OutputUploadSessionDto{}, internalerrors.ProcessErrorToReturn(err)
+	}
+	if err := session.CheckOffset(input.Offset); err != nil {
+		return dto.OutputUploadSessionDto{}, err
+	}
+
+	token := xid.New().String()
+	claimed, err := uc.repo.ClaimChunk(session, token)
+	if err != nil {
+		return dto.OutputUploadSessionDto{}, internalerrors.ErrInternal
+	}
+	if !claimed {
+		return dto.OutputUploadSessionDto{}, entity.ErrUploadBusy
+	}
+
+	offset := session.ReceivedBytes
+	written, writeErr := uc.write(session, input.Chunk)
+	if written < 0 {
+		// Nothing was stored; committing the same offset releases the claim.
+		written, writeErr = 0, internalerrors.ErrInternal
+	}
+	if err := session.Receive(written); err != nil {
+		return dto.OutputUploadSessionDto{}, err
+	}
+
+	committed, err := uc.repo.CommitChunk(session, token, offset)
+	if err != nil {
+		return dto.OutputUploadSessionDto{}, internalerrors.ErrInternal
+	}
+	if !committed {
+		// Another request took the session over after ChunkLease.
+		return dto.OutputUploadSessionDto{}, entity.ErrUploadBusy
+	}
+	if writeErr != nil {
+		return dto.OutputUploadSessionDto{}, writeErr
+	}
+
+	return dto.OutputUploadSessionDto{
+		ID:              session.ID,
+		Status:          string(session.Status),
+		FileName:        session.FileName,
+		Format:          session.Format,
+		Layout:          session.Layout,
+		Encoding:        session.Encoding,
+		Strict:          session.Strict,
+		DuplicatePolicy: session.DuplicatePolicy,
+		TotalBytes:      session.TotalBytes,
+		ReceivedBytes:   session.ReceivedBytes,
+		CreatedAt:       session.CreatedAt,
+		UpdatedAt:       session.UpdatedAt,
+	}, nil
+}
+
+// write appends the chunk after the bytes already acknowledged and returns how
+// many bytes were stored, or -1 when the file could not be written at all.
+// A chunk going past the declared size is discarded.
+func (uc *AppendUploadChunkUseCase) write(session *entity.UploadSession, chunk io.Reader) (int64, error) {
+	file, err := os.OpenFile(session.FilePath, os.O_WRONLY, 0)
+	if err != nil {
+		return -1, err
+	}
+	defer file.Close()
+
+	// Anything past the recorded offset was never acknowledged to the client.
+	if err := file.Truncate(session.ReceivedBytes); err != nil {
+		return -1, err
+	}
+	if _, err := file.Seek(session.ReceivedBytes, io.SeekStart); err != nil {
+		return -1, err
+	}
+
+	if remaining := session.Remaining(); remaining >= 0 {
+		// One extra byte is enough to tell the chunk is too large.
+		chunk = io.LimitReader(chunk, remaining+1)
+	}
+
+	written, copyErr := io.Copy(file, chunk)
+	if remaining := session.Remaining(); remaining >= 0 && written > remaining {
+		file.Truncate(session.ReceivedBytes)
+		return 0, entity.ErrUploadTooLarge
+	}
+
+	if err := file.Sync(); err != nil {
+		return -1, err
+	}
+
+	return written, copyErr
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"errors"
+	"io"
+	"neoway_test/internal/domain/uploadsession/dto"
+	"neoway_test/internal/domain/uploadsession/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSession(t *testing.T, totalBytes int64) *entity.UploadSession {
+	filePath := filepath.Join(t.TempDir(), "upload-1")
+	os.WriteFile(filePath, nil, 0o600)
+	return entity.NewUploadSession("base_teste.txt", "", filePath, "", "", "", false, "", totalBytes)
+}
+
+func TestAppendUploadChunkUseCase_AppendsInOrder(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	appendUploadChunkUseCase := NewAppendUploadChunkUseCase(mockRepo)
+
+	session := newSession(t, 10)
+	mockRepo.On("GetById", session.ID).Return(session, nil)
+	mockRepo.On("ClaimChunk", session, mock.AnythingOfType("string")).Return(true, nil)
+	mockRepo.On("CommitChunk", session, mock.AnythingOfType("string"), mock.AnythingOfType("int64")).Return(true, nil)
+
+	output, err := appendUploadChunkUseCase.Execute(dto.InputAppendUploadChunkDto{ID: session.ID, Offset: 0, Chunk: strings.NewReader("hello")})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), output.ReceivedBytes)
+
+	output, err = appendUploadChunkUseCase.Execute(dto.InputAppendUploadChunkDto{ID: session.ID, Offset: 5, Chunk: strings.NewReader("world")})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), output.ReceivedBytes)
+
+	content, _ := os.ReadFile(session.FilePath)
+	assert.Equal(t, "helloworld", string(content))
+}
+
+func TestAppendUploadChunkUseCase_RejectsWrongOffset(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	appendUploadChunkUseCase := NewAppendUploadChunkUseCase(mockRepo)
+
+	session := newSession(t, 0)
+	session.Receive(5)
+	mockRepo.On("GetById", session.ID).Return(session, nil)
+
+	_, err := appendUploadChunkUseCase.Execute(dto.InputAppendUploadChunkDto{ID: session.ID, Offset: 0, Chunk: strings.NewReader("hello")})
+
+	assert.ErrorIs(t, err, internalerrors.ErrConflict)
+	mockRepo.AssertNotCalled(t, "ClaimChunk", mock.Anything, mock.Anything)
+}
+
+type brokenReader struct {
+	data string
+}
+
+func (r *brokenReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, io.ErrUnexpectedEOF
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestAppendUploadChunkUseCase_KeepsBytesOfInterruptedChunk(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	appendUploadChunkUseCase := NewAppendUploadChunkUseCase(mockRepo)
+
+	session := newSession(t, 0)
+	mockRepo.On("GetById", session.ID).Return(session, nil)
+	mockRepo.On("ClaimChunk", session, mock.AnythingOfType("string")).Return(true, nil)
+	mockRepo.On("CommitChunk", session, mock.AnythingOfType("string"), mock.AnythingOfType("int64")).Return(true, nil)
+
+	_, err := appendUploadChunkUseCase.Execute(dto.InputAppendUploadChunkDto{ID: session.ID, Offset: 0, Chunk: &brokenReader{data: "hel"}})
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	assert.Equal(t, int64(3), session.ReceivedBytes)
+
+	output, err := appendUploadChunkUseCase.Execute(dto.InputAppendUploadChunkDto{ID: session.ID, Offset: 3, Chunk: strings.NewReader("lo")})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), output.ReceivedBytes)
+
+	content, _ := os.ReadFile(session.FilePath)
+	assert.Equal(t, "hello", string(content))
+}
+
+func TestAppendUploadChunkUseCase_DiscardsOversizedChunk(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	appendUploadChunkUseCase := NewAppendUploadChunkUseCase(mockRepo)
+
+	session := newSession(t, 4)
+	mockRepo.On("GetById", session.ID).Return(session, nil)
+	mockRepo.On("ClaimChunk", session, mock.AnythingOfType("string")).Return(true, nil)
+	mockRepo.On("CommitChunk", session, mock.AnythingOfType("string"), mock.AnythingOfType("int64")).Return(true, nil)
+
+	_, err := appendUploadChunkUseCase.Execute(dto.InputAppendUploadChunkDto{ID: session.ID, Offset: 0, Chunk: strings.NewReader("hello")})
+
+	assert.Equal(t, entity.ErrUploadTooLarge, err)
+	assert.Equal(t, int64(0), session.ReceivedBytes)
+	content, _ := os.ReadFile(session.FilePath)
+	assert.Empty(t, content)
+}
+
+func TestAppendUploadChunkUseCase_SessionBusy(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	appendUploadChunkUseCase := NewAppendUploadChunkUseCase(mockRepo)
+
+	session := newSession(t, 0)
+	mockRepo.On("GetById", session.ID).Return(session, nil)
+	mockRepo.On("ClaimChunk", session, mock.AnythingOfType("string")).Return(false, nil)
+
+	_, err := appendUploadChunkUseCase.Execute(dto.InputAppendUploadChunkDto{ID: session.ID, Chunk: strings.NewReader("hello")})
+
+	assert.ErrorIs(t, err, internalerrors.ErrConflict)
+	content, _ := os.ReadFile(session.FilePath)
+	assert.Empty(t, content)
+	mockRepo.AssertNotCalled(t, "CommitChunk", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAppendUploadChunkUseCase_ClaimTakenOver(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	appendUploadChunkUseCase := NewAppendUploadChunkUseCase(mockRepo)
+
+	session := newSession(t, 0)
+	mockRepo.On("GetById", session.ID).Return(session, nil)
+	mockRepo.On("ClaimChunk", session, mock.AnythingOfType("string")).Return(true, nil)
+	mockRepo.On("CommitChunk", session, mock.AnythingOfType("string"), int64(0)).Return(false, nil)
+
+	_, err := appendUploadChunkUseCase.Execute(dto.InputAppendUploadChunkDto{ID: session.ID, Chunk: strings.NewReader("hello")})
+
+	assert.Equal(t, entity.ErrUploadBusy, err)
+}
+
+func TestAppendUploadChunkUseCase_RepositoryError(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	appendUploadChunkUseCase := NewAppendUploadChunkUseCase(mockRepo)
+
+	mockRepo.On("GetById", "id").Return(nil, errors.New("database error"))
+
+	_, err := appendUploadChunkUseCase.Execute(dto.InputAppendUploadChunkDto{ID: "id", Chunk: strings.NewReader("hello")})
+
+	assert.Equal(t, internalerrors.ErrInternal, err)
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"neoway_test/internal/domain/customer/service"
+	"neoway_test/internal/domain/uploadsession/dto"
+	"neoway_test/internal/domain/uploadsession/entity"
+	"neoway_test/internal/domain/uploadsession/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"os"
+	"path/filepath"
+)
+
+type CreateUploadSessionUseCase struct {
+	repo        repository.UploadSessionRepository
+	fileFormats *service.FileFormatRegistry
+	uploadDir   string
+}
+
+func NewCreateUploadSessionUseCase(repo repository.UploadSessionRepository, fileFormats *service.FileFormatRegistry, uploadDir string) *CreateUploadSessionUseCase {
+	return &CreateUploadSessionUseCase{
+		repo:        repo,
+		fileFormats: fileFormats,
+		uploadDir:   uploadDir,
+	}
+}
+
+// Execute opens a session with an empty file that chunks are appended to. The
+// import options are checked now so a bad format or layout fails before any
+// byte is sent.
+func (uc *CreateUploadSessionUseCase) Execute(input dto.InputCreateUploadSessionDto) (dto.OutputUploadSessionDto, error) {
+	options := service.ParseOptions{
+		Format:      input.Format,
+		ContentType: input.ContentType,
+		FileName:    input.FileName,
+		Layout:      input.Layout,
+		Encoding:    input.Encoding,
+	}
+	if err := uc.fileFormats.ValidateOptions(options); err != nil {
+		return dto.OutputUploadSessionDto{}, err
+	}
+	if err := service.ValidateDuplicatePolicy(input.DuplicatePolicy); err != nil {
+		return dto.OutputUploadSessionDto{}, err
+	}
+
+	if err := os.MkdirAll(uc.uploadDir, 0o755); err != nil {
+		return dto.OutputUploadSessionDto{}, internalerrors.ErrInternal
+	}
+
+	file, err := os.CreateTemp(uc.uploadDir, "upload-*")
+	if err != nil {
+		return dto.OutputUploadSessionDto{}, internalerrors.ErrInternal
+	}
+	file.Close()
+
+	session := entity.NewUploadSession(filepath.Base(input.FileName), input.ContentType, file.Name(), input.Format, input.Layout, input.Encoding, input.Strict, input.DuplicatePolicy, input.TotalBytes)
+
+	if err := uc.repo.Create(session); err != nil {
+		os.Remove(file.Name())
+		return dto.OutputUploadSessionDto{}, internalerrors.ErrInternal
+	}
+
+	return dto.OutputUploadSessionDto{
+		ID:              session.ID,
+		Status:          string(session.Status),
+		FileName:        session.FileName,
+		Format:          session.Format,
+		Layout:          session.Layout,
+		Encoding:        session.Encoding,
+		Strict:          session.Strict,
+		DuplicatePolicy: session.DuplicatePolicy,
+		TotalBytes:      session.TotalBytes,
+		ReceivedBytes:   session.ReceivedBytes,
+		CreatedAt:       session.CreatedAt,
+		UpdatedAt:       session.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"errors"
+	"neoway_test/internal/domain/customer/service"
+	"neoway_test/internal/domain/uploadsession/dto"
+	"neoway_test/internal/domain/uploadsession/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateUploadSessionUseCase_Success(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	createUploadSessionUseCase := NewCreateUploadSessionUseCase(mockRepo, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())), t.TempDir())
+
+	var created *entity.UploadSession
+	mockRepo.On("Create", mock.AnythingOfType("*entity.UploadSession")).Run(func(args mock.Arguments) {
+		created = args.Get(0).(*entity.UploadSession)
+	}).Return(nil)
+
+	output, err := createUploadSessionUseCase.Execute(dto.InputCreateUploadSessionDto{
+		FileName:   "base_teste.txt",
+		TotalBytes: 3 << 30,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, created.ID, output.ID)
+	assert.Equal(t, "open", output.Status)
+	assert.Equal(t, int64(3<<30), output.TotalBytes)
+	assert.Equal(t, int64(0), output.ReceivedBytes)
+
+	info, err := os.Stat(created.FilePath)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), info.Size())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateUploadSessionUseCase_InvalidLayout(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	createUploadSessionUseCase := NewCreateUploadSessionUseCase(mockRepo, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())), t.TempDir())
+
+	_, err := createUploadSessionUseCase.Execute(dto.InputCreateUploadSessionDto{
+		FileName: "base_teste.txt",
+		Layout:   "unknown",
+	})
+
+	assert.ErrorIs(t, err, service.ErrUnknownLayout)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateUploadSessionUseCase_RepositoryError(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	uploadDir := t.TempDir()
+	createUploadSessionUseCase := NewCreateUploadSessionUseCase(mockRepo, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())), uploadDir)
+
+	mockRepo.On("Create", mock.AnythingOfType("*entity.UploadSession")).Return(errors.New("database error"))
+
+	_, err := createUploadSessionUseCase.Execute(dto.InputCreateUploadSessionDto{FileName: "base_teste.txt"})
+
+	assert.Equal(t, internalerrors.ErrInternal, err)
+	entries, _ := os.ReadDir(uploadDir)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"errors"
+	"io/fs"
+	"neoway_test/internal/domain/uploadsession/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"os"
+	"time"
+)
+
+type ExpireUploadSessionsUseCase struct {
+	repo repository.UploadSessionRepository
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// NewExpireUploadSessionsUseCase expires the open sessions that received no
+// chunk for longer than ttl.
+func NewExpireUploadSessionsUseCase(repo repository.UploadSessionRepository, ttl time.Duration) *ExpireUploadSessionsUseCase {
+	return &ExpireUploadSessionsUseCase{repo: repo, ttl: ttl, now: time.Now}
+}
+
+// Execute removes the abandoned sessions with the bytes they received and
+// returns how many. Finalized sessions are left alone, since their file
+// belongs to the import job.
+func (uc *ExpireUploadSessionsUseCase) Execute() (int, error) {
+	sessions, err := uc.repo.DeleteStale(uc.now().Add(-uc.ttl))
+	if err != nil {
+		return 0, internalerrors.ErrInternal
+	}
+
+	var removeErr error
+	for _, session := range sessions {
+		if err := os.Remove(session.FilePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			removeErr = err
+		}
+	}
+	return len(sessions), removeErr
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"errors"
+	"neoway_test/internal/domain/uploadsession/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExpireUploadSessionsUseCase_Success(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	expireUseCase := NewExpireUploadSessionsUseCase(mockRepo, 24*time.Hour)
+	expireUseCase.now = func() time.Time { return time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC) }
+
+	filePath := filepath.Join(t.TempDir(), "upload-1")
+	os.WriteFile(filePath, []byte("hel"), 0o600)
+	stale := entity.NewUploadSession("base_teste.txt", "", filePath, "", "", "", false, "", 5)
+	gone := entity.NewUploadSession("base_teste.txt", "", filepath.Join(t.TempDir(), "upload-2"), "", "", "", false, "", 5)
+	mockRepo.On("DeleteStale", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)).Return([]*entity.UploadSession{stale, gone}, nil)
+
+	expired, err := expireUseCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, expired)
+	assert.NoFileExists(t, filePath)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExpireUploadSessionsUseCase_RepositoryError(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	expireUseCase := NewExpireUploadSessionsUseCase(mockRepo, time.Hour)
+
+	mockRepo.On("DeleteStale", mock.Anything).Return(nil, errors.New("database error"))
+
+	expired, err := expireUseCase.Execute()
+
+	assert.Equal(t, 0, expired)
+	assert.Equal(t, internalerrors.ErrInternal, err)
+}
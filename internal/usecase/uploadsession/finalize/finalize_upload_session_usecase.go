@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	importJobDto "neoway_test/internal/domain/importjob/dto"
+	"neoway_test/internal/domain/uploadsession/dto"
+	"neoway_test/internal/domain/uploadsession/entity"
+	"neoway_test/internal/domain/uploadsession/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	usecaseImportJobCreate "neoway_test/internal/usecase/importjob/create"
+)
+
+type FinalizeUploadSessionUseCase struct {
+	repo                   repository.UploadSessionRepository
+	createImportJobUsecase *usecaseImportJobCreate.CreateImportJobUseCase
+}
+
+func NewFinalizeUploadSessionUseCase(repo repository.UploadSessionRepository, createImportJobUsecase *usecaseImportJobCreate.CreateImportJobUseCase) *FinalizeUploadSessionUseCase {
+	return &FinalizeUploadSessionUseCase{
+		repo:                   repo,
+		createImportJobUsecase: createImportJobUsecase,
+	}
+}
+
+// Execute closes a complete upload and queues an import job for the file it
+// received, as if it had been sent to /bulkCreation in a single request. The
+// session is claimed before the job is created, so concurrent requests queue
+// a single job, and reopened when the job cannot be created.
+func (uc *FinalizeUploadSessionUseCase) Execute(input dto.InputFinalizeUploadSessionDto) (importJobDto.OutputImportJobDto, error) {
+	session, err := uc.repo.GetById(input.ID)
+	if err != nil {
+		return importJobDto.OutputImportJobDto{}, internalerrors.ProcessErrorToReturn(err)
+	}
+
+	if err := session.CheckFinalize(); err != nil {
+		return importJobDto.OutputImportJobDto{}, err
+	}
+
+	claimed, err := uc.repo.ClaimFinalize(session)
+	if err != nil {
+		return importJobDto.OutputImportJobDto{}, internalerrors.ErrInternal
+	}
+	if !claimed {
+		return importJobDto.OutputImportJobDto{}, entity.ErrUploadBusy
+	}
+
+	job, err := uc.createImportJobUsecase.Execute(importJobDto.InputCreateImportJobDto{
+		FileName:        session.FileName,
+		ContentType:     session.ContentType,
+		FilePath:        session.FilePath,
+		Format:          session.Format,
+		Layout:          session.Layout,
+		Encoding:        session.Encoding,
+		Strict:          session.Strict,
+		DuplicatePolicy: session.DuplicatePolicy,
+		Actor:           input.Actor,
+		RequestID:       input.RequestID,
+	})
+	if err != nil {
+		// The session read is still open, so saving it reopens it.
+		uc.repo.Update(session)
+		return importJobDto.OutputImportJobDto{}, err
+	}
+
+	session.Finalize(job.ID)
+	if err := uc.repo.Update(session); err != nil {
+		return importJobDto.OutputImportJobDto{}, internalerrors.ErrInternal
+	}
+
+	return job, nil
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"errors"
+	"neoway_test/internal/domain/customer/service"
+	importJobEntity "neoway_test/internal/domain/importjob/entity"
+	"neoway_test/internal/domain/uploadsession/dto"
+	"neoway_test/internal/domain/uploadsession/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	usecaseImportJobCreate "neoway_test/internal/usecase/importjob/create"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type importJobQueueMock struct {
+	mock.Mock
+}
+
+func (q *importJobQueueMock) Enqueue(jobID string) {
+	q.Called(jobID)
+}
+
+func newFinalizeUploadSessionUseCase(t *testing.T, mockRepo *databaseRepository.UploadSessionRepositoryMock, mockImportJobRepo *databaseRepository.ImportJobRepositoryMock) *FinalizeUploadSessionUseCase {
+	mockQueue := new(importJobQueueMock)
+	mockQueue.On("Enqueue", mock.AnythingOfType("string")).Return()
+	fileFormats := service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry()))
+	createImportJobUseCase := usecaseImportJobCreate.NewCreateImportJobUseCase(mockImportJobRepo, mockQueue, fileFormats, t.TempDir())
+	return NewFinalizeUploadSessionUseCase(mockRepo, createImportJobUseCase)
+}
+
+func TestFinalizeUploadSessionUseCase_QueuesImportJob(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	mockImportJobRepo := new(databaseRepository.ImportJobRepositoryMock)
+	finalizeUploadSessionUseCase := newFinalizeUploadSessionUseCase(t, mockRepo, mockImportJobRepo)
+
+	filePath := filepath.Join(t.TempDir(), "upload-1")
+	os.WriteFile(filePath, []byte("hello"), 0o600)
+	session := entity.NewUploadSession("base_teste.csv", "", filePath, "", "", "", false, "", 5)
+	session.Receive(5)
+
+	var job *importJobEntity.ImportJob
+	mockRepo.On("GetById", session.ID).Return(session, nil)
+	mockRepo.On("ClaimFinalize", session).Return(true, nil)
+	mockRepo.On("Update", session).Return(nil)
+	mockImportJobRepo.On("Create", mock.AnythingOfType("*entity.ImportJob")).Run(func(args mock.Arguments) {
+		job = args.Get(0).(*importJobEntity.ImportJob)
+	}).Return(nil)
+
+	output, err := finalizeUploadSessionUseCase.Execute(dto.InputFinalizeUploadSessionDto{ID: session.ID})
+
+	assert.Nil(t, err)
+	assert.Equal(t, job.ID, output.ID)
+	assert.Equal(t, "csv", output.Format)
+	assert.Equal(t, filePath, job.FilePath)
+	assert.Equal(t, entity.UploadSessionFinalized, session.Status)
+	assert.Equal(t, job.ID, session.ImportJobID)
+}
+
+func TestFinalizeUploadSessionUseCase_IncompleteUpload(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	mockImportJobRepo := new(databaseRepository.ImportJobRepositoryMock)
+	finalizeUploadSessionUseCase := newFinalizeUploadSessionUseCase(t, mockRepo, mockImportJobRepo)
+
+	session := entity.NewUploadSession("base_teste.txt", "", "/tmp/upload-1", "", "", "", false, "", 5)
+	session.Receive(3)
+	mockRepo.On("GetById", session.ID).Return(session, nil)
+
+	_, err := finalizeUploadSessionUseCase.Execute(dto.InputFinalizeUploadSessionDto{ID: session.ID})
+
+	assert.Equal(t, entity.ErrUploadIncomplete, err)
+	mockImportJobRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestFinalizeUploadSessionUseCase_ClaimedByAnotherRequest(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	mockImportJobRepo := new(databaseRepository.ImportJobRepositoryMock)
+	finalizeUploadSessionUseCase := newFinalizeUploadSessionUseCase(t, mockRepo, mockImportJobRepo)
+
+	session := entity.NewUploadSession("base_teste.txt", "", "/tmp/upload-1", "", "", "", false, "", 5)
+	session.Receive(5)
+	mockRepo.On("GetById", session.ID).Return(session, nil)
+	mockRepo.On("ClaimFinalize", session).Return(false, nil)
+
+	_, err := finalizeUploadSessionUseCase.Execute(dto.InputFinalizeUploadSessionDto{ID: session.ID})
+
+	assert.ErrorIs(t, err, internalerrors.ErrConflict)
+	mockImportJobRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestFinalizeUploadSessionUseCase_ReopensWhenJobFails(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	mockImportJobRepo := new(databaseRepository.ImportJobRepositoryMock)
+	finalizeUploadSessionUseCase := newFinalizeUploadSessionUseCase(t, mockRepo, mockImportJobRepo)
+
+	filePath := filepath.Join(t.TempDir(), "upload-1")
+	os.WriteFile(filePath, []byte("hello"), 0o600)
+	session := entity.NewUploadSession("base_teste.csv", "", filePath, "", "", "", false, "", 5)
+	session.Receive(5)
+	mockRepo.On("GetById", session.ID).Return(session, nil)
+	mockRepo.On("ClaimFinalize", session).Return(true, nil)
+	mockRepo.On("Update", mock.MatchedBy(func(updated *entity.UploadSession) bool {
+		return updated.Status == entity.UploadSessionOpen
+	})).Return(nil).Once()
+	mockImportJobRepo.On("Create", mock.AnythingOfType("*entity.ImportJob")).Return(errors.New("database error"))
+
+	_, err := finalizeUploadSessionUseCase.Execute(dto.InputFinalizeUploadSessionDto{ID: session.ID})
+
+	assert.Equal(t, internalerrors.ErrInternal, err)
+	assert.FileExists(t, filePath)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"neoway_test/internal/domain/uploadsession/dto"
+	"neoway_test/internal/domain/uploadsession/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+)
+
+type GetUploadSessionByIdUseCase struct {
+	repo repository.UploadSessionRepository
+}
+
+func NewGetUploadSessionByIdUseCase(repo repository.UploadSessionRepository) *GetUploadSessionByIdUseCase {
+	return &GetUploadSessionByIdUseCase{repo: repo}
+}
+
+func (uc *GetUploadSessionByIdUseCase) Execute(input dto.InputGetUploadSessionByIdDto) (*dto.OutputUploadSessionDto, error) {
+	session, err := uc.repo.GetById(input.ID)
+
+	if err != nil {
+		return nil, internalerrors.ProcessErrorToReturn(err)
+	}
+
+	return &dto.OutputUploadSessionDto{
+		ID:              session.ID,
+		Status:          string(session.Status),
+		FileName:        session.FileName,
+		Format:          session.Format,
+		Layout:          session.Layout,
+		Encoding:        session.Encoding,
+		Strict:          session.Strict,
+		DuplicatePolicy: session.DuplicatePolicy,
+		TotalBytes:      session.TotalBytes,
+		ReceivedBytes:   session.ReceivedBytes,
+		ImportJobID:     session.ImportJobID,
+		CreatedAt:       session.CreatedAt,
+		UpdatedAt:       session.UpdatedAt,
+	}, nil
+}
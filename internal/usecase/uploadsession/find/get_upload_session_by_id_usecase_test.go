@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"errors"
+	"neoway_test/internal/domain/uploadsession/dto"
+	"neoway_test/internal/domain/uploadsession/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	internalerrors "neoway_test/internal/internal-errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestGetUploadSessionByIdUseCase_Success(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	getUploadSessionByIdUseCase := NewGetUploadSessionByIdUseCase(mockRepo)
+
+	session := entity.NewUploadSession("base_teste.txt", "", "/tmp/upload-1", "", "", "", false, "", 100)
+	session.Receive(40)
+	mockRepo.On("GetById", session.ID).Return(session, nil)
+
+	output, err := getUploadSessionByIdUseCase.Execute(dto.InputGetUploadSessionByIdDto{ID: session.ID})
+
+	assert.Nil(t, err)
+	assert.Equal(t, session.ID, output.ID)
+	assert.Equal(t, int64(40), output.ReceivedBytes)
+	assert.Equal(t, int64(100), output.TotalBytes)
+}
+
+func TestGetUploadSessionByIdUseCase_NotFound(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	getUploadSessionByIdUseCase := NewGetUploadSessionByIdUseCase(mockRepo)
+
+	mockRepo.On("GetById", "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	output, err := getUploadSessionByIdUseCase.Execute(dto.InputGetUploadSessionByIdDto{ID: "missing"})
+
+	assert.Nil(t, output)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
+
+func TestGetUploadSessionByIdUseCase_RepositoryError(t *testing.T) {
+	mockRepo := new(databaseRepository.UploadSessionRepositoryMock)
+	getUploadSessionByIdUseCase := NewGetUploadSessionByIdUseCase(mockRepo)
+
+	mockRepo.On("GetById", "id").Return(nil, errors.New("database error"))
+
+	_, err := getUploadSessionByIdUseCase.Execute(dto.InputGetUploadSessionByIdDto{ID: "id"})
+
+	assert.Equal(t, internalerrors.ErrInternal, err)
+}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	customerDto "neoway_test/internal/domain/customer/dto"
+	"neoway_test/internal/domain/customer/service"
+	"neoway_test/internal/domain/watchedfile/dto"
+	"neoway_test/internal/domain/watchedfile/entity"
+	"neoway_test/internal/domain/watchedfile/repository"
+	usecaseCreate "neoway_test/internal/usecase/customer/create"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DoneFolder   = "done"
+	FailedFolder = "failed"
+	ReportSuffix = ".report.json"
+	// WatchFolderActor is the actor of watched folder imports in the audit
+	// log.
+	WatchFolderActor = "watch-folder"
+
+	// duplicateStatus marks, in the sidecar report only, a file whose content
+	// was already imported.
+	duplicateStatus       = "duplicate"
+	interruptedFileReason = "import interrupted: no progress reported for too long"
+
+	// FileLease is how long a file may go without a heartbeat before it is
+	// taken as abandoned and retried.
+	FileLease = 10 * time.Minute
+
+	// heartbeatInterval is how often a file being imported renews its lease,
+	// whether or not a batch was written in the meantime.
+	heartbeatInterval = FileLease / 10
+)
+
+type IngestWatchFolderUseCase struct {
+	repo                       repository.WatchedFileRepository
+	createCustomersBulkUsecase *usecaseCreate.CreateCustomerBulkUseCase
+	dir                        string
+	settle                     time.Duration
+	now                        func() time.Time
+	heartbeatInterval          time.Duration
+}
+
+// NewIngestWatchFolderUseCase watches dir, leaving alone files modified less
+// than settle ago since they may still be arriving.
+func NewIngestWatchFolderUseCase(repo repository.WatchedFileRepository, createCustomersBulkUsecase *usecaseCreate.CreateCustomerBulkUseCase, dir string, settle time.Duration) *IngestWatchFolderUseCase {
+	return &IngestWatchFolderUseCase{
+		repo:                       repo,
+		createCustomersBulkUsecase: createCustomersBulkUsecase,
+		dir:                        dir,
+		settle:                     settle,
+		now:                        time.Now,
+		heartbeatInterval:          heartbeatInterval,
+	}
+}
+
+// Prepare creates the done/ and failed/ folders.
+func (uc *IngestWatchFolderUseCase) Prepare() error {
+	for _, folder := range []string{DoneFolder, FailedFolder} {
+		if err := os.MkdirAll(filepath.Join(uc.dir, folder), 0o755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute imports every settled file in the watch folder and moves it, with a
+// sidecar report, to done/ or failed/. Files whose import sent no heartbeat
+// for longer than FileLease are failed first, so they are retried.
+// It stops at the first error that is not the import's own, leaving the
+// remaining files for the next round.
+func (uc *IngestWatchFolderUseCase) Execute() ([]dto.OutputWatchedFileReportDto, error) {
+	if err := uc.repo.FailStale(uc.now().Add(-FileLease), interruptedFileReason); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(uc.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []dto.OutputWatchedFileReportDto
+	for _, entry := range entries {
+		if !uc.ready(entry) {
+			continue
+		}
+
+		report, err := uc.ingest(entry.Name())
+		if err != nil {
+			return reports, err
+		}
+		if report != nil {
+			reports = append(reports, *report)
+		}
+	}
+
+	return reports, nil
+}
+
+// ready skips folders, hidden files (SFTP clients often upload to a dot file
+// and rename it when done) and files still being written.
+func (uc *IngestWatchFolderUseCase) ready(entry os.DirEntry) bool {
+	if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+		return false
+	}
+
+	info, err := entry.Info()
+	if err != nil {
+		return false
+	}
+	return time.Since(info.ModTime()) >= uc.settle
+}
+
+func (uc *IngestWatchFolderUseCase) ingest(name string) (*dto.OutputWatchedFileReportDto, error) {
+	path := filepath.Join(uc.dir, name)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fileHash, err := service.HashUpload(file)
+	if err != nil {
+		return nil, err
+	}
+
+	watched, err := uc.repo.GetByHash(fileHash)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		watched = entity.NewWatchedFile(name, fileHash)
+		err = uc.repo.Create(watched)
+	case err != nil:
+	case watched.Status == entity.WatchedFileImported:
+		report := newReport(name, watched)
+		report.Status = duplicateStatus
+		report.FinishedAt = time.Now()
+		return uc.move(path, DoneFolder, watched.ID, report)
+	case watched.Status == entity.WatchedFileProcessing:
+		// Another instance is importing the same content.
+		return nil, nil
+	default:
+		watched.Retry(name)
+		var claimed bool
+		claimed, err = uc.repo.Retry(watched)
+		if err == nil && !claimed {
+			// Another instance is retrying the same content.
+			return nil, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	stop := uc.keepAlive(name, watched.ID, watched.Attempt)
+	// The watched file ID doubles as the import batch ID of every customer it writes.
+	output, err := uc.createCustomersBulkUsecase.Execute(customerDto.InputCreateCustomerBulkDto{
+		File:     file,
+		FileName: name,
+		BatchID:  watched.ID,
+		FileHash: fileHash,
+		Actor:    WatchFolderActor,
+	})
+	stop()
+
+	folder := DoneFolder
+	if err != nil {
+		watched.Fail(err)
+		folder = FailedFolder
+	} else {
+		watched.Succeed(output)
+	}
+
+	finished, err := uc.repo.Finish(watched)
+	if err != nil {
+		return nil, err
+	}
+	if !finished {
+		// The attempt was taken as abandoned; the file belongs to the run
+		// retrying it now.
+		log.Printf("watch folder %s: attempt %d lost its lease", name, watched.Attempt)
+		return nil, nil
+	}
+
+	return uc.move(path, folder, watched.ID, newReport(name, watched))
+}
+
+// keepAlive renews the lease of the attempt every heartbeatInterval until the
+// returned function is called, so a slow batch or a long run of rejected
+// lines is not taken as a crash.
+func (uc *IngestWatchFolderUseCase) keepAlive(name string, id string, attempt int) func() {
+	ticker := time.NewTicker(uc.heartbeatInterval)
+	done := make(chan struct{})
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := uc.repo.Heartbeat(id, attempt); err != nil {
+					log.Printf("watch folder %s: failed to save heartbeat: %v", name, err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// move writes the sidecar report into folder and moves the file next to it.
+// A name already taken in folder gets the batch ID as prefix.
+func (uc *IngestWatchFolderUseCase) move(path string, folder string, batchID string, report dto.OutputWatchedFileReportDto) (*dto.OutputWatchedFileReportDto, error) {
+	target := filepath.Join(uc.dir, folder, filepath.Base(path))
+	if _, err := os.Stat(target); err == nil {
+		target = filepath.Join(uc.dir, folder, batchID+"_"+filepath.Base(path))
+	}
+
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(target+ReportSuffix, content, 0o644); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(path, target); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func newReport(name string, watched *entity.WatchedFile) dto.OutputWatchedFileReportDto {
+	report := dto.OutputWatchedFileReportDto{
+		BatchID:    watched.ID,
+		FileName:   name,
+		FileHash:   watched.FileHash,
+		Status:     string(watched.Status),
+		Error:      watched.Error,
+		Report:     watched.Report,
+		FinishedAt: time.Now(),
+	}
+	if watched.FinishedAt != nil {
+		report.FinishedAt = *watched.FinishedAt
+	}
+	return report
+}
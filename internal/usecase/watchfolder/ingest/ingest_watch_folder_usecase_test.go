@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	customerDto "neoway_test/internal/domain/customer/dto"
+	customerEntity "neoway_test/internal/domain/customer/entity"
+	"neoway_test/internal/domain/customer/service"
+	"neoway_test/internal/domain/watchedfile/dto"
+	"neoway_test/internal/domain/watchedfile/entity"
+	databaseRepository "neoway_test/internal/infrastructure/database/repository"
+	usecaseCreate "neoway_test/internal/usecase/customer/create"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const fileContent = `CPF                PRIVATE     INCOMPLETO  DATA DA ÚLTIMA COMPRA TICKET MÉDIO          TICKET DA ÚLTIMA COMPRA LOJA MAIS FREQUÊNTE LOJA DA ÚLTIMA COMPRA
+026.987.379-13     0           0           2011-01-20            159,31                159,31                  79.379.491/0001-83  79.379.491/0001-83`
+
+func newIngestUseCase(t *testing.T, settle time.Duration) (*IngestWatchFolderUseCase, *databaseRepository.WatchedFileRepositoryMock, *databaseRepository.CustomerRepositoryMock, string) {
+	dir := t.TempDir()
+	mockWatchedRepo := new(databaseRepository.WatchedFileRepositoryMock)
+	mockCustomerRepo := new(databaseRepository.CustomerRepositoryMock)
+	bulkUseCase := usecaseCreate.NewCreateCustomersBulkUseCase(mockCustomerRepo, service.NewFileFormatRegistry(service.NewParseTxtFileService(service.NewLayoutRegistry())))
+
+	ingestUseCase := NewIngestWatchFolderUseCase(mockWatchedRepo, bulkUseCase, dir, settle)
+	assert.Nil(t, ingestUseCase.Prepare())
+	assert.DirExists(t, filepath.Join(dir, DoneFolder))
+	assert.DirExists(t, filepath.Join(dir, FailedFolder))
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ingestUseCase.now = func() time.Time { return now }
+	mockWatchedRepo.On("FailStale", now.Add(-FileLease), interruptedFileReason).Return(nil)
+	mockWatchedRepo.On("Heartbeat", mock.AnythingOfType("string"), mock.AnythingOfType("int")).Return(nil).Maybe()
+
+	return ingestUseCase, mockWatchedRepo, mockCustomerRepo, dir
+}
+
+func readReport(t *testing.T, path string) dto.OutputWatchedFileReportDto {
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+
+	var report dto.OutputWatchedFileReportDto
+	assert.Nil(t, json.Unmarshal(content, &report))
+	return report
+}
+
+func TestIngestWatchFolderUseCase_ImportsNewFile(t *testing.T) {
+	ingestUseCase, mockWatchedRepo, mockCustomerRepo, dir := newIngestUseCase(t, 0)
+	os.WriteFile(filepath.Join(dir, "base.txt"), []byte(fileContent), 0o644)
+
+	mockWatchedRepo.On("GetByHash", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	mockWatchedRepo.On("Create", mock.AnythingOfType("*entity.WatchedFile")).Return(nil)
+	mockWatchedRepo.On("Finish", mock.AnythingOfType("*entity.WatchedFile")).Return(true, nil)
+	var imported []*customerEntity.Customer
+	mockCustomerRepo.On("WithAudit", customerEntity.AuditInfo{Actor: WatchFolderActor}).Return(mockCustomerRepo)
+	mockCustomerRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Run(func(args mock.Arguments) {
+		imported = args.Get(0).([]*customerEntity.Customer)
+	}).Return(1, 0, 0, nil)
+
+	reports, err := ingestUseCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, string(entity.WatchedFileImported), reports[0].Status)
+	assert.Equal(t, 1, reports[0].Report.Accepted)
+	assert.Len(t, imported, 1)
+	assert.Equal(t, reports[0].BatchID, imported[0].ImportBatchID)
+	assert.Equal(t, reports[0].FileHash, imported[0].SourceFileHash)
+
+	assert.NoFileExists(t, filepath.Join(dir, "base.txt"))
+	assert.FileExists(t, filepath.Join(dir, DoneFolder, "base.txt"))
+	sidecar := readReport(t, filepath.Join(dir, DoneFolder, "base.txt"+ReportSuffix))
+	assert.Equal(t, reports[0].BatchID, sidecar.BatchID)
+	assert.Equal(t, reports[0].FileHash, sidecar.FileHash)
+	assert.Equal(t, reports[0].Report, sidecar.Report)
+}
+
+func TestIngestWatchFolderUseCase_SkipsImportedContent(t *testing.T) {
+	ingestUseCase, mockWatchedRepo, mockCustomerRepo, dir := newIngestUseCase(t, 0)
+	os.WriteFile(filepath.Join(dir, "base_copia.txt"), []byte(fileContent), 0o644)
+
+	previous := entity.NewWatchedFile("base.txt", "abc123")
+	previous.Succeed(customerDto.OutputCreateCustomerBulkDto{Accepted: 1})
+	mockWatchedRepo.On("GetByHash", mock.AnythingOfType("string")).Return(previous, nil)
+
+	reports, err := ingestUseCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, duplicateStatus, reports[0].Status)
+	assert.Equal(t, previous.ID, reports[0].BatchID)
+	assert.FileExists(t, filepath.Join(dir, DoneFolder, "base_copia.txt"+ReportSuffix))
+	mockCustomerRepo.AssertNotCalled(t, "UpsertBulk", mock.Anything)
+	mockWatchedRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestIngestWatchFolderUseCase_FailedImport(t *testing.T) {
+	ingestUseCase, mockWatchedRepo, mockCustomerRepo, dir := newIngestUseCase(t, 0)
+	os.WriteFile(filepath.Join(dir, "base.txt"), []byte(fileContent), 0o644)
+
+	previous := entity.NewWatchedFile("base.txt", "abc123")
+	previous.Fail(errors.New("internal server error"))
+	mockWatchedRepo.On("GetByHash", mock.AnythingOfType("string")).Return(previous, nil)
+	mockWatchedRepo.On("Retry", previous).Return(true, nil)
+	mockWatchedRepo.On("Finish", previous).Return(true, nil)
+	mockCustomerRepo.On("WithAudit", mock.Anything).Return(mockCustomerRepo)
+	mockCustomerRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Return(0, 0, 0, errors.New("connection refused"))
+
+	reports, err := ingestUseCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, previous.ID, reports[0].BatchID)
+	assert.Equal(t, string(entity.WatchedFileFailed), reports[0].Status)
+	assert.NotEmpty(t, reports[0].Error)
+	assert.FileExists(t, filepath.Join(dir, FailedFolder, "base.txt"))
+	assert.FileExists(t, filepath.Join(dir, FailedFolder, "base.txt"+ReportSuffix))
+}
+
+func TestIngestWatchFolderUseCase_RetryTakenByAnotherInstance(t *testing.T) {
+	ingestUseCase, mockWatchedRepo, mockCustomerRepo, dir := newIngestUseCase(t, 0)
+	os.WriteFile(filepath.Join(dir, "base.txt"), []byte(fileContent), 0o644)
+
+	previous := entity.NewWatchedFile("base.txt", "abc123")
+	previous.Fail(errors.New("internal server error"))
+	mockWatchedRepo.On("GetByHash", mock.AnythingOfType("string")).Return(previous, nil)
+	mockWatchedRepo.On("Retry", previous).Return(false, nil)
+
+	reports, err := ingestUseCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Empty(t, reports)
+	assert.FileExists(t, filepath.Join(dir, "base.txt"))
+	mockCustomerRepo.AssertNotCalled(t, "UpsertBulk", mock.Anything)
+	mockWatchedRepo.AssertNotCalled(t, "Finish", mock.Anything)
+}
+
+func TestIngestWatchFolderUseCase_RenewsLeaseDuringSlowBatch(t *testing.T) {
+	ingestUseCase, mockWatchedRepo, mockCustomerRepo, dir := newIngestUseCase(t, 0)
+	ingestUseCase.heartbeatInterval = time.Millisecond
+	os.WriteFile(filepath.Join(dir, "base.txt"), []byte(fileContent), 0o644)
+
+	mockWatchedRepo.On("GetByHash", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	mockWatchedRepo.On("Create", mock.AnythingOfType("*entity.WatchedFile")).Return(nil)
+	mockWatchedRepo.On("Finish", mock.AnythingOfType("*entity.WatchedFile")).Return(true, nil)
+	mockCustomerRepo.On("WithAudit", mock.Anything).Return(mockCustomerRepo)
+	mockCustomerRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Run(func(args mock.Arguments) {
+		time.Sleep(20 * time.Millisecond)
+	}).Return(1, 0, 0, nil)
+
+	reports, err := ingestUseCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Len(t, reports, 1)
+	mockWatchedRepo.AssertCalled(t, "Heartbeat", reports[0].BatchID, 1)
+}
+
+func TestIngestWatchFolderUseCase_LeaseLost(t *testing.T) {
+	ingestUseCase, mockWatchedRepo, mockCustomerRepo, dir := newIngestUseCase(t, 0)
+	os.WriteFile(filepath.Join(dir, "base.txt"), []byte(fileContent), 0o644)
+
+	mockWatchedRepo.On("GetByHash", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	mockWatchedRepo.On("Create", mock.AnythingOfType("*entity.WatchedFile")).Return(nil)
+	mockWatchedRepo.On("Finish", mock.AnythingOfType("*entity.WatchedFile")).Return(false, nil)
+	mockCustomerRepo.On("WithAudit", mock.Anything).Return(mockCustomerRepo)
+	mockCustomerRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Return(1, 0, 0, nil)
+
+	reports, err := ingestUseCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Empty(t, reports)
+	assert.FileExists(t, filepath.Join(dir, "base.txt"))
+	assert.NoFileExists(t, filepath.Join(dir, DoneFolder, "base.txt"))
+}
+
+func TestIngestWatchFolderUseCase_NameTakenInDone(t *testing.T) {
+	ingestUseCase, mockWatchedRepo, mockCustomerRepo, dir := newIngestUseCase(t, 0)
+	os.WriteFile(filepath.Join(dir, DoneFolder, "base.txt"), []byte("older"), 0o644)
+	os.WriteFile(filepath.Join(dir, "base.txt"), []byte(fileContent), 0o644)
+
+	mockWatchedRepo.On("GetByHash", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	mockWatchedRepo.On("Create", mock.AnythingOfType("*entity.WatchedFile")).Return(nil)
+	mockWatchedRepo.On("Finish", mock.AnythingOfType("*entity.WatchedFile")).Return(true, nil)
+	mockCustomerRepo.On("WithAudit", mock.Anything).Return(mockCustomerRepo)
+	mockCustomerRepo.On("UpsertBulk", mock.AnythingOfType("[]*entity.Customer")).Return(1, 0, 0, nil)
+
+	reports, err := ingestUseCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Len(t, reports, 1)
+	assert.FileExists(t, filepath.Join(dir, DoneFolder, reports[0].BatchID+"_base.txt"))
+	older, _ := os.ReadFile(filepath.Join(dir, DoneFolder, "base.txt"))
+	assert.Equal(t, "older", string(older))
+}
+
+func TestIngestWatchFolderUseCase_SkipsUnsettledAndHiddenFiles(t *testing.T) {
+	ingestUseCase, mockWatchedRepo, _, dir := newIngestUseCase(t, time.Hour)
+	os.WriteFile(filepath.Join(dir, "base.txt"), []byte(fileContent), 0o644)
+	os.WriteFile(filepath.Join(dir, ".base.txt.part"), []byte(fileContent), 0o644)
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(filepath.Join(dir, ".base.txt.part"), old, old)
+
+	reports, err := ingestUseCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Empty(t, reports)
+	assert.FileExists(t, filepath.Join(dir, "base.txt"))
+	mockWatchedRepo.AssertNotCalled(t, "GetByHash", mock.Anything)
+}
+
+func TestIngestWatchFolderUseCase_RepositoryError(t *testing.T) {
+	ingestUseCase, mockWatchedRepo, _, dir := newIngestUseCase(t, 0)
+	os.WriteFile(filepath.Join(dir, "base.txt"), []byte(fileContent), 0o644)
+
+	mockWatchedRepo.On("GetByHash", mock.AnythingOfType("string")).Return(nil, errors.New("connection refused"))
+
+	reports, err := ingestUseCase.Execute()
+
+	assert.NotNil(t, err)
+	assert.Empty(t, reports)
+	assert.FileExists(t, filepath.Join(dir, "base.txt"))
+}